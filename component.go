@@ -0,0 +1,57 @@
+package ahatconfig
+
+import (
+	"reflect"
+)
+
+// LoadComponent loads the full configuration like InitConfigSafe but only
+// enforces required fields and constraints for the fields and sections tagged
+// component:"<component>". Other fields are loaded on a best-effort basis, so
+// a component of a large application can start even when settings of other
+// components are missing. The configuration is returned without being stored
+// as the shared instance.
+//
+// Example:
+//
+//	type AppConfig struct {
+//	    Billing BillingConfig `toml:"billing" component:"billing"`
+//	    Search  SearchConfig  `toml:"search" component:"search"`
+//	}
+//
+//	cfg, err := ahatconfig.LoadComponent[AppConfig]("myapp", "billing")
+func LoadComponent[T any](appname, component string) (*T, error) {
+	AppName = appname
+
+	cfg, _, err := buildPrefixedConfig(AppName, component, loadFileBase[T])
+	return cfg, err
+}
+
+// validateComponent validates the fields of the struct v tagged with
+// component, including everything nested below them. Untagged sections are
+// searched for tagged fields.
+func validateComponent(v reflect.Value, path, component string) []error {
+	if err := checkDepth(path); err != nil {
+		return []error{err}
+	}
+
+	t := v.Type()
+	var errs []error
+	for i, fieldInfo := range getCachedTypeInfo(t).Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch {
+		case fieldInfo.Component == component:
+			errs = append(errs, validateField(value, t.Field(i), fieldInfo, fieldPath)...)
+		case isNestedStruct(fieldInfo.Type):
+			errs = append(errs, validateComponent(value, fieldPath, component)...)
+		case isStructList(fieldInfo.Type):
+			for j := 0; j < value.Len(); j++ {
+				if elem := derefElem(value.Index(j)); elem.IsValid() {
+					errs = append(errs, validateComponent(elem, indexPath(fieldPath, j), component)...)
+				}
+			}
+		}
+	}
+	return errs
+}
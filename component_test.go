@@ -0,0 +1,48 @@
+package ahatconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadComponent는 LoadComponent가 요청한 컴포넌트의 필드만 검증하고 다른 컴포넌트의 필수 필드는 로드를 막지 않는지 테스트합니다
+func TestLoadComponent(t *testing.T) {
+	type BillingConfig struct {
+		APIKey  string `toml:"api_key" env:"API_KEY" required:"true"`
+		Retries int    `toml:"retries" env:"RETRIES" max:"5"`
+	}
+	type SearchConfig struct {
+		URL string `toml:"url" env:"URL" required:"true"`
+	}
+	type MonolithConfig struct {
+		Name    string        `toml:"name" env:"NAME" required:"true"`
+		Billing BillingConfig `toml:"billing" env:"BILLING" component:"billing"`
+		Search  SearchConfig  `toml:"search" env:"SEARCH" component:"search"`
+		Region  string        `toml:"region" env:"REGION" required:"true" component:"billing"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	t.Setenv("COMPAPP_BILLING_API_KEY", "key")
+	t.Setenv("COMPAPP_REGION", "eu")
+
+	cfg, err := LoadComponent[MonolithConfig]("compapp", "billing")
+	if err != nil {
+		t.Fatalf("expected the missing search and name fields not to block billing, got %v", err)
+	}
+	if cfg.Billing.APIKey != "key" || cfg.Region != "eu" {
+		t.Errorf("expected billing values to be loaded, got %+v", cfg)
+	}
+	if currentInstance() != nil {
+		t.Error("expected LoadComponent not to store the shared instance")
+	}
+
+	if _, err := LoadComponent[MonolithConfig]("compapp", "search"); err == nil || !strings.Contains(err.Error(), "search.url") {
+		t.Errorf("expected a required error for search.url, got %v", err)
+	}
+
+	t.Setenv("COMPAPP_BILLING_RETRIES", "9")
+	if _, err := LoadComponent[MonolithConfig]("compapp", "billing"); err == nil || !strings.Contains(err.Error(), "billing.retries") {
+		t.Errorf("expected a bounds error for billing.retries, got %v", err)
+	}
+}
@@ -1,41 +1,126 @@
 package ahatconfig
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/pelletier/go-toml"
 )
 
 var (
 	instance   interface{}
+	instanceMu sync.RWMutex // guards instance and warnings against watcher reloads
 	once       sync.Once
 	AppName    string
 	configPath string
 )
 
+// currentInstance returns the loaded configuration, or nil.
+func currentInstance() interface{} {
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	return instance
+}
+
+// configSource records how the current instance was built, so reloads and
+// refreshes read the same sources.
+type configSource struct {
+	prefix   string      // prefix of the environment variables
+	loadBase interface{} // func(cfg *T) error populating the base layer
+}
+
+// instanceSource is the source of instance, guarded by instanceMu.
+var instanceSource configSource
+
+// storeInstance replaces the loaded configuration, the problems recorded
+// while building it and the sources it was built from.
+func storeInstance(cfg interface{}, report loadReport, source configSource) {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+	instance = cfg
+	instanceSource = source
+	loadedAt = now()
+	warnings = report.warnings
+	parseErrors = report.parseErrors
+}
+
+// currentSource returns the prefix and base layer the current instance of
+// type T was built from. Without such an instance, these are the ones of
+// LoadConfig.
+func currentSource[T any]() (string, func(cfg *T) error) {
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	if loadBase, ok := instanceSource.loadBase.(func(cfg *T) error); ok {
+		if _, ok := instance.(*T); ok {
+			return instanceSource.prefix, loadBase
+		}
+	}
+	return AppName, loadFileBase[T]
+}
+
 // TypeInfo caches reflection information for performance optimization.
 // It stores pre-computed field metadata to avoid repeated reflection operations.
 type TypeInfo struct {
 	Fields []FieldInfo
+	// Flat is set when no field is a nested struct or struct slice, so the
+	// struct is loaded from the environment in a single pass
+	Flat bool
+
+	// envKeys caches the environment variable names of the fields of a flat
+	// struct by prefix
+	envKeys sync.Map
 }
 
 // FieldInfo contains cached field information extracted from struct tags.
 // This information is computed once and reused for better performance.
 type FieldInfo struct {
 	Name         string       // Field name
+	Key          string       // TOML key used in field paths
 	Type         reflect.Type // Field type
 	EnvTag       string       // Environment variable tag
+	EnvAbs       string       // Exact environment variable name, bypassing prefixes (envabs tag)
 	DefaultValue string       // Default value tag
+	DefaultRefs  bool         // Default references sibling fields, e.g. "{Host}"
 	Required     bool         // Required field flag
+	RequiredEnv  []string     // Environments in which the field is required (requiredenv tag)
 	Secret       bool         // Secret masking flag
+	Optional     bool         // Optional section: validated only when present
+	Transforms   []string     // String transforms applied after loading
+	TransformFns []string     // Registered functions applied to raw values (transformfn tag)
+	Encoding     string       // Encoding of string or []byte values, e.g. "base64"
+	Delim        string       // Delimiter of list values (delim tag), e.g. " " or ";"
+	OneOf        []string     // Allowed values (space-separated oneof tag)
+	Min          string       // Minimum value, length or element count (min tag)
+	Max          string       // Maximum value, length or element count (max tag)
+	MinElems     int          // Struct slice elements always built from defaults (minelems tag); -1 if invalid
+	MinLen       int          // Minimum number of map entries or slice elements, checked even when empty (minlen tag); -1 if invalid
+	Merge        string       // How env vars combine with a struct slice from the file (merge tag)
+	MergeKey     string       // Field matching env elements of a struct slice to file elements (mergekey tag)
+	Inline       bool         // Struct read from one key=value list env var (inline tag)
+	Desc         string       // Human-readable description (desc tag)
+	ErrMsg       string       // Message replacing the validation problems of the field (errmsg tag)
+	Deprecated   string       // Deprecation message, e.g. "use server.addr" (deprecated tag)
+	Format       string       // Format of a struct or map env value, e.g. "toml" (format tag)
+	Component    string       // Component that owns the field or section, e.g. "billing" (component tag)
+	Strict       bool         // Set env var must be non-empty and parse, without fallback (strict tag)
+	Base         int          // Base of integer values, e.g. 8 (base tag); 0 if not set, -1 if invalid
+	Credential   string       // Name of a systemd credential read when the env var is unset (credential tag)
+	Negate       bool         // Bool field set to the opposite of its env var (negate tag)
 }
 
 // typeCache stores cached type information
@@ -49,25 +134,141 @@ func getCachedTypeInfo(t reflect.Type) *TypeInfo {
 
 	typeInfo := &TypeInfo{
 		Fields: make([]FieldInfo, 0, t.NumField()),
+		Flat:   true,
 	}
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		fieldInfo := FieldInfo{
 			Name:         field.Name,
+			Key:          tomlKey(field),
 			Type:         field.Type,
 			EnvTag:       field.Tag.Get("env"),
+			EnvAbs:       field.Tag.Get("envabs"),
 			DefaultValue: field.Tag.Get("default"),
-			Required:     strings.ToLower(field.Tag.Get("required")) == "true",
-			Secret:       strings.ToLower(field.Tag.Get("secret")) == "true",
+			DefaultRefs:  hasDefaultRefs(field.Tag.Get("default"), t),
+			Required:     boolTag(field, "required"),
+			RequiredEnv:  splitTagList(field.Tag.Get("requiredenv")),
+			Secret:       boolTag(field, "secret"),
+			Optional:     boolTag(field, "optional"),
+			Transforms:   splitTagList(field.Tag.Get("transform")),
+			TransformFns: splitTagList(field.Tag.Get("transformfn")),
+			Encoding:     field.Tag.Get("encoding"),
+			Delim:        field.Tag.Get("delim"),
+			OneOf:        strings.Fields(field.Tag.Get("oneof")),
+			Min:          field.Tag.Get("min"),
+			Max:          field.Tag.Get("max"),
+			MinElems:     countTag(field, "minelems"),
+			MinLen:       countTag(field, "minlen"),
+			Merge:        field.Tag.Get("merge"),
+			MergeKey:     field.Tag.Get("mergekey"),
+			Inline:       boolTag(field, "inline"),
+			Desc:         field.Tag.Get("desc"),
+			ErrMsg:       field.Tag.Get("errmsg"),
+			Deprecated:   field.Tag.Get("deprecated"),
+			Format:       field.Tag.Get("format"),
+			Component:    field.Tag.Get("component"),
+			Strict:       boolTag(field, "strict"),
+			Base:         baseTag(field),
+			Credential:   field.Tag.Get("credential"),
+			Negate:       boolTag(field, "negate"),
 		}
 		typeInfo.Fields = append(typeInfo.Fields, fieldInfo)
+		if isNestedStruct(field.Type) || (field.Type.Kind() == reflect.Slice && isStructList(field.Type)) {
+			typeInfo.Flat = false
+		}
 	}
 
 	typeCache.Store(t, typeInfo)
 	return typeInfo
 }
 
+// boolTag reports whether a boolean struct tag is set to a truthy value:
+// true, 1, yes or on, case-insensitively.
+func boolTag(field reflect.StructField, name string) bool {
+	switch strings.ToLower(strings.TrimSpace(field.Tag.Get(name))) {
+	case "true", "1", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// countTag returns the value of a count struct tag such as minlen: 0 if it
+// is not set, -1 if it is not a non-negative integer. checkCountTags reports
+// invalid ones when a load starts.
+func countTag(field reflect.StructField, name string) int {
+	tag := field.Tag.Get(name)
+	if tag == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(tag)
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// splitTagList splits a comma-separated tag value into trimmed, non-empty items.
+func splitTagList(tag string) []string {
+	var items []string
+	for _, item := range strings.Split(tag, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// tomlKey returns the TOML key of a struct field: the name from its toml tag,
+// or the lowercased field name when the tag is absent.
+func tomlKey(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("toml"), ",")[0]
+	if name == "" || name == "-" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
+
+// joinPath appends a key to a dotted field path (e.g. "server" + "host").
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// structElem returns the struct type held by the elements of the slice or
+// array type t, looking through pointer elements such as []*Server.
+func structElem(t reflect.Type) (reflect.Type, bool) {
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem, isNestedStruct(elem)
+}
+
+// isNestedStruct reports whether t is a struct whose fields are loaded one by
+// one. time.Time is a struct too, but it is loaded as a single value.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// isStructList reports whether t is a slice or array of structs or of
+// pointers to structs.
+func isStructList(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+	_, isStruct := structElem(t)
+	return isStruct
+}
+
+// indexPath appends a slice index to a field path (e.g. "users[0]").
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
 // InitConfig initializes the configuration for the given application name.
 // It loads configuration from TOML file or environment variables based on the
 // {APPNAME}_CONFIG_TYPE environment variable.
@@ -77,13 +278,8 @@ func getCachedTypeInfo(t reflect.Type) *TypeInfo {
 //
 //	ahatconfig.InitConfig[MyConfig]("myapp")
 func InitConfig[T any](appname string) {
-	AppName = appname
-
-	once.Do(func() {
-		err := LoadConfig[T]()
-		if err != nil {
-			panic(err)
-		}
+	initConfigOnce[T](func() {
+		AppName = appname
 	})
 }
 
@@ -96,15 +292,54 @@ func InitConfig[T any](appname string) {
 //
 //	ahatconfig.InitConfigWithPath[MyConfig]("myapp", "/custom/path")
 func InitConfigWithPath[T any](appname, path string) {
-	AppName = appname
-	configPath = path
+	initConfigOnce[T](func() {
+		AppName = appname
+		configPath = path
+	})
+}
 
+// initConfigOnce applies the settings of setup and loads the configuration
+// on the first call of InitConfig or InitConfigWithPath, and panics if that
+// fails. Later calls do nothing, not even change AppName, and one for a
+// different config type is logged, since GetConfig for that type would fail.
+func initConfigOnce[T any](setup func()) {
+	loaded := false
 	once.Do(func() {
-		err := LoadConfig[T]()
-		if err != nil {
+		loaded = true
+		setup()
+		if err := LoadConfig[T](); err != nil {
 			panic(err)
 		}
 	})
+	if !loaded {
+		if _, ok := currentInstance().(*T); !ok {
+			log.Printf("InitConfig for %T ignored: the config was already initialized as %T, use InitConfigForce to replace it", (*T)(nil), currentInstance())
+		}
+	}
+}
+
+// InitConfigForce loads the configuration like InitConfigSafe and replaces
+// the current one, even when InitConfig already ran; later InitConfig calls
+// do nothing. When the load fails, the current configuration and AppName are
+// kept. It is meant for tests that initialize several config types in
+// one binary and for applications that reconfigure themselves.
+//
+// Example:
+//
+//	if err := ahatconfig.InitConfigForce[OtherConfig]("otherapp"); err != nil {
+//	    t.Fatal(err)
+//	}
+func InitConfigForce[T any](appname string) error {
+	previous := AppName
+	AppName = appname
+	if err := LoadConfig[T](); err != nil {
+		AppName = previous
+		return err
+	}
+
+	// Keep a later InitConfig from replacing the forced configuration
+	once.Do(func() {})
+	return nil
 }
 
 // InitConfigSafe initializes configuration and returns error instead of panicking.
@@ -137,134 +372,1005 @@ func InitConfigWithPathSafe[T any](appname, path string) error {
 	return LoadConfig[T]()
 }
 
+// InitConfigWithExactFile initializes configuration from exactly the given
+// file, such as a path passed with a --config flag, then applies environment
+// variable overrides. Files ending in .json are read as JSON, anything else
+// as TOML. Unlike LoadConfig, a missing or malformed file is an error.
+//
+// Example:
+//
+//	err := ahatconfig.InitConfigWithExactFile[MyConfig]("myapp", *configFlag)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func InitConfigWithExactFile[T any](appname, file string) error {
+	AppName = appname
+
+	return loadConfig(func(cfg *T) error {
+		return loadExactFile(cfg, file)
+	})
+}
+
+// loadExactFile decodes exactly the config file at path into cfg. Files
+// ending in .json are read as JSON, anything else as TOML. A missing or
+// malformed file is an error.
+func loadExactFile(cfg interface{}, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	if err := decodeConfigFile(osFiles, path, fileFormat(path), cfg); err != nil {
+		return fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// InitConfigFromFS initializes configuration from the file name in fsys,
+// such as a default config embedded with go:embed, then applies environment
+// variable overrides. Files ending in .json are read as JSON, anything else
+// as TOML; TOML includes are read from fsys too. A missing or malformed file
+// is an error.
+//
+// Example:
+//
+//	//go:embed defaults.toml
+//	var defaults embed.FS
+//
+//	err := ahatconfig.InitConfigFromFS[MyConfig]("myapp", defaults, "defaults.toml")
+func InitConfigFromFS[T any](appname string, fsys fs.FS, name string) error {
+	AppName = appname
+
+	return loadConfig(func(cfg *T) error {
+		if err := decodeConfigFile(fsFiles(fsys), name, fileFormat(name), cfg); err != nil {
+			return fmt.Errorf("failed to load config file %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// LoadWithPrefix builds a configuration from defaults and environment
+// variables named after prefix (e.g. "TENANTA" reads TENANTA_SERVER_HOST) and
+// returns it without storing it as the shared instance or changing AppName.
+// It is meant for multi-tenant processes that load one config per tenant.
+// No config file is read.
+//
+// Example:
+//
+//	tenantA, err := ahatconfig.LoadWithPrefix[MyConfig]("TENANTA")
+//	tenantB, err := ahatconfig.LoadWithPrefix[MyConfig]("TENANTB")
+func LoadWithPrefix[T any](prefix string) (*T, error) {
+	cfg, _, err := buildPrefixedConfig(strings.TrimSuffix(prefix, "_"), "", func(cfg *T) error {
+		return nil
+	})
+	return cfg, err
+}
+
+// fileFormat returns the format of a config file from its extension: "json"
+// for .json files and "toml" otherwise.
+func fileFormat(name string) string {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return "json"
+	}
+	return "toml"
+}
+
 // LoadConfig loads configuration from TOML file first, then overrides with environment variables.
 // Environment variables have higher priority and will override TOML values.
 // This provides a hybrid approach where TOML serves as defaults and env vars as overrides.
 func LoadConfig[T any]() error {
-	var err error
-	cfg := new(T)
+	return loadConfig(loadFileBase[T])
+}
+
+// loadFileBase is the base layer of LoadConfig: the config file selected by
+// {APPNAME}_CONFIG_TYPE, if it exists. CONFIG_TYPE=env skips the file and
+// any other unrecognized value is an error. {APPNAME}_CONFIG_FILE overrides
+// the search: exactly that file is loaded, and a missing or malformed file is
+// an error.
+func loadFileBase[T any](cfg *T) error {
+	if file := strings.TrimSpace(buildGetenv(configFileEnvKey())); file != "" {
+		return loadExactFile(cfg, file)
+	}
 
-	// First, try to load from TOML file (if it exists)
-	tomlErr := loadConfigFile[T](cfg)
-	if tomlErr != nil {
-		log.Printf("TOML config load failed (this is OK if file doesn't exist): %v", tomlErr)
+	format := configType(buildLookup)
+	switch format {
+	case "env":
+		return nil
+	case "toml", "json":
+	default:
+		return fmt.Errorf("unknown config type '%s', expected toml, json or env", format)
+	}
+
+	// First, try to load from the config file (if it exists)
+	fileErr := loadConfigFileAs(cfg, format)
+	if fileErr != nil {
+		log.Printf("%s config load failed (this is OK if file doesn't exist): %v", strings.ToUpper(format), fileErr)
 		// Continue with empty config - environment variables will populate it
 	}
+	return nil
+}
+
+// configFileEnvKey returns the name of the {APPNAME}_CONFIG_FILE variable.
+func configFileEnvKey() string {
+	return strings.ReplaceAll(strings.ToUpper(AppName), "-", "_") + "_CONFIG_FILE"
+}
+
+// configType returns the lowercased value of {APPNAME}_CONFIG_TYPE looked up
+// with lookup, or "toml" when it is not set.
+func configType(lookup envLookup) string {
+	key := strings.ReplaceAll(strings.ToUpper(AppName), "-", "_") + "_CONFIG_TYPE"
+	value, _ := lookup(key)
+	if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+		return value
+	}
+	return "toml"
+}
+
+// loadConfig builds a new config with buildConfig and stores it as the
+// current instance.
+func loadConfig[T any](loadBase func(cfg *T) error) error {
+	cfg, report, err := buildConfig(loadBase)
+	if err != nil {
+		return err
+	}
+
+	storeInstance(cfg, report, configSource{prefix: AppName, loadBase: loadBase})
+	return nil
+}
+
+// loadReport holds the problems that were tolerated while building a config.
+type loadReport struct {
+	warnings        []error  // validation problems in Warn mode
+	parseErrors     []error  // values skipped in SkipAndCount mode
+	insecureSources []error  // secret fields set in the config file
+	deprecated      []string // deprecated fields that are set
+	envNames        []string // environment variables in the snapshot of the load
+}
+
+var (
+	// buildMu serializes builds, which collect skipped parse errors in
+	// skippedParseErrors and env var problems of strict fields in
+	// strictEnvErrors.
+	buildMu            sync.Mutex
+	skippedParseErrors []error
+	strictEnvErrors    []error
+)
+
+// buildConfig builds a new config from the base layer populated by loadBase,
+// overrides it with environment variables and validates it. An error from
+// loadBase aborts the load. In Warn mode validation problems are reported as
+// warnings instead of an error.
+func buildConfig[T any](loadBase func(cfg *T) error) (*T, loadReport, error) {
+	return buildPrefixedConfig(AppName, "", loadBase)
+}
+
+// buildPrefixedConfig is buildConfig with environment variables named after
+// prefix instead of AppName. When component is not empty, only the fields of
+// that component are validated.
+func buildPrefixedConfig[T any](prefix, component string, loadBase func(cfg *T) error) (*T, loadReport, error) {
+	cfg := new(T)
+	report, err := buildInto(cfg, prefix, component, takeEnvSnapshot(prefix), func() error {
+		return loadBase(cfg)
+	})
+	if err != nil {
+		return nil, report, err
+	}
+	return cfg, report, nil
+}
+
+// buildInto does the work of buildPrefixedConfig on cfg, a pointer to a new
+// struct, whose base layer is populated by loadBase. Every environment
+// variable under prefix is read from env, the snapshot taken when the load
+// started. A nil env builds the config without the environment layer.
+func buildInto(cfg interface{}, prefix, component string, env *envSnapshot, loadBase func() error) (loadReport, error) {
+	buildMu.Lock()
+	defer buildMu.Unlock()
+	buildEnv = env
+	defer func() { buildEnv = nil }()
+	skippedParseErrors = nil
+	strictEnvErrors = nil
+	secretSourceErrors = nil
+	fileKeys = nil
+	if conflictPolicy == ErrorOnConflict {
+		fileKeys = map[string]bool{}
+	}
+
+	var err error
+	var report loadReport
+
+	if err = checkRuntimeDefaults(reflect.TypeOf(cfg).Elem()); err != nil {
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
+
+	if err = checkCountTags(reflect.TypeOf(cfg).Elem(), "", map[reflect.Type]bool{}); err != nil {
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
+
+	if err = loadBase(); err != nil {
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
+
+	// Keep the values of the file to find the ones env vars change
+	var base []PathValue
+	if conflictPolicy == ErrorOnConflict {
+		base = appendValues(nil, reflect.ValueOf(cfg).Elem(), "", false)
+	}
 
 	// Then, override with environment variables (higher priority)
 	// Don't fail if env loading has issues - TOML values can serve as fallback
-	if envErr := loadConfigEnv[T](cfg); envErr != nil {
-		log.Printf("Environment variable loading failed (this is OK if no env vars are set): %v", envErr)
-		// Continue with TOML values only
+	if env != nil {
+		if envErr := loadPrefixedEnv(env.lookup, cfg, prefix); envErr != nil {
+			log.Printf("Environment variable loading failed (this is OK if no env vars are set): %v", envErr)
+			// Continue with TOML values only
+		}
+	}
+	// Strict fields never fall back to the TOML value or default
+	if len(strictEnvErrors) > 0 {
+		err = &ValidationError{Problems: strictEnvErrors}
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
+
+	if conflicts := envConflicts(cfg, base); len(conflicts) > 0 {
+		err = &ValidationError{Problems: conflicts}
+		log.Printf("Config load failed: %s", err)
+		return report, err
 	}
 
 	v := reflect.ValueOf(cfg)
-	err = checkRequiredField(v)
+	err = resolveDefaultRefs(v, "")
 	if err != nil {
 		log.Printf("Config load failed: %s", err)
-		return err
+		return report, err
 	}
 
-	instance = cfg
+	// Secret references are resolved after all sources are loaded
+	err = resolveSecrets(v, "")
+	if err != nil {
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
 
-	return nil
+	err = applyTransforms(v, "")
+	if err != nil {
+		log.Printf("Config load failed: %s", err)
+		return report, err
+	}
+
+	var errs []error
+	if component == "" {
+		errs = validateFields(v, "")
+	} else {
+		errs = validateComponent(v.Elem(), "", component)
+	}
+	if secretSourcePolicy == DisallowFile {
+		errs = append(errs, secretSourceErrors...)
+	}
+	if len(errs) > 0 {
+		if validationMode != Warn {
+			err = &ValidationError{Problems: errs}
+			log.Printf("Config load failed: %s", err)
+			return report, err
+		}
+		for _, err := range errs {
+			log.Printf("Config validation warning: %s", err)
+		}
+	}
+
+	report.warnings = errs
+	report.parseErrors = skippedParseErrors
+	report.insecureSources = secretSourceErrors
+	report.deprecated = deprecatedFields(v.Elem(), "")
+	if env != nil {
+		report.envNames = env.names()
+	}
+	for _, message := range report.deprecated {
+		log.Printf("Config deprecation warning: %s", message)
+	}
+	return report, nil
 }
 
-func loadConfigFile[T any](cfg *T) error {
-	var tomlPath string
+// skipParseError records err and reports whether loading should continue
+// without the field, which is the case in SkipAndCount mode.
+func skipParseError(err error) bool {
+	if parseErrorMode != SkipAndCount {
+		return false
+	}
+	log.Printf("Skipping field that failed to parse: %v", err)
+	skippedParseErrors = append(skippedParseErrors, err)
+	return true
+}
+
+// workingDir and executablePath locate the directories searched for the
+// config file. They are variables so tests can control the search.
+var (
+	workingDir     = os.Getwd
+	executablePath = os.Executable
+)
+
+// configFilePath returns the path of the config file with the given name
+// (e.g. "myapp.toml"): next to configPath when it is set, else in the
+// working directory, falling back to the executable's directory when the
+// file is not in the working directory. For an executable started through a
+// symlink, the directory of the real binary is searched before the directory
+// of the link. The returned file may not exist.
+func configFilePath(filename string) (string, error) {
+	if configPath != "" {
+		return filepath.Join(filepath.Dir(configPath), filename), nil
+	}
 
-	if configPath == "" {
-		// First try current working directory
-		wd, err := os.Getwd()
+	// First try current working directory
+	wd, err := workingDir()
+	if err != nil {
+		log.Printf("Error getting working directory: %v", err)
+		return "", err
+	}
+	tomlPath := filepath.Join(wd, filename)
+
+	// If not found in current directory, try executable directory
+	if _, err := os.Stat(tomlPath); os.IsNotExist(err) {
+		exePath, err := executablePath()
 		if err != nil {
-			log.Printf("Error getting working directory: %v", err)
-			return err
+			// Some sandboxes cannot resolve the executable; keep the working directory path
+			log.Printf("Error getting executable path, skipping executable directory: %v", err)
+			return tomlPath, nil
 		}
-		tomlPath = filepath.Join(wd, AppName+".toml")
-		// If not found in current directory, try executable directory
-		if _, err := os.Stat(tomlPath); os.IsNotExist(err) {
-			exePath, err := os.Executable()
-			if err != nil {
-				log.Printf("Error getting executable path: %v", err)
-				return err
+		tomlPath = filepath.Join(filepath.Dir(exePath), filename)
+
+		// When launched through a symlink, prefer the directory of the real binary
+		if realPath, err := filepath.EvalSymlinks(exePath); err == nil && realPath != exePath {
+			realTomlPath := filepath.Join(filepath.Dir(realPath), filename)
+			if _, err := os.Stat(realTomlPath); err == nil {
+				tomlPath = realTomlPath
 			}
-			exeDir := filepath.Dir(exePath)
-			tomlPath = filepath.Join(exeDir, AppName+".toml")
 		}
-	} else {
-		dirPath := filepath.Dir(configPath)
-		tomlPath = filepath.Join(dirPath, AppName+".toml")
 	}
+	return tomlPath, nil
+}
 
-	// Check if TOML file exists
-	if _, err := os.Stat(tomlPath); os.IsNotExist(err) {
-		// TOML file doesn't exist - this is OK, we'll use env vars only
+func loadConfigFile[T any](cfg *T) error {
+	return loadConfigFileAs(cfg, "toml")
+}
+
+// loadConfigFileAs loads {AppName}.{format} into cfg, where format is "toml"
+// or "json". A missing file is not an error.
+func loadConfigFileAs[T any](cfg *T, format string) error {
+	filePath, err := configFilePath(AppName + "." + format)
+	if err != nil {
+		return err
+	}
+
+	// Check if the file exists
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		// File doesn't exist - this is OK, we'll use env vars only
 		return nil
 	}
 
-	tree, err := toml.LoadFile(tomlPath)
+	err = decodeConfigFile(osFiles, filePath, format, cfg)
 	if err != nil {
-		log.Printf("TOML file exists but failed to load: %v", err)
+		log.Printf("Failed to load %s file %s: %v", strings.ToUpper(format), filePath, err)
 		return err
 	}
+	return nil
+}
 
-	err = tree.Unmarshal(cfg)
+// decodeConfigFile reads the config file at path in the given format into
+// cfg, reading files from src. TOML files may include other files, see
+// loadTOMLFile.
+func decodeConfigFile(src fileSource, path, format string, cfg interface{}) error {
+	if format == "toml" {
+		tree, err := loadTOMLFile(src, path)
+		if err != nil {
+			return err
+		}
+		return decodeTree(tree, cfg)
+	}
+
+	data, err := src.readFile(path)
 	if err != nil {
-		log.Printf("Failed to unmarshal TOML: %v", err)
 		return err
 	}
+	return decodeDocument(data, format, cfg)
+}
+
+// decodeDocument decodes a configuration document in the given format
+// ("toml" or "json") into cfg, which must be a pointer to a struct.
+// JSON documents use the same keys as TOML (the toml tags).
+func decodeDocument(data []byte, format string, cfg interface{}) error {
+	var tree *toml.Tree
+	var err error
+
+	switch format {
+	case "toml":
+		tree, err = loadTOML(data)
+	case "json":
+		tree, err = jsonToTree(data, reflect.TypeOf(cfg).Elem())
+	default:
+		return fmt.Errorf("unsupported config format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return decodeTree(tree, cfg)
+}
+
+// decodeTree unmarshals a parsed TOML tree into cfg and post-processes the
+// values read from the document.
+func decodeTree(tree *toml.Tree, cfg interface{}) error {
+	if err := prepareTree(tree, reflect.TypeOf(cfg).Elem(), true); err != nil {
+		return err
+	}
+
+	if err := unmarshalTree(tree, cfg); err != nil {
+		return err
+	}
+
+	// Runtime defaults fill the keys the document leaves out
+	if err := applyTreeDefaults(tree, reflect.ValueOf(cfg)); err != nil {
+		return err
+	}
+
+	// go-toml silently zero-fills fixed-size arrays given too few elements
+	if err := checkTOMLArrayLengths(tree, reflect.TypeOf(cfg).Elem(), ""); err != nil {
+		return err
+	}
+
+	// Keep keys that match no field in toml:",remain" maps
+	captureRemainingKeys(tree, reflect.ValueOf(cfg))
+
+	// Expand ${VAR} references in the values read from the document
+	interpolateEnv(reflect.ValueOf(cfg))
+
+	return decodeEncodedFields(reflect.ValueOf(cfg), "")
+}
+
+// prepareTree rewrites a parsed document in place so that go-toml can
+// decode it into type t, for config files and for environment variables
+// holding documents alike. For config files (fromFile), it also records the
+// secrets and the keys the document sets, before their values are rewritten.
+func prepareTree(tree *toml.Tree, t reflect.Type, fromFile bool) error {
+	// Move the keys go-toml matches to fields, e.g. Password or TOKEN, and
+	// kebab-case or snake_case keys to the keys the walkers below look up
+	renameTOMLKeys(tree, t)
+
+	if fromFile {
+		// Secrets written in the document break DisallowFile
+		checkSecretSources(tree, t, "")
+
+		// Keys written in the document are compared with env vars
+		if fileKeys != nil {
+			recordFileKeys(tree, t, "")
+		}
+	}
+
+	// Registered transform functions rewrite raw string values
+	if err := convertTOMLTransformFns(tree, t, ""); err != nil {
+		return err
+	}
+
+	// go-toml cannot decode local dates and times into time.Time or string fields
+	convertTOMLDateTimes(tree, t)
+
+	// go-toml cannot decode strings into []byte fields
+	convertTOMLByteStrings(tree, t)
+
+	// Strings assigned to fields with a base tag are integers in that base
+	return convertTOMLBaseInts(tree, t, "")
+}
+
+// jsonToTree converts a JSON document into a TOML tree so that it can be
+// decoded with the toml tags of the target type t.
+func jsonToTree(data []byte, t reflect.Type) (*toml.Tree, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return toml.TreeFromMap(jsonToTOMLValue(doc, t).(map[string]interface{}))
+}
+
+// jsonToTOMLValue converts a decoded JSON value into the form go-toml expects
+// for a field of type t. go-toml does not convert between number types, so
+// JSON numbers become int64 or float64 depending on the target field kind.
+func jsonToTOMLValue(v interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		if t != nil && (t.Kind() == reflect.Float64 || t.Kind() == reflect.Float32) {
+			f, _ := val.Float64()
+			return f
+		}
+		if n, err := val.Int64(); err == nil {
+			return n
+		}
+		f, _ := val.Float64()
+		return f
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		for i, elem := range val {
+			val[i] = jsonToTOMLValue(elem, elemType)
+		}
+		return val
+	case map[string]interface{}:
+		for key, elem := range val {
+			val[key] = jsonToTOMLValue(elem, jsonFieldType(t, key))
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// jsonFieldType returns the type of the value stored under key in a value of
+// type t: the matching struct field (by TOML key) or the map element type.
+func jsonFieldType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+			if strings.EqualFold(fieldInfo.Key, key) {
+				return fieldInfo.Type
+			}
+		}
+	}
 	return nil
 }
 
-func checkRequiredField(v reflect.Value) error {
-	// 포인터면 구조체로 접근
+// checkTOMLArrayLengths reports an error when a TOML array assigned to a
+// fixed-size array field does not have exactly as many elements as the field.
+// It descends into tables and arrays of tables.
+func checkTOMLArrayLengths(tree *toml.Tree, t reflect.Type, path string) error {
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		raw := tree.GetPath([]string{fieldInfo.Key})
+		if raw == nil {
+			continue
+		}
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch fieldInfo.Type.Kind() {
+		case reflect.Array:
+			if elems, ok := raw.([]interface{}); ok && len(elems) != fieldInfo.Type.Len() {
+				return fmt.Errorf("field %s expects %d elements, got %d", fieldPath, fieldInfo.Type.Len(), len(elems))
+			}
+		case reflect.Struct:
+			if sub, ok := raw.(*toml.Tree); ok {
+				if err := checkTOMLArrayLengths(sub, fieldInfo.Type, fieldPath); err != nil {
+					return err
+				}
+			}
+		case reflect.Slice:
+			subs, ok := raw.([]*toml.Tree)
+			elemType, isStruct := structElem(fieldInfo.Type)
+			if !ok || !isStruct {
+				continue
+			}
+			for i, sub := range subs {
+				if err := checkTOMLArrayLengths(sub, elemType, indexPath(fieldPath, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// convertTOMLByteStrings replaces string values assigned to []byte fields
+// with their bytes, which go-toml can decode. It descends into tables and
+// arrays of tables.
+func convertTOMLByteStrings(tree *toml.Tree, t reflect.Type) {
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		raw := tree.GetPath([]string{fieldInfo.Key})
+		if raw == nil {
+			continue
+		}
+
+		switch {
+		case isByteSlice(fieldInfo.Type):
+			if str, ok := raw.(string); ok {
+				values := make([]int64, len(str))
+				for i := 0; i < len(str); i++ {
+					values[i] = int64(str[i])
+				}
+				tree.SetPath([]string{fieldInfo.Key}, values)
+			}
+		case fieldInfo.Type.Kind() == reflect.Struct:
+			if sub, ok := raw.(*toml.Tree); ok {
+				convertTOMLByteStrings(sub, fieldInfo.Type)
+			}
+		case fieldInfo.Type.Kind() == reflect.Slice:
+			elemType, isStruct := structElem(fieldInfo.Type)
+			if subs, ok := raw.([]*toml.Tree); ok && isStruct {
+				for _, sub := range subs {
+					convertTOMLByteStrings(sub, elemType)
+				}
+			}
+		}
+	}
+}
+
+// envRefPattern matches ${VAR} references inside TOML string values.
+var envRefPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// interpolateEnv replaces ${VAR} references in string values with the value of
+// the referenced environment variable. It walks nested structs, slices, arrays
+// and map values so that references inside TOML arrays and tables are expanded too.
+// Unset variables expand to an empty string.
+func interpolateEnv(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			interpolateEnv(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				interpolateEnv(field)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			interpolateEnv(v.Index(i))
+		}
+	case reflect.Map:
+		// Map values are not addressable, so expand a copy and store it back
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			interpolateEnv(elem)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		interpolateEnv(elem)
+		v.Set(elem)
+	case reflect.String:
+		if strings.Contains(v.String(), "${") {
+			v.SetString(expandEnvRefs(v.String()))
+		}
+	}
+}
+
+// expandEnvRefs expands every ${VAR} reference in s.
+func expandEnvRefs(s string) string {
+	return envRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return buildGetenv(ref[2 : len(ref)-1])
+	})
+}
+
+// defaultRefPattern matches {Field} references to sibling fields in default tags.
+var defaultRefPattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// hasDefaultRefs reports whether a default tag references a field of the
+// struct type t, e.g. default:"{Host}".
+func hasDefaultRefs(defaultValue string, t reflect.Type) bool {
+	for _, match := range defaultRefPattern.FindAllStringSubmatch(defaultValue, -1) {
+		if _, ok := t.FieldByName(match[1]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDefaultRefs applies defaults that reference sibling fields, such as
+// default:"{Host}", to fields that are still empty after loading. It runs
+// after all sources are applied so that references see final values, and
+// reports an error when references form a cycle.
+func resolveDefaultRefs(v reflect.Value, path string) error {
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 
 	if v.Kind() != reflect.Struct {
-		return nil // 구조체 아니면 무시
+		return nil
 	}
 
-	t := v.Type()
-	typeInfo := getCachedTypeInfo(t)
+	typeInfo := getCachedTypeInfo(v.Type())
+
+	for i, fieldInfo := range typeInfo.Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		if isNestedStruct(value.Type()) {
+			if err := resolveDefaultRefs(value, fieldPath); err != nil {
+				return err
+			}
+		}
+
+		if isStructList(fieldInfo.Type) {
+			for j := 0; j < value.Len(); j++ {
+				if err := resolveDefaultRefs(value.Index(j), indexPath(fieldPath, j)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	const (
+		unresolved = iota
+		resolving
+		resolved
+	)
+	states := make([]int, len(typeInfo.Fields))
+
+	var resolve func(i int) error
+	resolve = func(i int) error {
+		fieldInfo := typeInfo.Fields[i]
+		if !fieldInfo.DefaultRefs || states[i] == resolved {
+			return nil
+		}
+		fieldPath := joinPath(path, fieldInfo.Key)
+		if states[i] == resolving {
+			return fmt.Errorf("default value of field %s references itself through a cycle", fieldPath)
+		}
+		states[i] = resolving
+
+		// The TOML decoder copies default tags verbatim into string fields
+		// that are absent from the file, so the raw template also means unset
+		value := v.Field(i)
+		if isZero(value) || (value.Kind() == reflect.String && value.String() == fieldInfo.DefaultValue) {
+			var resolveErr error
+			expanded := defaultRefPattern.ReplaceAllStringFunc(fieldInfo.DefaultValue, func(ref string) string {
+				ref = ref[1 : len(ref)-1]
+				for j, sibling := range typeInfo.Fields {
+					if sibling.Name != ref {
+						continue
+					}
+					if err := resolve(j); err != nil && resolveErr == nil {
+						resolveErr = err
+					}
+					return formatDefaultRef(v.Field(j))
+				}
+				return "{" + ref + "}"
+			})
+			if resolveErr != nil {
+				return resolveErr
+			}
+
+			if expanded != "" {
+				parsed, err := parseFieldValue(expanded, fieldInfo, fieldPath)
+				if err != nil {
+					return err
+				}
+				value.Set(reflect.ValueOf(parsed))
+			}
+		}
+
+		states[i] = resolved
+		return nil
+	}
+
+	for i := range typeInfo.Fields {
+		if err := resolve(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatDefaultRef formats a referenced field value for substitution into a
+// default tag. Slices are joined with commas so they parse back as lists.
+func formatDefaultRef(v reflect.Value) string {
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// stringTransforms are the transforms available to the transform tag.
+var stringTransforms = map[string]func(string) string{
+	"trimspace": strings.TrimSpace,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"title":     titleCase,
+}
+
+// titleCase upper-cases the first letter of every space-separated word.
+func titleCase(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(prev) {
+			prev = r
+			return unicode.ToTitle(r)
+		}
+		prev = r
+		return r
+	}, s)
+}
+
+// applyTransforms applies the transforms listed in each field's transform tag
+// (e.g. transform:"trimspace,lower") in order. It handles string and []string
+// fields and descends into nested structs and struct slices.
+func applyTransforms(v reflect.Value, path string) error {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typeInfo := getCachedTypeInfo(v.Type())
 
 	for i, fieldInfo := range typeInfo.Fields {
 		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
 
-		// 중첩 구조체면 재귀 검사
-		if value.Kind() == reflect.Struct {
-			if err := checkRequiredField(value); err != nil {
+		if isNestedStruct(value.Type()) {
+			if err := applyTransforms(value, fieldPath); err != nil {
 				return err
 			}
 			continue
 		}
 
-		// 슬라이스 안의 구조체 검사
-		if value.Kind() == reflect.Slice && fieldInfo.Type.Elem().Kind() == reflect.Struct {
+		if isStructList(fieldInfo.Type) {
 			for j := 0; j < value.Len(); j++ {
-				if err := checkRequiredField(value.Index(j)); err != nil {
+				if err := applyTransforms(value.Index(j), indexPath(fieldPath, j)); err != nil {
 					return err
 				}
 			}
 			continue
 		}
 
-		if !fieldInfo.Required {
+		for _, name := range fieldInfo.Transforms {
+			transform, ok := stringTransforms[name]
+			if !ok {
+				return fmt.Errorf("unknown transform '%s' on field %s", name, fieldPath)
+			}
+
+			switch {
+			case value.Kind() == reflect.String:
+				value.SetString(transform(value.String()))
+			case value.Kind() == reflect.Slice && fieldInfo.Type.Elem().Kind() == reflect.String:
+				for j := 0; j < value.Len(); j++ {
+					value.Index(j).SetString(transform(value.Index(j).String()))
+				}
+			default:
+				return fmt.Errorf("transform '%s' on field %s requires a string field", name, fieldPath)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRequiredField returns the first validation problem found in v, or nil.
+func checkRequiredField(v reflect.Value) error {
+	if errs := validateFields(v, ""); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// validateFields checks the required fields of v, descending into nested
+// structs and struct slices, and returns every problem found. path is the
+// dotted path of v and is reported with each problem (e.g. "users[1].role").
+func validateFields(v reflect.Value, path string) []error {
+	// 포인터면 구조체로 접근
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil // 구조체 아니면 무시
+	}
+
+	if err := checkDepth(path); err != nil {
+		return []error{err}
+	}
+
+	t := v.Type()
+	typeInfo := getCachedTypeInfo(t)
+	var errs []error
+
+	for i, fieldInfo := range typeInfo.Fields {
+		errs = append(errs, validateField(v.Field(i), t.Field(i), fieldInfo, joinPath(path, fieldInfo.Key))...)
+	}
+
+	return errs
+}
+
+// validateField checks the field value described by field and fieldInfo,
+// whose dotted path is fieldPath, including nested sections.
+func validateField(value reflect.Value, field reflect.StructField, fieldInfo FieldInfo, fieldPath string) []error {
+	// 중첩 구조체면 재귀 검사
+	if isNestedStruct(value.Type()) {
+		// 선택적 섹션은 값이 하나라도 주어졌을 때만 검사
+		if fieldInfo.Optional && !sectionProvided(value) {
+			return nil
+		}
+		return validateFields(value, fieldPath)
+	}
+
+	// 슬라이스/배열 안의 구조체 검사
+	if isStructList(fieldInfo.Type) {
+		errs := withErrMsg(checkMinLen(value, fieldInfo, fieldPath), fieldInfo, fieldPath)
+		for j := 0; j < value.Len(); j++ {
+			errs = append(errs, validateFields(value.Index(j), indexPath(fieldPath, j))...)
+		}
+		return errs
+	}
+
+	errs := checkMinLen(value, fieldInfo, fieldPath)
+	errs = append(errs, checkBounds(value, fieldInfo, fieldPath)...)
+	errs = append(errs, checkOneOf(value, fieldInfo, fieldPath)...)
+
+	// 비어있음 검사 (기본값 포함)
+	if isRequiredField(field, fieldInfo, fieldPath) && isZero(value) {
+		tagName := fieldInfo.EnvTag
+		if tagName == "" {
+			tagName = fieldInfo.Name
+		}
+		errs = append(errs, &requiredFieldError{name: tagName, path: fieldPath})
+	}
+	errs = withErrMsg(errs, fieldInfo, fieldPath)
+
+	// 맵 값의 구조체 검사 (구조체 포인터 포함)
+	if value.Kind() == reflect.Map && isStructMapElem(fieldInfo.Type.Elem()) {
+		iter := value.MapRange()
+		for iter.Next() {
+			if elem := derefElem(iter.Value()); elem.IsValid() {
+				errs = append(errs, validateFields(elem, joinPath(fieldPath, fmt.Sprint(iter.Key().Interface())))...)
+			}
+		}
+	}
+	return errs
+}
+
+// sectionProvided reports whether any field of the struct v, including nested
+// sections, holds a value other than its zero value or its default tag value,
+// meaning the section was present in one of the config sources.
+func sectionProvided(v reflect.Value) bool {
+	typeInfo := getCachedTypeInfo(v.Type())
+
+	for i, fieldInfo := range typeInfo.Fields {
+		value := v.Field(i)
+
+		if isNestedStruct(value.Type()) {
+			if sectionProvided(value) {
+				return true
+			}
 			continue
 		}
 
-		// 비어있음 검사 (기본값 포함)
 		if isZero(value) {
-			tagName := fieldInfo.EnvTag
-			if tagName == "" {
-				tagName = fieldInfo.Name
+			continue
+		}
+
+		if fieldInfo.DefaultValue != "" {
+			if def, err := parseEnvValue(fieldInfo.DefaultValue, fieldInfo.Type); err == nil && reflect.DeepEqual(def, value.Interface()) {
+				continue
 			}
-			return fmt.Errorf("required field '%s' is missing or empty", tagName)
 		}
+		return true
 	}
 
-	return nil
+	return false
 }
 
 func isZero(v reflect.Value) bool {
@@ -275,8 +1381,14 @@ func isZero(v reflect.Value) bool {
 		return v.Int() == 0
 	case reflect.Float64, reflect.Float32:
 		return v.Float() == 0
-	case reflect.Slice, reflect.Array, reflect.Map:
+	case reflect.Slice, reflect.Map:
 		return v.Len() == 0
+	case reflect.Array:
+		// A fixed-size array always has its full length, so it counts as
+		// empty only when every element is the zero value
+		return v.IsZero()
+	case reflect.Struct:
+		return v.Type() == timeType && v.IsZero()
 	case reflect.Interface, reflect.Ptr:
 		return v.IsNil()
 	default:
@@ -284,36 +1396,220 @@ func isZero(v reflect.Value) bool {
 	}
 }
 
+// parseFieldValue parses an environment variable value for the given field.
+// path is the dotted path of the field from the config root and is used to
+// give parse errors context. For secret fields the returned error never
+// contains the raw value, so a malformed secret cannot leak into logs.
+// Values of fields with an encoding tag are decoded instead of parsed.
+func parseFieldValue(envValue string, fieldInfo FieldInfo, path string) (interface{}, error) {
+	if len(fieldInfo.TransformFns) > 0 {
+		var err error
+		if envValue, err = applyTransformFns(envValue, fieldInfo, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if fieldInfo.Encoding != "" {
+		parsed, err := parseEncodedValue(envValue, fieldInfo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode env value for field %s: %w", path, err)
+		}
+		return parsed, nil
+	}
+
+	if fieldInfo.Base != 0 {
+		return parseBaseField(envValue, fieldInfo, path)
+	}
+
+	delim := sliceDelimiter
+	if fieldInfo.Delim != "" {
+		var err error
+		if delim, err = parseDelim(fieldInfo.Delim); err != nil {
+			return nil, fmt.Errorf("invalid delim tag '%s' on field %s: %w", fieldInfo.Delim, path, err)
+		}
+	}
+
+	parsed, err := parseValue(envValue, fieldInfo.Type, delim)
+	if err != nil {
+		if isSecretField(fieldInfo, path) {
+			err = redactParseError(err, fieldInfo)
+		}
+		return nil, fmt.Errorf("failed to parse env value for field %s: %w", path, err)
+	}
+	return parsed, nil
+}
+
+// redactParseError replaces a parse error with one that only names the
+// target type, masking the offending value.
+func redactParseError(err error, fieldInfo FieldInfo) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return fmt.Errorf("cannot parse value **** as %s: %w", fieldInfo.Type, numErr.Err)
+	}
+	return fmt.Errorf("cannot parse value **** as %s", fieldInfo.Type)
+}
+
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// durationType is the reflect.Type of time.Duration, which is parsed from
+// duration strings such as "1m30s" rather than as a plain integer.
+var durationType = reflect.TypeOf(time.Duration(0))
+
 // parseEnvValue parses environment variable value to the target type.
-// Supports string, int, bool, float64, and slice types.
+// Supports string, int, bool, float64, time.Duration, and slice types.
+// []byte fields take the raw bytes of the value instead of a list.
+// Lists are split on the delimiter set with SetSliceDelimiter.
 // Returns the parsed value or an error if parsing fails.
 func parseEnvValue(envValue string, targetType reflect.Type) (interface{}, error) {
+	return parseValue(envValue, targetType, sliceDelimiter)
+}
+
+// parseValue is parseEnvValue with lists split on delim.
+func parseValue(envValue string, targetType reflect.Type, delim rune) (interface{}, error) {
 	if envValue == "" {
 		return getZeroValue(targetType), nil
 	}
 
+	if targetType == durationType {
+		return time.ParseDuration(envValue)
+	}
+
+	if targetType == timeType {
+		return parseTimeValue(envValue)
+	}
+
+	if isByteSlice(targetType) {
+		return reflect.ValueOf([]byte(envValue)).Convert(targetType).Interface(), nil
+	}
+
 	switch targetType.Kind() {
 	case reflect.String:
 		return envValue, nil
 	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
-		return strconv.Atoi(envValue)
+		return parseIntValue(envValue, targetType)
 	case reflect.Bool:
 		return strconv.ParseBool(envValue)
 	case reflect.Float64, reflect.Float32:
-		return strconv.ParseFloat(envValue, 64)
+		return parseFloatValue(envValue, targetType)
 	case reflect.Slice:
-		return parseSliceValue(envValue, targetType)
+		return parseSliceValue(envValue, targetType, delim)
+	case reflect.Array:
+		return parseArrayValue(envValue, targetType, delim)
 	default:
 		return nil, fmt.Errorf("unsupported type: %v", targetType.Kind())
 	}
 }
 
-// parseSliceValue parses comma-separated values into a slice.
+// timeLayouts are the layouts accepted for time.Time values: the TOML
+// date-time forms and a date-time with a space separator. Values without an
+// offset are in the default location.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05.999999999", "2006-01-02", "15:04:05.999999999"}
+
+// parseTimeValue parses a time.Time value in RFC 3339 form (e.g.
+// "2024-03-15T10:00:00+09:00"), as a local date-time, a local date or a
+// local time. Values without an offset are taken in the location set with
+// SetDefaultLocation.
+func parseTimeValue(envValue string) (interface{}, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, envValue, defaultLocation); err == nil {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time '%s', expected RFC 3339, a date (2006-01-02) or a time (15:04:05)", envValue)
+}
+
+// parseIntValue parses an integer into the given integer type. Values are
+// base 10 unless they start with a 0x, 0o or 0b prefix (hexadecimal, octal
+// or binary, e.g. "0xFF" or "0o755"). A plain leading zero does not select
+// octal: "0755" is 755. A leading '+' and underscore digit separators are
+// accepted.
+func parseIntValue(envValue string, targetType reflect.Type) (interface{}, error) {
+	s, base := envValue, 10
+	if hasBasePrefix(s) {
+		// strconv handles the prefix, the sign and separators itself
+		base = 0
+	} else {
+		var err error
+		if s, err = normalizeNumber(s); err != nil {
+			return nil, err
+		}
+	}
+	n, err := strconv.ParseInt(s, base, targetType.Bits())
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(n).Convert(targetType).Interface(), nil
+}
+
+// hasBasePrefix reports whether the integer s, after an optional sign,
+// starts with a 0x, 0o or 0b base prefix.
+func hasBasePrefix(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) < 2 || s[0] != '0' {
+		return false
+	}
+	switch s[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
+
+// parseFloatValue parses a floating point number into the given float type.
+// A leading '+' and underscore digit separators are accepted.
+func parseFloatValue(envValue string, targetType reflect.Type) (interface{}, error) {
+	s, err := normalizeNumber(envValue)
+	if err != nil {
+		return nil, err
+	}
+	f, err := strconv.ParseFloat(s, targetType.Bits())
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(f).Convert(targetType).Interface(), nil
+}
+
+// normalizeNumber strips a leading '+' and underscore digit separators
+// (e.g. "+8080", "1_000") so the value can be handed to strconv.
+// Thousands separators such as "1,000" are rejected with a clear error.
+func normalizeNumber(s string) (string, error) {
+	if strings.Contains(s, ",") {
+		return "", fmt.Errorf("invalid number %q: thousands separators are not supported, use '_' instead", s)
+	}
+	if strings.HasPrefix(s, "+") && !strings.HasPrefix(s, "+-") {
+		s = s[1:]
+	}
+	if !strings.Contains(s, "_") {
+		return s, nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			continue
+		}
+		// An underscore must sit between two digits
+		if i == 0 || i == len(s)-1 || !isDigit(s[i-1]) || !isDigit(s[i+1]) {
+			return "", fmt.Errorf("invalid number %q: misplaced digit separator", s)
+		}
+	}
+	return strings.ReplaceAll(s, "_", ""), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseSliceValue parses delim-separated values into a slice.
 // Handles slices of string, int, bool, and float64 types.
+// Elements may be double-quoted to include the delimiter, e.g. `"a,b",c`.
 // Empty values are skipped during parsing.
-func parseSliceValue(envValue string, sliceType reflect.Type) (interface{}, error) {
+func parseSliceValue(envValue string, sliceType reflect.Type, delim rune) (interface{}, error) {
 	elemType := sliceType.Elem()
-	strs := strings.Split(envValue, ",")
+	strs, err := splitList(envValue, delim)
+	if err != nil {
+		return nil, err
+	}
 	sliceVal := reflect.MakeSlice(sliceType, 0, len(strs))
 
 	for _, s := range strs {
@@ -331,6 +1627,61 @@ func parseSliceValue(envValue string, sliceType reflect.Type) (interface{}, erro
 	return sliceVal.Interface(), nil
 }
 
+// parseArrayValue parses delim-separated values into a fixed-size array.
+// The number of elements must match the array length exactly.
+func parseArrayValue(envValue string, arrayType reflect.Type, delim rune) (interface{}, error) {
+	parsed, err := parseSliceValue(envValue, reflect.SliceOf(arrayType.Elem()), delim)
+	if err != nil {
+		return nil, err
+	}
+
+	elems := reflect.ValueOf(parsed)
+	if elems.Len() != arrayType.Len() {
+		return nil, fmt.Errorf("expected %d elements for %s, got %d", arrayType.Len(), arrayType, elems.Len())
+	}
+
+	arrayVal := reflect.New(arrayType).Elem()
+	reflect.Copy(arrayVal, elems)
+	return arrayVal.Interface(), nil
+}
+
+// splitList splits a delim-separated list into its elements.
+// When an element starts with a quote the list is parsed as a CSV record so
+// that quoted elements may contain the delimiter; a list without one is split
+// as it is, keeping quotes inside elements. A space
+// delimiter splits on runs of whitespace.
+func splitList(envValue string, delim rune) ([]string, error) {
+	var strs []string
+	if delim == ' ' {
+		strs = strings.Fields(envValue)
+	} else {
+		strs = strings.Split(envValue, string(delim))
+	}
+	quoted := false
+	for _, s := range strs {
+		if strings.HasPrefix(strings.TrimLeft(s, " \t"), `"`) {
+			quoted = true
+			break
+		}
+	}
+	if !quoted {
+		return strs, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(envValue))
+	r.Comma = delim
+	r.TrimLeadingSpace = true
+	strs, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid quoted list: %w", err)
+	}
+	// A line break outside quotes would start a second record
+	if _, err := r.Read(); err != io.EOF {
+		return nil, fmt.Errorf("invalid quoted list: line breaks must be inside quotes")
+	}
+	return strs, nil
+}
+
 // getZeroValue returns the zero value for the given type.
 // Used when environment variable is empty or not set.
 func getZeroValue(t reflect.Type) interface{} {
@@ -350,7 +1701,27 @@ func getZeroValue(t reflect.Type) interface{} {
 	}
 }
 
+// lookupEnv looks up an environment variable for the loaders.
+var lookupEnv = os.LookupEnv
+
+// envLookup looks up a value by its environment variable name. The env
+// loaders take it as a parameter so that other key/value sources, such as a
+// directory of key files, can reuse them.
+type envLookup func(key string) (string, bool)
+
+// getEnv returns the value of an environment variable, or "" if it is unset.
+func getEnv(key string) string {
+	value, _ := lookupEnv(key)
+	return value
+}
+
 func loadConfigEnv[T any](cfg *T) error {
+	return loadPrefixedEnv(takeEnvSnapshot(AppName).lookup, cfg, AppName)
+}
+
+// loadPrefixedEnv populates cfg from the environment variables named after
+// prefix, looked up with lookup.
+func loadPrefixedEnv(lookup envLookup, cfg interface{}, prefix string) error {
 	v := reflect.ValueOf(cfg)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -360,106 +1731,290 @@ func loadConfigEnv[T any](cfg *T) error {
 		return nil // 구조체가 아니면 무시
 	}
 
-	return loadStructEnv(v, AppName)
+	return loadStructEnv(withPrefixFallbacks(lookup, prefix), v, prefix, "")
+}
+
+// fieldEnvKey returns the environment variable name of a field nested under
+// prefix: {PREFIX}_{ENV_TAG}, using the field name when the env tag is empty.
+// An envabs tag names the variable exactly and ignores the prefix.
+func fieldEnvKey(prefix string, fieldInfo FieldInfo) string {
+	if fieldInfo.EnvAbs != "" {
+		return fieldInfo.EnvAbs
+	}
+
+	// Convert hyphens to underscores for environment variable names
+	normalizedPrefix := strings.ReplaceAll(strings.ToUpper(prefix), "-", "_")
+	if fieldInfo.EnvTag == "" {
+		return normalizedPrefix + "_" + strings.ToUpper(fieldInfo.Name)
+	}
+	return normalizedPrefix + "_" + strings.ToUpper(fieldInfo.EnvTag)
+}
+
+// loadStructEnv populates the struct v from environment variables named after
+// parentPrefix. parentPath is the dotted path of v from the config root.
+func loadStructEnv(lookup envLookup, v reflect.Value, parentPrefix, parentPath string) error {
+	if err := checkDepth(parentPath); err != nil {
+		return err
+	}
+
+	if getCachedTypeInfo(v.Type()).Flat {
+		return loadFlatStructEnv(lookup, v, parentPrefix, parentPath)
+	}
+	return loadNestedStructEnv(lookup, v, parentPrefix, parentPath)
+}
+
+// loadFlatStructEnv populates a struct without nested structs or struct
+// slices in a single pass, with the environment variable names of its fields
+// computed once per prefix.
+func loadFlatStructEnv(lookup envLookup, v reflect.Value, parentPrefix, parentPath string) error {
+	typeInfo := getCachedTypeInfo(v.Type())
+
+	keys, ok := typeInfo.envKeys.Load(parentPrefix)
+	if !ok {
+		envKeys := make([]string, len(typeInfo.Fields))
+		for i, fieldInfo := range typeInfo.Fields {
+			envKeys[i] = fieldEnvKey(parentPrefix, fieldInfo)
+		}
+		keys, _ = typeInfo.envKeys.LoadOrStore(parentPrefix, envKeys)
+	}
+
+	for i, envKey := range keys.([]string) {
+		fieldInfo := typeInfo.Fields[i]
+		if err := loadFieldEnv(lookup, v.Field(i), fieldInfo, envKey, joinPath(parentPath, fieldInfo.Key)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func loadStructEnv(v reflect.Value, parentPrefix string) error {
+// loadNestedStructEnv populates a struct that has nested structs or struct
+// slices, descending into them.
+func loadNestedStructEnv(lookup envLookup, v reflect.Value, parentPrefix, parentPath string) error {
 	t := v.Type()
 	typeInfo := getCachedTypeInfo(t)
 
 	for i, fieldInfo := range typeInfo.Fields {
 		value := v.Field(i)
-
-		// Convert hyphens to underscores for environment variable names
-		normalizedPrefix := strings.ReplaceAll(strings.ToUpper(parentPrefix), "-", "_")
-		envKeyBase := normalizedPrefix + "_" + strings.ToUpper(fieldInfo.EnvTag)
-		if fieldInfo.EnvTag == "" {
-			envKeyBase = normalizedPrefix + "_" + strings.ToUpper(fieldInfo.Name)
-		}
+		path := joinPath(parentPath, fieldInfo.Key)
+		envKeyBase := fieldEnvKey(parentPrefix, fieldInfo)
 
 		// --- ✅ 슬라이스(특히 []struct) 처리 ---
-		if value.Kind() == reflect.Slice && fieldInfo.Type.Elem().Kind() == reflect.Struct {
-			sliceValues, err := loadStructSliceEnv(envKeyBase, fieldInfo.Type.Elem())
+		if value.Kind() == reflect.Slice && isStructList(fieldInfo.Type) {
+			// With a merge key env elements are matched to the file's by identity
+			if fieldInfo.MergeKey != "" {
+				if err := mergeKeyedSliceEnv(lookup, value, fieldInfo, envKeyBase, path); err != nil {
+					return err
+				}
+				continue
+			}
+
+			// Defaulted elements are only materialized when the file didn't provide the slice
+			minElems := 0
+			if value.Len() == 0 {
+				minElems = fieldInfo.MinElems
+			}
+
+			// In extend mode env vars override the elements from the file by
+			// index, and higher indices append to them
+			start := 0
+			if fieldInfo.Merge != "" && fieldInfo.Merge != "extend" {
+				return fmt.Errorf("unknown merge mode '%s' on field %s", fieldInfo.Merge, path)
+			}
+			if fieldInfo.Merge == "extend" {
+				for j := 0; j < value.Len(); j++ {
+					elem := value.Index(j)
+					if elem.Kind() == reflect.Ptr {
+						if elem.IsNil() {
+							continue
+						}
+						elem = elem.Elem()
+					}
+					if err := loadStructEnv(lookup, elem, indexEnvKey(envKeyBase, j), indexPath(path, j)); err != nil {
+						return err
+					}
+				}
+				start = value.Len()
+			}
+
+			elemType, _ := structElem(fieldInfo.Type)
+			sliceValues, err := loadStructSliceEnv(lookup, envKeyBase, path, elemType, start, minElems)
 			if err != nil {
 				return err
 			}
+			// Slices of struct pointers hold the addresses of the built elements
+			if fieldInfo.Type.Elem().Kind() == reflect.Ptr {
+				for j := range sliceValues {
+					sliceValues[j] = sliceValues[j].Addr()
+				}
+			}
 			// In hybrid mode, if env vars exist, replace TOML slice completely
 			// If no env vars, keep TOML slice
 			if len(sliceValues) > 0 {
-				value.Set(reflect.MakeSlice(value.Type(), 0, len(sliceValues)))
+				if start == 0 {
+					value.Set(reflect.MakeSlice(value.Type(), 0, len(sliceValues)))
+				}
 				value.Set(reflect.Append(value, sliceValues...))
 			}
 			continue
 		}
 
-		// --- ✅ 일반 필드 처리 ---
-		envValue := os.Getenv(envKeyBase)
-
 		// 중첩 구조체는 값을 직접 설정하지 않고 재귀적으로 처리하므로 건너뛴다.
-		if value.Kind() == reflect.Struct {
+		if isNestedStruct(value.Type()) {
+			envValue, _ := lookup(envKeyBase)
+
+			// A struct with a format tag is set from a single document first
+			if fieldInfo.Format != "" && envValue != "" {
+				if err := loadFormattedValue(value, fieldInfo, envValue, path); err != nil {
+					if skipParseError(err) {
+						continue
+					}
+					return err
+				}
+			}
+
+			// An inline struct is set from a single key=value list first
+			if fieldInfo.Inline && envValue != "" {
+				if err := loadInlineStruct(value, envValue, path); err != nil {
+					if skipParseError(err) {
+						continue
+					}
+					return err
+				}
+			}
+
 			// 환경변수가 있거나 기본값이 있는 경우 재귀적으로 처리
-			hasEnvVars := hasStructEnvValues(value, envKeyBase)
-			hasDefaults := hasStructDefaultValues(value)
+			hasEnvVars := hasStructEnvValues(lookup, value, envKeyBase)
+			hasDefaults := hasStructDefaultValues(value) || hasRuntimeDefaults(path)
 			if envValue != "" || hasEnvVars || hasDefaults {
-				if err := loadStructEnv(value, envKeyBase); err != nil {
+				if err := loadStructEnv(lookup, value, envKeyBase, path); err != nil {
 					return err
 				}
 			}
 			continue
 		}
 
-		// Apply default value if env is empty AND no TOML value exists
-		// In hybrid mode, TOML values should take precedence over defaults
-		if envValue == "" && fieldInfo.DefaultValue != "" && isZero(value) {
-			envValue = fieldInfo.DefaultValue
+		// --- ✅ 일반 필드 처리 ---
+		if err := loadFieldEnv(lookup, value, fieldInfo, envKeyBase, path); err != nil {
+			return err
 		}
+	}
 
-		// In hybrid mode, we don't validate required fields here
-		// Required field validation is done in checkRequiredField after all loading is complete
+	return nil
+}
 
-		// Use unified parser for type conversion
-		// Only set value if we have an environment variable or default value
-		// This preserves TOML values when no env var is set
-		if envValue != "" {
-			parsed, err := parseEnvValue(envValue, value.Type())
-			if err != nil {
-				return fmt.Errorf("failed to parse env value for field %s: %w", fieldInfo.Name, err)
-			}
-			value.Set(reflect.ValueOf(parsed))
+// loadFieldEnv sets a field that is not a nested struct or struct slice from
+// the environment variable envKey, or from its default when the variable is
+// unset and the field is still zero.
+func loadFieldEnv(lookup envLookup, value reflect.Value, fieldInfo FieldInfo, envKey, path string) error {
+	envValue, present := lookup(envKey)
+
+	// Without an env var, a systemd credential can provide the value
+	if !present && fieldInfo.Credential != "" {
+		var err error
+		if envValue, present, err = lookupCredential(fieldInfo.Credential, path); err != nil {
+			return err
+		}
+	}
+
+	// A map or slice with a format tag is set from a single document
+	if fieldInfo.Format != "" && envValue != "" {
+		if err := loadFormattedValue(value, fieldInfo, envValue, path); err != nil && !skipParseError(err) {
+			return err
+		}
+		return nil
+	}
+
+	// A negated bool is the opposite of its env var, e.g. DISABLE_CACHE=true
+	// sets EnableCache to false
+	if fieldInfo.Negate && envValue != "" {
+		return loadNegatedEnv(value, fieldInfo, envValue, path)
+	}
+
+	// A strict field fails the load instead of falling back when its env var
+	// is set but empty or unparseable
+	if fieldInfo.Strict && present {
+		if envValue == "" {
+			strictEnvErrors = append(strictEnvErrors, fmt.Errorf("env var %s of strict field %s is set but empty", envKey, path))
+			return nil
+		}
+		parsed, err := parseFieldValue(envValue, fieldInfo, path)
+		if err != nil {
+			strictEnvErrors = append(strictEnvErrors, err)
+			return nil
+		}
+		value.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	// Scalar slices can also be set one element per variable: {KEY}_0, {KEY}_1, ...
+	if envValue == "" && isScalarSlice(fieldInfo.Type) {
+		found, err := loadIndexedSlice(lookup, value, fieldInfo, envKey, path)
+		if err != nil && !skipParseError(err) {
+			return err
 		}
+		if found {
+			return nil
+		}
+	}
+
+	// An explicitly empty env var clears the field when EmptyClears is set
+	if envValue == "" && present && emptyEnvMode == EmptyClears {
+		value.Set(reflect.Zero(value.Type()))
+		return nil
 	}
 
+	// Apply default value if env is empty AND no TOML value exists
+	// In hybrid mode, TOML values should take precedence over defaults
+	if envValue == "" && isZero(value) {
+		envValue = fieldDefault(fieldInfo, path)
+	}
+
+	// In hybrid mode, we don't validate required fields here
+	// Required field validation is done in checkRequiredField after all loading is complete
+
+	// Use unified parser for type conversion
+	// Only set value if we have an environment variable or default value
+	// This preserves TOML values when no env var is set
+	if envValue == "" {
+		return nil
+	}
+	parsed, err := parseFieldValue(envValue, fieldInfo, path)
+	if err != nil {
+		if skipParseError(err) {
+			return nil
+		}
+		return err
+	}
+	value.Set(reflect.ValueOf(parsed))
 	return nil
 }
 
 // hasStructEnvValues는 중첩된 구조체에 환경변수 값이 있는지 확인하는 헬퍼 함수
-func hasStructEnvValues(v reflect.Value, prefix string) bool {
+func hasStructEnvValues(lookup envLookup, v reflect.Value, prefix string) bool {
 	t := v.Type()
 	typeInfo := getCachedTypeInfo(t)
 
 	for i, fieldInfo := range typeInfo.Fields {
 		value := v.Field(i)
-
-		// Convert hyphens to underscores for environment variable names
-		normalizedPrefix := strings.ReplaceAll(strings.ToUpper(prefix), "-", "_")
-		envKeyBase := normalizedPrefix + "_" + strings.ToUpper(fieldInfo.EnvTag)
-		if fieldInfo.EnvTag == "" {
-			envKeyBase = normalizedPrefix + "_" + strings.ToUpper(fieldInfo.Name)
-		}
+		envKeyBase := fieldEnvKey(prefix, fieldInfo)
 
 		// 슬라이스 필드 처리
-		if value.Kind() == reflect.Slice && fieldInfo.Type.Elem().Kind() == reflect.Struct {
+		if value.Kind() == reflect.Slice && isStructList(fieldInfo.Type) {
 			// 슬라이스의 첫 번째 요소에 대해 확인
-			if hasStructSliceEnvValues(envKeyBase, fieldInfo.Type.Elem()) {
+			elemType, _ := structElem(fieldInfo.Type)
+			if hasStructSliceEnvValues(lookup, envKeyBase, elemType) {
 				return true
 			}
 			continue
 		}
 
 		// 중첩 구조체 재귀 확인
-		if value.Kind() == reflect.Struct {
+		if isNestedStruct(value.Type()) {
+			if envValue, _ := lookup(envKeyBase); (fieldInfo.Inline || fieldInfo.Format != "") && envValue != "" {
+				return true
+			}
 			log.Printf("DEBUG: Checking nested struct %s with prefix %s", fieldInfo.Name, envKeyBase)
-			if hasStructEnvValues(value, envKeyBase) {
+			if hasStructEnvValues(lookup, value, envKeyBase) {
 				log.Printf("DEBUG: Found env vars for nested struct %s", fieldInfo.Name)
 				return true
 			}
@@ -467,10 +2022,19 @@ func hasStructEnvValues(v reflect.Value, prefix string) bool {
 		}
 
 		// 일반 필드 확인
-		envValue := os.Getenv(envKeyBase)
-		if envValue != "" {
+		envValue, present := lookup(envKeyBase)
+		if envValue != "" || (present && emptyEnvMode == EmptyClears) {
+			return true
+		}
+		if isScalarSlice(fieldInfo.Type) && len(lookupIndexedEnv(lookup, envKeyBase)) > 0 {
 			return true
 		}
+		if fieldInfo.Credential != "" {
+			// A credential that fails to read is reported when the field is loaded
+			if _, found, err := lookupCredential(fieldInfo.Credential, fieldInfo.Key); found || err != nil {
+				return true
+			}
+		}
 	}
 
 	return false
@@ -492,11 +2056,11 @@ func hasStructDefaultValues(v reflect.Value) bool {
 }
 
 // hasStructSliceEnvValues는 구조체 슬라이스에 환경변수 값이 있는지 확인하는 헬퍼 함수
-func hasStructSliceEnvValues(prefix string, t reflect.Type) bool {
+func hasStructSliceEnvValues(lookup envLookup, prefix string, t reflect.Type) bool {
 	// 첫 번째 인덱스(0)에 대해서만 확인
 	// Convert hyphens to underscores for environment variable names
 	normalizedPrefix := strings.ReplaceAll(strings.ToUpper(prefix), "-", "_")
-	envKey := fmt.Sprintf("%s_0_", normalizedPrefix)
+	envKey := indexEnvKey(normalizedPrefix, 0) + "_"
 
 	// 구조체의 모든 필드에 대해 환경변수가 있는지 확인
 	for j := 0; j < t.NumField(); j++ {
@@ -507,13 +2071,13 @@ func hasStructSliceEnvValues(prefix string, t reflect.Type) bool {
 		}
 		fieldEnvKey := envKey + strings.ToUpper(tag)
 
-		if os.Getenv(fieldEnvKey) != "" {
+		if value, _ := lookup(fieldEnvKey); value != "" {
 			return true
 		}
 
 		// 중첩된 구조체 필드 확인
-		if field.Type.Kind() == reflect.Struct {
-			if hasStructEnvValues(reflect.New(field.Type).Elem(), fieldEnvKey) {
+		if isNestedStruct(field.Type) {
+			if hasStructEnvValues(lookup, reflect.New(field.Type).Elem(), fieldEnvKey) {
 				return true
 			}
 		}
@@ -522,79 +2086,67 @@ func hasStructSliceEnvValues(prefix string, t reflect.Type) bool {
 	return false
 }
 
-func loadStructSliceEnv(prefix string, t reflect.Type) ([]reflect.Value, error) {
+// loadStructSliceEnv builds the elements of a struct slice from indexed
+// environment variables ({prefix}_{i}_{FIELD}, see SetSliceIndexStyle),
+// starting at index start.
+// path is the dotted path of the slice field and is used for error messages.
+// Elements below index minElems are kept even when they are built from
+// defaults alone.
+func loadStructSliceEnv(lookup envLookup, prefix, path string, t reflect.Type, start, minElems int) ([]reflect.Value, error) {
 	var result []reflect.Value
 
 	// Convert hyphens to underscores for environment variable names
 	normalizedPrefix := strings.ReplaceAll(strings.ToUpper(prefix), "-", "_")
 
-	for i := 0; ; i++ {
+	typeInfo := getCachedTypeInfo(t)
+	for i := start; ; i++ {
 		elem := reflect.New(t).Elem()
+		elemPath := indexPath(path, i)
 		hasAnyEnvValue := false // Only count actual environment variables, not defaults
 
-		for j := 0; j < t.NumField(); j++ {
-			field := t.Field(j)
-			tag := field.Tag.Get("env")
+		for j, fieldInfo := range typeInfo.Fields {
+			tag := fieldInfo.EnvTag
 			if tag == "" {
-				tag = field.Name
+				tag = fieldInfo.Name
 			}
-			envKey := fmt.Sprintf("%s_%d_%s", normalizedPrefix, i, strings.ToUpper(tag))
-			envVal := os.Getenv(envKey)
-
-			// Get field info for default value and required check
-			fieldInfo := FieldInfo{
-				Name:         field.Name,
-				Type:         field.Type,
-				EnvTag:       tag,
-				DefaultValue: field.Tag.Get("default"),
-				Required:     strings.ToLower(field.Tag.Get("required")) == "true",
-				Secret:       strings.ToLower(field.Tag.Get("secret")) == "true",
-			}
-
+			envKey := indexEnvKey(normalizedPrefix, i) + "_" + strings.ToUpper(tag)
+			fieldPath := joinPath(elemPath, fieldInfo.Key)
 			fieldVal := elem.Field(j)
 
 			// 중첩된 구조체는 재귀적으로 처리
-			if fieldVal.Kind() == reflect.Struct {
-				if err := loadStructEnv(fieldVal, envKey); err != nil {
+			if isNestedStruct(fieldVal.Type()) {
+				if err := loadStructEnv(lookup, fieldVal, envKey, fieldPath); err != nil {
 					return nil, err
 				}
 				// 구조체 필드가 처리되었는지 확인 (하위 필드에 env 값이 있는지)
-				if hasStructEnvValues(fieldVal, envKey) {
+				if hasStructEnvValues(lookup, fieldVal, envKey) {
 					hasAnyEnvValue = true
 				}
 				continue
 			}
 
 			// Only count actual environment variables for hasAnyEnvValue
+			envVal, _ := lookup(envKey)
 			if envVal != "" {
 				hasAnyEnvValue = true
 			}
 
-			// Apply default value if env is empty (regardless of required status)
-			if envVal == "" && fieldInfo.DefaultValue != "" {
-				envVal = fieldInfo.DefaultValue
-			}
-
 			// Check required field validation - only if we have environment variables
 			// In env-only mode, we should not fail here as required validation is done later
-			if envVal == "" && fieldInfo.Required && hasAnyEnvValue {
+			if envVal == "" && fieldInfo.Required && hasAnyEnvValue && fieldDefault(fieldInfo, fieldPath) == "" {
 				// required field인데 default 값도 없으면 에러 (단, 환경변수가 있는 경우에만)
 				return nil, fmt.Errorf("required field '%s' is missing or empty", tag)
 			}
 
-			// Use unified parser for type conversion
-			if envVal != "" || !isZero(fieldVal) {
-				parsed, err := parseEnvValue(envVal, fieldVal.Type())
-				if err != nil {
-					return nil, fmt.Errorf("failed to parse env value for field %s: %w", field.Name, err)
-				}
-				fieldVal.Set(reflect.ValueOf(parsed))
+			// Defaults, format and strict tags are applied as for any other field
+			if err := loadFieldEnv(lookup, fieldVal, fieldInfo, envKey, fieldPath); err != nil {
+				return nil, err
 			}
 		}
 
 		// Only break if no environment variables were found for this index
 		// This prevents infinite loop when only default values are present
-		if !hasAnyEnvValue {
+		if !hasAnyEnvValue && i >= minElems {
 			break
 		}
 
@@ -611,12 +2163,9 @@ func loadStructSliceEnv(prefix string, t reflect.Type) ([]reflect.Value, error)
 //
 //	cfg := ahatconfig.GetConfig[MyConfig]()
 func GetConfig[T any]() *T {
-	if instance == nil {
-		panic("Config not initialized. Call InitConfig first.")
-	}
-	cfg, ok := instance.(*T)
-	if !ok {
-		panic("Invalid config type")
+	cfg, err := GetConfigSafe[T]()
+	if err != nil {
+		panic(err.Error())
 	}
 	return cfg
 }
@@ -631,16 +2180,46 @@ func GetConfig[T any]() *T {
 //	    log.Fatal(err)
 //	}
 func GetConfigSafe[T any]() (*T, error) {
-	if instance == nil {
-		return nil, fmt.Errorf("config not initialized, call InitConfig first")
+	cfg, err := instanceAs[T](currentInstance())
+	if err == nil {
+		refreshIfStale[T]()
+	}
+	return cfg, err
+}
+
+// instanceAs returns the loaded configuration current as a *T, or an error
+// when it is not loaded or has another type.
+func instanceAs[T any](current interface{}) (*T, error) {
+	if current == nil {
+		return nil, fmt.Errorf("config not initialized, call InitConfig first (requested %T)", (*T)(nil))
 	}
-	cfg, ok := instance.(*T)
+	cfg, ok := current.(*T)
 	if !ok {
-		return nil, fmt.Errorf("invalid config type")
+		return nil, fmt.Errorf("invalid config type: requested %T, but config was loaded as %T", (*T)(nil), current)
 	}
 	return cfg, nil
 }
 
+// GetConfigValue retrieves a copy of the loaded configuration.
+// Unlike GetConfigSafe, the returned value is not shared with the package, so
+// reassigning its fields does not affect later GetConfig calls. The copy is
+// shallow: slices and maps still share their backing storage.
+//
+// Example:
+//
+//	cfg, err := ahatconfig.GetConfigValue[MyConfig]()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func GetConfigValue[T any]() (T, error) {
+	var zero T
+	cfg, err := GetConfigSafe[T]()
+	if err != nil {
+		return zero, err
+	}
+	return *cfg, nil
+}
+
 // PrintConfig prints the current configuration with secret masking applied.
 // Fields marked with secret:"true" will be displayed as "****".
 // This is useful for debugging and logging configuration values safely.
@@ -660,58 +2239,179 @@ func GetConfigSafe[T any]() (*T, error) {
 //	//     "Password": "****"
 //	//   }
 //	// }
+//
+// During an incident the real values can be printed: secrets are shown only
+// when AllowUnmaskedSecrets(true) was called AND {APPNAME}_CONFIG_UNMASK=1 is
+// set. Either gate alone keeps masking on.
 func PrintConfig() {
-	masked := maskSecrets(instance)
-	configBytes, err := json.MarshalIndent(masked, "", "  ")
-	if err != nil {
+	printConfig(FprintConfig)
+}
+
+// printConfig writes the current configuration to stdout with fprint, masked
+// unless both gates of PrintConfig are open. PrintConfig and PrintConfigFlat
+// share it so that they agree on when secrets are shown.
+func printConfig(fprint func(w io.Writer, mask bool) error) {
+	mask := !(allowUnmasked && unmaskRequested())
+	if !mask {
+		log.Printf("Printing config with secrets unmasked (%s is set)", unmaskEnvKey())
+	}
+	if err := fprint(os.Stdout, mask); err != nil {
 		log.Printf("Failed to print config: %v", err)
-		return
+	}
+}
+
+// allowUnmasked enables unmasked output in FprintConfig.
+var allowUnmasked bool
+
+// AllowUnmaskedSecrets enables or disables printing secrets in clear text
+// with FprintConfig. It is disabled by default so that unmasked output cannot
+// be turned on by accident; enable it only for local debugging.
+//
+// Example:
+//
+//	if *debugFlag {
+//	    ahatconfig.AllowUnmaskedSecrets(true)
+//	    ahatconfig.FprintConfig(os.Stderr, false)
+//	}
+func AllowUnmaskedSecrets(allow bool) {
+	allowUnmasked = allow
+}
+
+// unmaskEnvKey returns the name of the {APPNAME}_CONFIG_UNMASK variable.
+func unmaskEnvKey() string {
+	return strings.ReplaceAll(strings.ToUpper(AppName), "-", "_") + "_CONFIG_UNMASK"
+}
+
+// unmaskRequested reports whether {APPNAME}_CONFIG_UNMASK is set to 1.
+func unmaskRequested() bool {
+	return strings.TrimSpace(getEnv(unmaskEnvKey())) == "1"
+}
+
+// FprintConfig writes the current configuration to w in the format of
+// PrintConfig, listing fields in declaration order. Secret fields are masked
+// unless mask is false, which is only honored after
+// AllowUnmaskedSecrets(true); otherwise FprintConfig writes nothing and
+// returns an error.
+//
+// Example:
+//
+//	ahatconfig.FprintConfig(logFile, true)
+func FprintConfig(w io.Writer, mask bool) error {
+	if !mask && !allowUnmasked {
+		return fmt.Errorf("unmasked config output is disabled, call AllowUnmaskedSecrets(true) first")
+	}
+
+	configBytes, err := json.MarshalIndent(maskSecretsAt(currentInstance(), "", mask), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "🔹 config:\n%s\n", configBytes)
+	return err
+}
+
+// PrintConfigFlat prints the configuration like PrintConfig but as one
+// "dotted.path = value" line per value, in the order of Values, which is
+// easier to scan than nested JSON. Secrets are masked behind the same gates
+// as PrintConfig.
+//
+// Example:
+//
+//	ahatconfig.PrintConfigFlat()
+//	// Output:
+//	// 🔹 config:
+//	// server.host = localhost
+//	// server.port = 8080
+//	// database.password = ****
+func PrintConfigFlat() {
+	printConfig(FprintConfigFlat)
+}
+
+// FprintConfigFlat writes the current configuration to w in the format of
+// PrintConfigFlat. mask behaves as in FprintConfig.
+func FprintConfigFlat(w io.Writer, mask bool) error {
+	if !mask && !allowUnmasked {
+		return fmt.Errorf("unmasked config output is disabled, call AllowUnmaskedSecrets(true) first")
 	}
 
-	fmt.Println("🔹 config:")
-	fmt.Println(string(configBytes))
+	var buf bytes.Buffer
+	buf.WriteString("🔹 config:\n")
+	for _, v := range configValues(mask) {
+		fmt.Fprintf(&buf, "%s = %v\n", v.Path, v.Value)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
 }
 
 func maskSecrets(cfg interface{}) interface{} {
+	return maskSecretsAt(cfg, "", true)
+}
+
+// maskSecretsAt converts cfg, whose dotted path from the config root is path,
+// into plain values for printing: structs become orderedFields keyed by field
+// name in declaration order, slices and maps become []interface{} and
+// map[string]interface{}. Secret fields are masked when mask is true. Nil
+// slices print as [] and nil maps as {}, nil pointers as null.
+func maskSecretsAt(cfg interface{}, path string, mask bool) interface{} {
+	if err := checkDepth(path); err != nil {
+		return err.Error()
+	}
+
 	v := reflect.ValueOf(cfg)
+	if !v.IsValid() {
+		return nil
+	}
 
+	// A nil pointer, such as an unset optional section, prints as null
 	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
 		v = v.Elem()
 	}
 
 	switch v.Kind() {
 	case reflect.Struct:
 		t := v.Type()
+		if t == timeType {
+			return v.Interface()
+		}
 		typeInfo := getCachedTypeInfo(t)
-		masked := map[string]interface{}{}
+		masked := make(orderedFields, 0, len(typeInfo.Fields))
 
 		for i, fieldInfo := range typeInfo.Fields {
-			field := v.Field(i)
 			fieldName := fieldInfo.Name
+			fieldPath := joinPath(path, fieldInfo.Key)
 
-			// 재귀 구조
-			if field.Kind() == reflect.Struct || field.Kind() == reflect.Slice {
-				masked[fieldName] = maskSecrets(field.Interface())
+			// 시크릿 마스킹 (슬라이스와 맵도 통째로 마스킹)
+			if mask && isSecretField(fieldInfo, fieldPath) {
+				masked = append(masked, PathValue{Path: fieldName, Value: "****"})
 				continue
 			}
 
-			// 시크릿 마스킹
-			if fieldInfo.Secret {
-				masked[fieldName] = "****"
-			} else {
-				masked[fieldName] = field.Interface()
-			}
+			// 재귀 구조
+			masked = append(masked, PathValue{Path: fieldName, Value: maskSecretsAt(v.Field(i).Interface(), fieldPath, mask)})
 		}
 		return masked
 
 	case reflect.Slice:
 		result := []interface{}{}
 		for i := 0; i < v.Len(); i++ {
-			result = append(result, maskSecrets(v.Index(i).Interface()))
+			result = append(result, maskSecretsAt(v.Index(i).Interface(), indexPath(path, i), mask))
+		}
+		return result
+
+	case reflect.Map:
+		result := map[string]interface{}{}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			result[key] = maskSecretsAt(iter.Value().Interface(), joinPath(path, key), mask)
 		}
 		return result
 
 	default:
-		return cfg
+		return v.Interface()
 	}
 }
@@ -2,6 +2,8 @@ package ahatconfig
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
@@ -9,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 )
 
 // Test struct for configuration
@@ -47,9 +51,45 @@ type NestedStructSliceConfig struct {
 
 func resetGlobalConfig() {
 	instance = nil
+	instanceSource = configSource{}
 	once = sync.Once{}
 	AppName = ""
 	configPath = ""
+	validationMode = Strict
+	warnings = nil
+	emptyEnvMode = EmptyIsUnset
+	secretPredicate = nil
+	environment = ""
+	parseErrorMode = FailFast
+	parseErrors = nil
+	runtimeDefaults = nil
+	maxDepth = DefaultMaxDepth
+	secretSourcePolicy = AllowFile
+	sliceDelimiter = ','
+	allowUnmasked = false
+	secretProviders = map[string]SecretProvider{}
+	transformFuncs = map[string]TransformFunc{}
+	envSecretProviders = map[string]map[string]SecretProvider{}
+	tomlKeyStyle = DefaultKeyStyle
+	requiredByDefault = false
+	defaultLocation = time.UTC
+	tomlDecodeOptions = nil
+	refreshInterval = 0
+	reloadErrorHook = nil
+	envPrefixFallbacks = nil
+	sliceIndexStyle = UnderscoreIndex
+	conflictPolicy = EnvWins
+	now = time.Now
+}
+
+// maskedFields turns a struct printed by maskSecrets into a map keyed by field
+// name, for looking fields up in tests.
+func maskedFields(v interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for _, field := range v.(orderedFields) {
+		fields[field.Path] = field.Value
+	}
+	return fields
 }
 
 func createTestTomlFile(t *testing.T, appName, content string) (string, func()) {
@@ -464,7 +504,7 @@ func TestLoadStructSliceEnvNestedStructs(t *testing.T) {
 	servicesType := servicesField.Type.Elem() // 슬라이스 요소 타입
 
 	// loadStructSliceEnv 함수 직접 테스트
-	result, err := loadStructSliceEnv("SERVICES", servicesType)
+	result, err := loadStructSliceEnv(lookupEnv, "SERVICES", "services", servicesType, 0, 0)
 	if err != nil {
 		t.Fatalf("loadStructSliceEnv failed: %v", err)
 	}
@@ -571,7 +611,7 @@ func TestLoadStructSliceEnvNestedStructsWithDefaults(t *testing.T) {
 	servicesType := servicesField.Type.Elem() // 슬라이스 요소 타입
 
 	// loadStructSliceEnv 함수 직접 테스트
-	result, err := loadStructSliceEnv("SERVICES", servicesType)
+	result, err := loadStructSliceEnv(lookupEnv, "SERVICES", "services", servicesType, 0, 0)
 	if err != nil {
 		t.Fatalf("loadStructSliceEnv failed: %v", err)
 	}
@@ -598,6 +638,98 @@ func TestLoadStructSliceEnvNestedStructsWithDefaults(t *testing.T) {
 	}
 }
 
+// TestStructSliceEnvDurations는 구조체 슬라이스 요소의 time.Duration, time.Time 필드가 최상위 필드와 같은 파서로 인덱스 환경변수에서 로드되는지 테스트합니다
+func TestStructSliceEnvDurations(t *testing.T) {
+	type Upstream struct {
+		Name    string          `toml:"name" env:"NAME"`
+		Timeout time.Duration   `toml:"timeout" env:"TIMEOUT" default:"5s"`
+		Backoff []time.Duration `toml:"backoff" env:"BACKOFF"`
+		Since   time.Time       `toml:"since" env:"SINCE"`
+	}
+	type UpstreamConfig struct {
+		Upstreams []Upstream `toml:"upstreams" env:"UPSTREAMS"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "sliceduration"
+	t.Setenv("SLICEDURATION_UPSTREAMS_0_NAME", "auth")
+	t.Setenv("SLICEDURATION_UPSTREAMS_0_TIMEOUT", "1m30s")
+	t.Setenv("SLICEDURATION_UPSTREAMS_0_BACKOFF", "100ms,1s")
+	t.Setenv("SLICEDURATION_UPSTREAMS_0_SINCE", "2024-03-15")
+	t.Setenv("SLICEDURATION_UPSTREAMS_1_NAME", "billing")
+
+	if err := LoadConfig[UpstreamConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[UpstreamConfig]()
+	if len(cfg.Upstreams) != 2 {
+		t.Fatalf("expected 2 upstreams, got %d", len(cfg.Upstreams))
+	}
+	first := cfg.Upstreams[0]
+	if first.Timeout != 90*time.Second {
+		t.Errorf("expected timeout 1m30s, got %v", first.Timeout)
+	}
+	if !reflect.DeepEqual(first.Backoff, []time.Duration{100 * time.Millisecond, time.Second}) {
+		t.Errorf("expected backoff [100ms 1s], got %v", first.Backoff)
+	}
+	if !first.Since.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected since 2024-03-15, got %v", first.Since)
+	}
+	if cfg.Upstreams[1].Timeout != 5*time.Second {
+		t.Errorf("expected the default timeout 5s for the second upstream, got %v", cfg.Upstreams[1].Timeout)
+	}
+
+	t.Setenv("SLICEDURATION_UPSTREAMS_1_TIMEOUT", "soon")
+	if _, err := loadStructSliceEnv(lookupEnv, "SLICEDURATION_UPSTREAMS", "upstreams", reflect.TypeOf(Upstream{}), 0, 0); err == nil || !strings.Contains(err.Error(), "upstreams[1].timeout") {
+		t.Errorf("expected a duration parse error naming upstreams[1].timeout, got %v", err)
+	}
+}
+
+// TestStructSliceEnvCachedTags는 구조체 슬라이스 요소도 getCachedTypeInfo의 태그 정보(strict, format)를 사용하는지 테스트합니다
+func TestStructSliceEnvCachedTags(t *testing.T) {
+	type Route struct {
+		Path    string            `toml:"path" env:"PATH"`
+		Port    int               `toml:"port" env:"PORT" strict:"true"`
+		Headers map[string]string `toml:"headers" env:"HEADERS" format:"json"`
+	}
+
+	lookup := func(values map[string]string) envLookup {
+		return func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		}
+	}
+
+	result, err := loadStructSliceEnv(lookup(map[string]string{
+		"ROUTES_0_PATH":    "/api",
+		"ROUTES_0_PORT":    "8080",
+		"ROUTES_0_HEADERS": `{"X-Team": "core"}`,
+	}), "ROUTES", "routes", reflect.TypeOf(Route{}), 0, 0)
+	if err != nil {
+		t.Fatalf("loadStructSliceEnv failed: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected 1 route, got %d", len(result))
+	}
+	route := result[0].Interface().(Route)
+	if route.Port != 8080 || !reflect.DeepEqual(route.Headers, map[string]string{"X-Team": "core"}) {
+		t.Errorf("expected the port and the JSON headers of the element, got %+v", route)
+	}
+
+	strictEnvErrors = nil
+	defer func() { strictEnvErrors = nil }()
+	if _, err := loadStructSliceEnv(lookup(map[string]string{
+		"ROUTES_0_PATH": "/api",
+		"ROUTES_0_PORT": "",
+	}), "ROUTES", "routes", reflect.TypeOf(Route{}), 0, 0); err != nil {
+		t.Fatalf("loadStructSliceEnv failed: %v", err)
+	}
+	if len(strictEnvErrors) != 1 || !strings.Contains(strictEnvErrors[0].Error(), "routes[0].port") {
+		t.Errorf("expected a strict error for routes[0].port, got %v", strictEnvErrors)
+	}
+}
+
 // TestNestedStructDefaultValues는 중첩 구조체에서 기본값이 제대로 적용되는지 테스트합니다
 func TestNestedStructDefaultValues(t *testing.T) {
 	// 중첩 구조체에 기본값이 있는 테스트 구조체
@@ -690,3 +822,2098 @@ func TestNestedStructWithPartialEnvVars(t *testing.T) {
 		t.Errorf("expected database timeout to be 30 (default), got %d", cfg.Database.Timeout)
 	}
 }
+
+// TestParseEnvValueNumbers는 숫자 파싱의 허용 형식과 거부 형식을 테스트합니다
+func TestParseEnvValueNumbers(t *testing.T) {
+	intType := reflect.TypeOf(int(0))
+	int64Type := reflect.TypeOf(int64(0))
+	floatType := reflect.TypeOf(float64(0))
+
+	tests := []struct {
+		name     string
+		input    string
+		typ      reflect.Type
+		expected interface{}
+	}{
+		{"leading plus", "+8080", intType, 8080},
+		{"underscore separator", "1_000", intType, 1000},
+		{"underscore separator int64", "1_000_000", int64Type, int64(1000000)},
+		{"negative", "-42", intType, -42},
+		{"float leading plus", "+1.5", floatType, 1.5},
+		{"float underscore separator", "1_000.25", floatType, 1000.25},
+		{"hexadecimal", "0xFF", intType, 255},
+		{"octal", "0o755", intType, 493},
+		{"binary", "0b1010", intType, 10},
+		{"decimal", "42", intType, 42},
+		{"leading zero stays decimal", "0755", intType, 755},
+		{"negative hexadecimal", "-0x10", int64Type, int64(-16)},
+		{"hexadecimal with separator", "0xFF_FF", intType, 65535},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvValue(tt.input, tt.typ)
+			if err != nil {
+				t.Fatalf("parseEnvValue(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseEnvValue(%q) = %#v, expected %#v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("thousands separator is rejected", func(t *testing.T) {
+		_, err := parseEnvValue("1,000", intType)
+		if err == nil {
+			t.Fatal("expected an error for '1,000', but got nil")
+		}
+		if !strings.Contains(err.Error(), "thousands separators are not supported") {
+			t.Errorf("expected a thousands separator error, got '%v'", err)
+		}
+	})
+
+	t.Run("invalid digits for the base are rejected", func(t *testing.T) {
+		if _, err := parseEnvValue("0o789", intType); err == nil {
+			t.Error("expected an error for '0o789', but got nil")
+		}
+	})
+
+	t.Run("misplaced underscore is rejected", func(t *testing.T) {
+		if _, err := parseEnvValue("_100", intType); err == nil {
+			t.Error("expected an error for '_100', but got nil")
+		}
+		if _, err := parseEnvValue("100_", intType); err == nil {
+			t.Error("expected an error for '100_', but got nil")
+		}
+	})
+}
+
+// TestGetConfigValue는 GetConfigValue가 공유 인스턴스와 분리된 복사본을 반환하는지 테스트합니다
+func TestGetConfigValue(t *testing.T) {
+	resetGlobalConfig()
+	AppName = "TESTVALUE"
+	t.Setenv("TESTVALUE_SERVER_HOST", "envhost")
+	t.Setenv("TESTVALUE_DATABASE_USER", "envuser")
+
+	if err := LoadConfig[TestConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	cfg, err := GetConfigValue[TestConfig]()
+	if err != nil {
+		t.Fatalf("GetConfigValue failed: %v", err)
+	}
+	if cfg.Server.Host != "envhost" {
+		t.Errorf("expected server host to be 'envhost', got '%s'", cfg.Server.Host)
+	}
+
+	// 복사본을 변경해도 공유 인스턴스에는 영향이 없어야 한다
+	cfg.Server.Host = "mutated"
+	cfg.Server.Port = 1
+	cfg.Enabled = true
+
+	shared := GetConfig[TestConfig]()
+	if shared.Server.Host != "envhost" {
+		t.Errorf("expected shared server host to stay 'envhost', got '%s'", shared.Server.Host)
+	}
+	if shared.Server.Port != 8080 {
+		t.Errorf("expected shared server port to stay 8080, got %d", shared.Server.Port)
+	}
+	if shared.Enabled {
+		t.Errorf("expected shared enabled to stay false, got true")
+	}
+
+	t.Run("not initialized", func(t *testing.T) {
+		resetGlobalConfig()
+		if _, err := GetConfigValue[TestConfig](); err == nil {
+			t.Error("expected an error for uninitialized config, but got nil")
+		}
+	})
+}
+
+// TestTOMLEnvInterpolation은 TOML 값 안의 ${VAR} 참조가 배열과 맵 요소까지 확장되는지 테스트합니다
+func TestTOMLEnvInterpolation(t *testing.T) {
+	type InterpolationConfig struct {
+		Database struct {
+			User  string   `toml:"user" env:"USER"`
+			Hosts []string `toml:"hosts" env:"HOSTS"`
+		} `toml:"database" env:"DATABASE"`
+		Labels map[string]string `toml:"labels"`
+	}
+
+	resetGlobalConfig()
+	appName := "interpapp"
+	tomlContent := `
+labels = { region = "${REGION}", static = "fixed" }
+
+[database]
+user = "${DB_USER}"
+hosts = ["${PRIMARY_DB}", "${REPLICA_DB}"]
+`
+	_, cleanup := createTestTomlFile(t, appName, tomlContent)
+	defer cleanup()
+
+	t.Setenv("PRIMARY_DB", "primary.example.com")
+	t.Setenv("REPLICA_DB", "replica.example.com")
+	t.Setenv("DB_USER", "admin")
+	t.Setenv("REGION", "eu-west-1")
+
+	AppName = appName
+	if err := LoadConfig[InterpolationConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	cfg := GetConfig[InterpolationConfig]()
+
+	expectedHosts := []string{"primary.example.com", "replica.example.com"}
+	if !reflect.DeepEqual(cfg.Database.Hosts, expectedHosts) {
+		t.Errorf("expected db hosts to be %v, got %v", expectedHosts, cfg.Database.Hosts)
+	}
+	if cfg.Database.User != "admin" {
+		t.Errorf("expected db user to be 'admin', got '%s'", cfg.Database.User)
+	}
+	if cfg.Labels["region"] != "eu-west-1" {
+		t.Errorf("expected region label to be 'eu-west-1', got '%s'", cfg.Labels["region"])
+	}
+	if cfg.Labels["static"] != "fixed" {
+		t.Errorf("expected static label to be 'fixed', got '%s'", cfg.Labels["static"])
+	}
+}
+
+// TestParseSliceValueQuoted는 따옴표로 감싼 요소에 쉼표가 포함될 수 있는지 테스트합니다
+func TestParseSliceValueQuoted(t *testing.T) {
+	stringSlice := reflect.TypeOf([]string{})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"quoted element with comma", `"a,b",c`, []string{"a,b", "c"}},
+		{"quoted elements with spaces", `"a,b", "c,d", e`, []string{"a,b", "c,d", "e"}},
+		{"escaped quote", `"say ""hi"", bob",x`, []string{`say "hi", bob`, "x"}},
+		{"unquoted falls back to split", "a, b,c", []string{"a", "b", "c"}},
+		{"quote inside an unquoted element", `say "hi",x`, []string{`say "hi"`, "x"}},
+		{"line break inside quotes", "\"a\nb\",c", []string{"a\nb", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvValue(tt.input, stringSlice)
+			if err != nil {
+				t.Fatalf("parseEnvValue(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseEnvValue(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("unterminated quote is rejected", func(t *testing.T) {
+		if _, err := parseEnvValue(`"a,b`, stringSlice); err == nil {
+			t.Error("expected an error for an unterminated quote, but got nil")
+		}
+	})
+
+	t.Run("line break outside quotes is rejected", func(t *testing.T) {
+		if _, err := parseEnvValue("\"a\",b\nc,d", stringSlice); err == nil || !strings.Contains(err.Error(), "line breaks") {
+			t.Errorf("expected an error for a second record, got %v", err)
+		}
+	})
+
+	t.Run("quoted elements from env", func(t *testing.T) {
+		type QuotedConfig struct {
+			Items []string `env:"ITEMS"`
+		}
+
+		resetGlobalConfig()
+		AppName = "APP"
+		t.Setenv("APP_ITEMS", `"a,b",c`)
+
+		if err := LoadConfig[QuotedConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[QuotedConfig]()
+		expected := []string{"a,b", "c"}
+		if !reflect.DeepEqual(cfg.Items, expected) {
+			t.Errorf("expected items to be %q, got %q", expected, cfg.Items)
+		}
+	})
+}
+
+// TestSecretParseErrorRedaction은 시크릿 필드의 파싱 오류에 원본 값이 노출되지 않는지 테스트합니다
+func TestSecretParseErrorRedaction(t *testing.T) {
+	type SecretConfig struct {
+		Vault struct {
+			Pin    int      `env:"PIN" secret:"true"`
+			Codes  []int    `env:"CODES" secret:"true"`
+			Port   int      `env:"PORT"`
+			Tokens []string `env:"TOKENS" secret:"true"`
+		} `env:"VAULT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+
+	t.Run("secret scalar field", func(t *testing.T) {
+		AppName = "SECRETAPP"
+		t.Setenv("SECRETAPP_VAULT_PIN", "hunter2")
+
+		// LoadConfig only logs env errors, so call the env loader directly
+		err := loadConfigEnv(new(SecretConfig))
+		if err == nil {
+			t.Fatal("expected a parse error, but got nil")
+		}
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("expected secret value to be redacted, got '%v'", err)
+		}
+		if !strings.Contains(err.Error(), "vault.pin") || !strings.Contains(err.Error(), "****") {
+			t.Errorf("expected error to name the field and mask the value, got '%v'", err)
+		}
+	})
+
+	t.Run("secret slice field", func(t *testing.T) {
+		AppName = "SECRETAPP"
+		t.Setenv("SECRETAPP_VAULT_CODES", "1,s3cr3t")
+
+		// LoadConfig only logs env errors, so call the env loader directly
+		err := loadConfigEnv(new(SecretConfig))
+		if err == nil {
+			t.Fatal("expected a parse error, but got nil")
+		}
+		if strings.Contains(err.Error(), "s3cr3t") {
+			t.Errorf("expected secret value to be redacted, got '%v'", err)
+		}
+	})
+
+	t.Run("non-secret field keeps the value", func(t *testing.T) {
+		AppName = "SECRETAPP"
+		t.Setenv("SECRETAPP_VAULT_PORT", "eighty")
+
+		// LoadConfig only logs env errors, so call the env loader directly
+		err := loadConfigEnv(new(SecretConfig))
+		if err == nil {
+			t.Fatal("expected a parse error, but got nil")
+		}
+		if !strings.Contains(err.Error(), "eighty") {
+			t.Errorf("expected non-secret value in error, got '%v'", err)
+		}
+	})
+}
+
+// TestParseErrorFieldPath는 파싱 오류가 설정 루트부터의 필드 경로를 포함하는지 테스트합니다
+func TestParseErrorFieldPath(t *testing.T) {
+	type PathConfig struct {
+		Server struct {
+			Port int `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+			Age  int    `toml:"age" env:"AGE"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "PATHAPP"
+
+	t.Run("nested struct field", func(t *testing.T) {
+		t.Setenv("PATHAPP_SERVER_PORT", "eighty")
+
+		err := loadConfigEnv(new(PathConfig))
+		if err == nil {
+			t.Fatal("expected a parse error, but got nil")
+		}
+		expectedError := "failed to parse env value for field server.port"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("expected error to contain '%s', got '%v'", expectedError, err)
+		}
+	})
+
+	t.Run("struct slice element field", func(t *testing.T) {
+		t.Setenv("PATHAPP_USERS_0_NAME", "alice")
+		t.Setenv("PATHAPP_USERS_1_NAME", "bob")
+		t.Setenv("PATHAPP_USERS_1_AGE", "old")
+
+		err := loadConfigEnv(new(PathConfig))
+		if err == nil {
+			t.Fatal("expected a parse error, but got nil")
+		}
+		expectedError := "failed to parse env value for field users[1].age"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("expected error to contain '%s', got '%v'", expectedError, err)
+		}
+	})
+
+	t.Run("valid values still load", func(t *testing.T) {
+		t.Setenv("PATHAPP_SERVER_PORT", "8080")
+		t.Setenv("PATHAPP_USERS_0_NAME", "alice")
+		t.Setenv("PATHAPP_USERS_0_AGE", "30")
+
+		cfg := new(PathConfig)
+		if err := loadConfigEnv(cfg); err != nil {
+			t.Fatalf("loadConfigEnv failed: %v", err)
+		}
+		if cfg.Server.Port != 8080 {
+			t.Errorf("expected server port to be 8080, got %d", cfg.Server.Port)
+		}
+		if len(cfg.Users) != 1 || cfg.Users[0].Name != "alice" || cfg.Users[0].Age != 30 {
+			t.Errorf("unexpected users: %+v", cfg.Users)
+		}
+	})
+}
+
+// TestFixedSizeArrays는 고정 길이 배열 필드를 환경변수와 TOML에서 로드하는지 테스트합니다
+func TestFixedSizeArrays(t *testing.T) {
+	type ArrayConfig struct {
+		Grid struct {
+			Coords [3]int     `toml:"coords" env:"COORDS" required:"true"`
+			Scale  [2]float64 `toml:"scale" env:"SCALE"`
+		} `toml:"grid" env:"GRID"`
+	}
+
+	t.Run("from env", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "ARRAYAPP"
+		t.Setenv("ARRAYAPP_GRID_COORDS", "1, 2, 3")
+		t.Setenv("ARRAYAPP_GRID_SCALE", "0.5,1.5")
+
+		if err := LoadConfig[ArrayConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[ArrayConfig]()
+		if cfg.Grid.Coords != [3]int{1, 2, 3} {
+			t.Errorf("expected coords to be [1 2 3], got %v", cfg.Grid.Coords)
+		}
+		if cfg.Grid.Scale != [2]float64{0.5, 1.5} {
+			t.Errorf("expected scale to be [0.5 1.5], got %v", cfg.Grid.Scale)
+		}
+	})
+
+	t.Run("from TOML", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "arrayapp"
+		_, cleanup := createTestTomlFile(t, appName, "[grid]\ncoords = [4, 5, 6]\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[ArrayConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[ArrayConfig]()
+		if cfg.Grid.Coords != [3]int{4, 5, 6} {
+			t.Errorf("expected coords to be [4 5 6], got %v", cfg.Grid.Coords)
+		}
+	})
+
+	t.Run("wrong length from env", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "ARRAYAPP"
+		t.Setenv("ARRAYAPP_GRID_COORDS", "1,2")
+
+		err := loadConfigEnv(new(ArrayConfig))
+		if err == nil {
+			t.Fatal("expected an error for a wrong element count, but got nil")
+		}
+		if !strings.Contains(err.Error(), "expected 3 elements") {
+			t.Errorf("expected an element count error, got '%v'", err)
+		}
+	})
+
+	t.Run("wrong length from TOML", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "arrayapp"
+		_, cleanup := createTestTomlFile(t, appName, "[grid]\ncoords = [1, 2]\n")
+		defer cleanup()
+
+		AppName = appName
+		err := loadConfigFile(new(ArrayConfig))
+		if err == nil {
+			t.Fatal("expected an error for a wrong element count, but got nil")
+		}
+		if !strings.Contains(err.Error(), "grid.coords expects 3 elements") {
+			t.Errorf("expected an element count error, got '%v'", err)
+		}
+	})
+
+	t.Run("all-zero required array is missing", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "ARRAYAPP"
+
+		err := LoadConfig[ArrayConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a missing required array, but got nil")
+		}
+		if !strings.Contains(err.Error(), "required field 'COORDS' is missing or empty") {
+			t.Errorf("expected a required field error, got '%v'", err)
+		}
+	})
+}
+
+// TestTransformTags는 transform 태그가 로드 후 문자열 필드에 순서대로 적용되는지 테스트합니다
+func TestTransformTags(t *testing.T) {
+	type TransformConfig struct {
+		Auth struct {
+			Role   string   `toml:"role" env:"ROLE" transform:"trimspace,lower"`
+			Region string   `toml:"region" env:"REGION" transform:"upper"`
+			Owner  string   `toml:"owner" env:"OWNER" transform:"trimspace, title"`
+			Scopes []string `toml:"scopes" env:"SCOPES" transform:"lower"`
+		} `toml:"auth" env:"AUTH"`
+	}
+
+	t.Run("from env", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "TRANSFORMAPP"
+		t.Setenv("TRANSFORMAPP_AUTH_ROLE", "  ADMIN ")
+		t.Setenv("TRANSFORMAPP_AUTH_REGION", "eu-west")
+		t.Setenv("TRANSFORMAPP_AUTH_OWNER", " jane doe ")
+		t.Setenv("TRANSFORMAPP_AUTH_SCOPES", "READ,Write")
+
+		if err := LoadConfig[TransformConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[TransformConfig]()
+		if cfg.Auth.Role != "admin" {
+			t.Errorf("expected role to be 'admin', got '%s'", cfg.Auth.Role)
+		}
+		if cfg.Auth.Region != "EU-WEST" {
+			t.Errorf("expected region to be 'EU-WEST', got '%s'", cfg.Auth.Region)
+		}
+		if cfg.Auth.Owner != "Jane Doe" {
+			t.Errorf("expected owner to be 'Jane Doe', got '%s'", cfg.Auth.Owner)
+		}
+		expectedScopes := []string{"read", "write"}
+		if !reflect.DeepEqual(cfg.Auth.Scopes, expectedScopes) {
+			t.Errorf("expected scopes to be %v, got %v", expectedScopes, cfg.Auth.Scopes)
+		}
+	})
+
+	t.Run("from TOML", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "transformapp"
+		_, cleanup := createTestTomlFile(t, appName, "[auth]\nrole = \"  ADMIN \"\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[TransformConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[TransformConfig]()
+		if cfg.Auth.Role != "admin" {
+			t.Errorf("expected role to be 'admin', got '%s'", cfg.Auth.Role)
+		}
+	})
+
+	t.Run("unknown transform", func(t *testing.T) {
+		type BadTransformConfig struct {
+			Name string `env:"NAME" transform:"reverse"`
+		}
+
+		resetGlobalConfig()
+		AppName = "TRANSFORMAPP"
+
+		err := LoadConfig[BadTransformConfig]()
+		if err == nil {
+			t.Fatal("expected an error for an unknown transform, but got nil")
+		}
+		if !strings.Contains(err.Error(), "unknown transform 'reverse'") {
+			t.Errorf("expected an unknown transform error, got '%v'", err)
+		}
+	})
+}
+
+// TestDefaultFieldReferences는 다른 필드를 참조하는 기본값이 최종 값으로 해석되는지 테스트합니다
+func TestDefaultFieldReferences(t *testing.T) {
+	type RefConfig struct {
+		Server struct {
+			Host          string `toml:"host" env:"HOST" default:"localhost"`
+			Port          int    `toml:"port" env:"PORT" default:"8080"`
+			AdvertiseHost string `toml:"advertise_host" env:"ADVERTISE_HOST" default:"{Host}"`
+			MetricsAddr   string `toml:"metrics_addr" env:"METRICS_ADDR" default:"{Host}:{Port}"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	t.Run("copies loaded sibling values", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "refapp"
+		_, cleanup := createTestTomlFile(t, appName, "[server]\nport = 9000\n")
+		defer cleanup()
+
+		AppName = appName
+		t.Setenv("REFAPP_SERVER_HOST", "api.example.com")
+
+		if err := LoadConfig[RefConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[RefConfig]()
+		if cfg.Server.AdvertiseHost != "api.example.com" {
+			t.Errorf("expected advertise host to copy host 'api.example.com', got '%s'", cfg.Server.AdvertiseHost)
+		}
+		if cfg.Server.MetricsAddr != "api.example.com:9000" {
+			t.Errorf("expected metrics addr to be 'api.example.com:9000', got '%s'", cfg.Server.MetricsAddr)
+		}
+	})
+
+	t.Run("explicit value wins over reference", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "REFAPP"
+		t.Setenv("REFAPP_SERVER_ADVERTISE_HOST", "public.example.com")
+
+		if err := LoadConfig[RefConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[RefConfig]()
+		if cfg.Server.AdvertiseHost != "public.example.com" {
+			t.Errorf("expected advertise host to be 'public.example.com', got '%s'", cfg.Server.AdvertiseHost)
+		}
+		if cfg.Server.MetricsAddr != "localhost:8080" {
+			t.Errorf("expected metrics addr to use defaults 'localhost:8080', got '%s'", cfg.Server.MetricsAddr)
+		}
+	})
+
+	t.Run("cycle is reported", func(t *testing.T) {
+		type CycleConfig struct {
+			A string `env:"A" default:"{B}"`
+			B string `env:"B" default:"{A}"`
+		}
+
+		resetGlobalConfig()
+		AppName = "REFAPP"
+
+		err := LoadConfig[CycleConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a reference cycle, but got nil")
+		}
+		if !strings.Contains(err.Error(), "cycle") {
+			t.Errorf("expected a cycle error, got '%v'", err)
+		}
+	})
+}
+
+// TestOptionalSection은 선택적 섹션의 필수 필드가 섹션이 주어졌을 때만 검사되는지 테스트합니다
+func TestOptionalSection(t *testing.T) {
+	type OptionalConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+		} `toml:"server" env:"SERVER"`
+		TLS struct {
+			Cert    string `toml:"cert" env:"CERT" required:"true"`
+			Key     string `toml:"key" env:"KEY" required:"true"`
+			MinVers string `toml:"min_version" env:"MIN_VERSION" default:"1.2"`
+		} `toml:"tls" env:"TLS" optional:"true"`
+	}
+
+	t.Run("omitted section passes", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "optionalapp"
+		_, cleanup := createTestTomlFile(t, appName, "[server]\nhost = \"localhost\"\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[OptionalConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[OptionalConfig]()
+		if cfg.TLS.MinVers != "1.2" {
+			t.Errorf("expected tls min version default '1.2', got '%s'", cfg.TLS.MinVers)
+		}
+	})
+
+	t.Run("partially provided section fails", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "optionalapp"
+		_, cleanup := createTestTomlFile(t, appName, "[server]\nhost = \"localhost\"\n[tls]\ncert = \"/etc/tls/cert.pem\"\n")
+		defer cleanup()
+
+		AppName = appName
+		err := LoadConfig[OptionalConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a missing required field in a present section, but got nil")
+		}
+		expectedError := "required field 'KEY' is missing or empty"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("expected error to contain '%s', got '%v'", expectedError, err)
+		}
+	})
+
+	t.Run("section provided through env fails", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "OPTIONALAPP"
+		t.Setenv("OPTIONALAPP_SERVER_HOST", "localhost")
+		t.Setenv("OPTIONALAPP_TLS_KEY", "/etc/tls/key.pem")
+
+		err := LoadConfig[OptionalConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a missing required field in a present section, but got nil")
+		}
+		expectedError := "required field 'CERT' is missing or empty"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("expected error to contain '%s', got '%v'", expectedError, err)
+		}
+	})
+}
+
+// TestInlineTableMaps는 TOML 인라인 테이블이 맵 필드로 로드되고 검증과 마스킹이 맵을 순회하는지 테스트합니다
+func TestInlineTableMaps(t *testing.T) {
+	type Backend struct {
+		Host     string `toml:"host" required:"true"`
+		Password string `toml:"password" secret:"true"`
+	}
+	type MapConfig struct {
+		Limits   map[string]string  `toml:"limits"`
+		Backends map[string]Backend `toml:"backends"`
+	}
+
+	t.Run("inline tables populate maps", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "mapapp"
+		tomlContent := `
+limits = { cpu = "2", memory = "1Gi" }
+backends = { primary = { host = "db1", password = "hunter2" } }
+`
+		_, cleanup := createTestTomlFile(t, appName, tomlContent)
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[MapConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[MapConfig]()
+		expectedLimits := map[string]string{"cpu": "2", "memory": "1Gi"}
+		if !reflect.DeepEqual(cfg.Limits, expectedLimits) {
+			t.Errorf("expected limits to be %v, got %v", expectedLimits, cfg.Limits)
+		}
+		if cfg.Backends["primary"].Host != "db1" {
+			t.Errorf("expected primary backend host to be 'db1', got '%s'", cfg.Backends["primary"].Host)
+		}
+
+		masked := maskedFields(maskSecrets(cfg))
+		backends := masked["Backends"].(map[string]interface{})
+		primary := maskedFields(backends["primary"])
+		if primary["Password"] != "****" {
+			t.Errorf("expected backend password to be masked, got %v", primary["Password"])
+		}
+		limits := masked["Limits"].(map[string]interface{})
+		if limits["cpu"] != "2" {
+			t.Errorf("expected cpu limit to be '2' in masked output, got %v", limits["cpu"])
+		}
+	})
+
+	t.Run("required fields inside map values are validated", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "mapapp"
+		_, cleanup := createTestTomlFile(t, appName, "backends = { primary = { password = \"hunter2\" } }\n")
+		defer cleanup()
+
+		AppName = appName
+		err := LoadConfig[MapConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a missing required field in a map value, but got nil")
+		}
+		expectedError := "required field 'Host' is missing or empty"
+		if !strings.Contains(err.Error(), expectedError) {
+			t.Errorf("expected error to contain '%s', got '%v'", expectedError, err)
+		}
+	})
+}
+
+// TestParseNegativeValues는 음수 정수, 실수, 기간 값이 단일 값과 슬라이스에서 모두 올바르게 파싱되는지 테스트합니다
+func TestParseNegativeValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		typ      reflect.Type
+		expected interface{}
+	}{
+		{"negative int", "-1", reflect.TypeOf(int(0)), -1},
+		{"negative int32", "-2147483648", reflect.TypeOf(int32(0)), int32(-2147483648)},
+		{"negative float", "-0.5", reflect.TypeOf(float64(0)), -0.5},
+		{"negative duration", "-5m", reflect.TypeOf(time.Duration(0)), -5 * time.Minute},
+		{"positive duration", "1h30m", reflect.TypeOf(time.Duration(0)), 90 * time.Minute},
+		{"negative int slice", "-1,-2", reflect.TypeOf([]int{}), []int{-1, -2}},
+		{"negative int slice with spaces", " -1 , -2 ", reflect.TypeOf([]int{}), []int{-1, -2}},
+		{"negative float slice", "-1.5,2.5,-3", reflect.TypeOf([]float64{}), []float64{-1.5, 2.5, -3}},
+		{"negative duration slice", "-5m,10s", reflect.TypeOf([]time.Duration{}), []time.Duration{-5 * time.Minute, 10 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEnvValue(tt.input, tt.typ)
+			if err != nil {
+				t.Fatalf("parseEnvValue(%q) failed: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseEnvValue(%q) = %#v, expected %#v", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("lone minus is rejected", func(t *testing.T) {
+		if _, err := parseEnvValue("-", reflect.TypeOf(int(0))); err == nil {
+			t.Error("expected an error for '-', but got nil")
+		}
+		if _, err := parseEnvValue("1,-", reflect.TypeOf([]int{})); err == nil {
+			t.Error("expected an error for '1,-', but got nil")
+		}
+	})
+
+	t.Run("negative values from env and TOML", func(t *testing.T) {
+		type NegativeConfig struct {
+			Offset  int           `toml:"offset" env:"OFFSET"`
+			Skew    time.Duration `toml:"skew" env:"SKEW"`
+			Weights []float64     `toml:"weights" env:"WEIGHTS"`
+		}
+
+		resetGlobalConfig()
+		appName := "negapp"
+		_, cleanup := createTestTomlFile(t, appName, "offset = -10\nskew = \"-30s\"\n")
+		defer cleanup()
+
+		AppName = appName
+		t.Setenv("NEGAPP_WEIGHTS", "-0.25,0.75")
+
+		if err := LoadConfig[NegativeConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[NegativeConfig]()
+		if cfg.Offset != -10 {
+			t.Errorf("expected offset to be -10, got %d", cfg.Offset)
+		}
+		if cfg.Skew != -30*time.Second {
+			t.Errorf("expected skew to be -30s, got %v", cfg.Skew)
+		}
+		if !reflect.DeepEqual(cfg.Weights, []float64{-0.25, 0.75}) {
+			t.Errorf("expected weights to be [-0.25 0.75], got %v", cfg.Weights)
+		}
+	})
+}
+
+// TestEnvAbsTag는 envabs 태그가 접두사 없이 지정된 환경변수를 읽는지 테스트합니다
+func TestEnvAbsTag(t *testing.T) {
+	type EnvAbsConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT" envabs:"PORT"`
+		} `toml:"server" env:"SERVER"`
+		DatabaseURL string `toml:"database_url" envabs:"DATABASE_URL"`
+	}
+
+	resetGlobalConfig()
+	AppName = "ENVABS"
+	t.Setenv("PORT", "9090")
+	t.Setenv("ENVABS_SERVER_PORT", "1234")
+	t.Setenv("ENVABS_SERVER_HOST", "localhost")
+	t.Setenv("DATABASE_URL", "postgres://db/app")
+
+	cfg := new(EnvAbsConfig)
+	if err := loadConfigEnv(cfg); err != nil {
+		t.Fatalf("loadConfigEnv failed: %v", err)
+	}
+
+	if cfg.Server.Port != 9090 {
+		t.Errorf("expected port 9090 from PORT, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.Host != "localhost" {
+		t.Errorf("expected host 'localhost', got %q", cfg.Server.Host)
+	}
+	if cfg.DatabaseURL != "postgres://db/app" {
+		t.Errorf("expected database URL from DATABASE_URL, got %q", cfg.DatabaseURL)
+	}
+}
+
+// TestEnvAbsTagSectionDetection는 envabs 환경변수만 있어도 중첩 섹션이 로드되는지 테스트합니다
+func TestEnvAbsTagSectionDetection(t *testing.T) {
+	type EnvAbsSectionConfig struct {
+		Server struct {
+			Port int `toml:"port" envabs:"PORT" required:"true"`
+		} `toml:"server"`
+	}
+
+	resetGlobalConfig()
+	AppName = "ENVABSSECTION"
+	t.Setenv("PORT", "8081")
+
+	cfg := new(EnvAbsSectionConfig)
+	if err := loadConfigEnv(cfg); err != nil {
+		t.Fatalf("loadConfigEnv failed: %v", err)
+	}
+	if cfg.Server.Port != 8081 {
+		t.Errorf("expected port 8081, got %d", cfg.Server.Port)
+	}
+}
+
+// TestLoadConfigExecutablePathFailure는 실행 파일 경로를 얻지 못해도 환경변수로 로드가 계속되는지 테스트합니다
+func TestLoadConfigExecutablePathFailure(t *testing.T) {
+	type ExeFailConfig struct {
+		Host string `toml:"host" env:"HOST" required:"true"`
+	}
+
+	resetGlobalConfig()
+	AppName = "EXEFAIL"
+	t.Setenv("EXEFAIL_HOST", "localhost")
+
+	originalExecutablePath := executablePath
+	executablePath = func() (string, error) {
+		return "", errors.New("executable path unavailable")
+	}
+	defer func() { executablePath = originalExecutablePath }()
+
+	cfg := new(ExeFailConfig)
+	if err := loadConfigFile(cfg); err != nil {
+		t.Fatalf("expected executable path failure to be non-fatal, got %v", err)
+	}
+
+	if err := LoadConfig[ExeFailConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := GetConfig[ExeFailConfig]().Host; got != "localhost" {
+		t.Errorf("expected host 'localhost' from env, got %q", got)
+	}
+}
+
+// setSearchDirs는 설정 파일 탐색에 사용할 작업 디렉토리와 실행 파일 디렉토리를 지정합니다
+func setSearchDirs(t *testing.T, wd, exeDir string) {
+	t.Helper()
+
+	originalWorkingDir, originalExecutablePath := workingDir, executablePath
+	workingDir = func() (string, error) { return wd, nil }
+	executablePath = func() (string, error) { return filepath.Join(exeDir, "app"), nil }
+	t.Cleanup(func() {
+		workingDir, executablePath = originalWorkingDir, originalExecutablePath
+	})
+}
+
+// TestLoadConfigFileSearchPath는 작업 디렉토리, 실행 파일 디렉토리 순서로 설정 파일을 찾는지 테스트합니다
+func TestLoadConfigFileSearchPath(t *testing.T) {
+	type SearchConfig struct {
+		Host string `toml:"host"`
+	}
+
+	writeConfig := func(t *testing.T, dir, host string) {
+		t.Helper()
+		content := "host = \"" + host + "\"\n"
+		if err := os.WriteFile(filepath.Join(dir, "searchapp.toml"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	t.Run("found in working directory", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "searchapp"
+		wd, exeDir := t.TempDir(), t.TempDir()
+		writeConfig(t, wd, "from-wd")
+		writeConfig(t, exeDir, "from-exe")
+		setSearchDirs(t, wd, exeDir)
+
+		cfg := new(SearchConfig)
+		if err := loadConfigFile(cfg); err != nil {
+			t.Fatalf("loadConfigFile failed: %v", err)
+		}
+		if cfg.Host != "from-wd" {
+			t.Errorf("expected working directory config to win, got %q", cfg.Host)
+		}
+	})
+
+	t.Run("found in executable directory", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "searchapp"
+		wd, exeDir := t.TempDir(), t.TempDir()
+		writeConfig(t, exeDir, "from-exe")
+		setSearchDirs(t, wd, exeDir)
+
+		cfg := new(SearchConfig)
+		if err := loadConfigFile(cfg); err != nil {
+			t.Fatalf("loadConfigFile failed: %v", err)
+		}
+		if cfg.Host != "from-exe" {
+			t.Errorf("expected executable directory config, got %q", cfg.Host)
+		}
+	})
+
+	t.Run("not found anywhere", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "searchapp"
+		setSearchDirs(t, t.TempDir(), t.TempDir())
+
+		cfg := new(SearchConfig)
+		if err := loadConfigFile(cfg); err != nil {
+			t.Fatalf("expected missing file to be ignored, got %v", err)
+		}
+		if cfg.Host != "" {
+			t.Errorf("expected empty host, got %q", cfg.Host)
+		}
+	})
+}
+
+// TestLoadConfigFileSymlinkedExecutable는 심볼릭 링크로 실행된 경우 실제 실행 파일 디렉토리의 설정 파일을 찾는지 테스트합니다
+func TestLoadConfigFileSymlinkedExecutable(t *testing.T) {
+	type SymlinkConfig struct {
+		Host string `toml:"host"`
+	}
+
+	setup := func(t *testing.T) (realDir, linkDir string) {
+		t.Helper()
+		resetGlobalConfig()
+		AppName = "symlinkapp"
+		realDir, linkDir = t.TempDir(), t.TempDir()
+		realExe := filepath.Join(realDir, "app.exe")
+		if err := os.WriteFile(realExe, nil, 0755); err != nil {
+			t.Fatalf("failed to create executable: %v", err)
+		}
+		linkExe := filepath.Join(linkDir, "app.exe")
+		if err := os.Symlink(realExe, linkExe); err != nil {
+			// Creating symlinks needs extra privileges on Windows
+			t.Skipf("symlinks not supported: %v", err)
+		}
+
+		originalWorkingDir, originalExecutablePath := workingDir, executablePath
+		workingDir = func() (string, error) { return t.TempDir(), nil }
+		executablePath = func() (string, error) { return linkExe, nil }
+		t.Cleanup(func() {
+			workingDir, executablePath = originalWorkingDir, originalExecutablePath
+		})
+		return realDir, linkDir
+	}
+
+	writeConfig := func(t *testing.T, dir, host string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "symlinkapp.toml"), []byte("host = \""+host+"\"\n"), 0644); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	t.Run("real binary directory wins", func(t *testing.T) {
+		realDir, linkDir := setup(t)
+		writeConfig(t, realDir, "from-real")
+		writeConfig(t, linkDir, "from-link")
+
+		cfg := new(SymlinkConfig)
+		if err := loadConfigFile(cfg); err != nil {
+			t.Fatalf("loadConfigFile failed: %v", err)
+		}
+		if cfg.Host != "from-real" {
+			t.Errorf("expected the config next to the real binary, got %q", cfg.Host)
+		}
+	})
+
+	t.Run("link directory as fallback", func(t *testing.T) {
+		_, linkDir := setup(t)
+		writeConfig(t, linkDir, "from-link")
+
+		cfg := new(SymlinkConfig)
+		if err := loadConfigFile(cfg); err != nil {
+			t.Fatalf("loadConfigFile failed: %v", err)
+		}
+		if cfg.Host != "from-link" {
+			t.Errorf("expected the config next to the link, got %q", cfg.Host)
+		}
+	})
+}
+
+// TestTruthyBoolTags는 required/secret 태그가 1, yes 같은 참 값도 인식하는지 테스트합니다
+func TestTruthyBoolTags(t *testing.T) {
+	type TruthyConfig struct {
+		Host     string `toml:"host" env:"HOST" required:"1"`
+		Password string `toml:"password" env:"PASSWORD" secret:"yes"`
+		Token    string `toml:"token" env:"TOKEN" secret:"On"`
+		Debug    string `toml:"debug" env:"DEBUG" secret:"no"`
+	}
+
+	cfg := &TruthyConfig{Password: "hunter2", Token: "abc", Debug: "visible"}
+	err := checkRequiredField(reflect.ValueOf(cfg).Elem())
+	if err == nil || !strings.Contains(err.Error(), "required field 'HOST' is missing or empty") {
+		t.Errorf("expected required:\"1\" to be enforced, got %v", err)
+	}
+
+	masked := maskedFields(maskSecrets(cfg))
+	if masked["Password"] != "****" {
+		t.Errorf("expected secret:\"yes\" to be masked, got %v", masked["Password"])
+	}
+	if masked["Token"] != "****" {
+		t.Errorf("expected secret:\"On\" to be masked, got %v", masked["Token"])
+	}
+	if masked["Debug"] != "visible" {
+		t.Errorf("expected secret:\"no\" to stay visible, got %v", masked["Debug"])
+	}
+}
+
+// TestMaskSecretsNilValues는 nil 슬라이스, nil 맵, nil 포인터 필드가 패닉 없이 일관된 형태로 마스킹 출력되는지 테스트합니다
+func TestMaskSecretsNilValues(t *testing.T) {
+	type Credentials struct {
+		User     string `toml:"user"`
+		Password string `toml:"password" secret:"true"`
+	}
+	type NilConfig struct {
+		Tags     []string          `toml:"tags"`
+		Labels   map[string]string `toml:"labels"`
+		Backup   *Credentials      `toml:"backup"`
+		Primary  *Credentials      `toml:"primary"`
+		Replicas []*Credentials    `toml:"replicas"`
+		Token    *string           `toml:"token" secret:"true"`
+		Keys     []string          `toml:"keys" secret:"true"`
+		Timeout  *int              `toml:"timeout"`
+	}
+
+	cfg := &NilConfig{
+		Primary:  &Credentials{User: "admin", Password: "hunter2"},
+		Replicas: []*Credentials{nil, {User: "reader", Password: "s3cret"}},
+		Keys:     []string{"k1"},
+	}
+
+	out, err := json.Marshal(maskSecrets(cfg))
+	if err != nil {
+		t.Fatalf("failed to marshal masked config: %v", err)
+	}
+	got := string(out)
+	for _, want := range []string{
+		`"Tags":[]`,
+		`"Labels":{}`,
+		`"Backup":null`,
+		`"Primary":{"User":"admin","Password":"****"}`,
+		`"Replicas":[null,{"User":"reader","Password":"****"}]`,
+		`"Token":"****"`,
+		`"Keys":"****"`,
+		`"Timeout":null`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in masked output, got %s", want, got)
+		}
+	}
+	if strings.Contains(got, "hunter2") || strings.Contains(got, "s3cret") || strings.Contains(got, "k1") {
+		t.Errorf("expected secrets behind pointers and in slices to be masked, got %s", got)
+	}
+
+	if masked := maskSecrets(nil); masked != nil {
+		t.Errorf("expected nil for a nil config, got %v", masked)
+	}
+}
+
+// TestRequiredFieldErrorPath는 TOML 테이블 배열 요소의 필수 필드 오류에 인덱스 경로가 포함되는지 테스트합니다
+func TestRequiredFieldErrorPath(t *testing.T) {
+	type PathUser struct {
+		Name string `toml:"name" env:"NAME"`
+		Role string `toml:"role" env:"ROLE" required:"true"`
+	}
+	type PathConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+		} `toml:"server" env:"SERVER"`
+		Users []PathUser `toml:"users" env:"USERS"`
+	}
+
+	resetGlobalConfig()
+	appName := "pathapp"
+	_, cleanup := createTestTomlFile(t, appName, `[server]
+host = "localhost"
+
+[[users]]
+name = "alice"
+role = "admin"
+
+[[users]]
+name = "bob"
+`)
+	defer cleanup()
+
+	AppName = appName
+	err := LoadConfig[PathConfig]()
+	if err == nil {
+		t.Fatal("expected an error for a missing required field in a table array, but got nil")
+	}
+	expectedError := "required field 'ROLE' is missing or empty at users[1].role"
+	if err.Error() != expectedError {
+		t.Errorf("expected error '%s', got '%v'", expectedError, err)
+	}
+
+	cfg := &PathConfig{}
+	err = checkRequiredField(reflect.ValueOf(cfg).Elem())
+	expectedError = "required field 'HOST' is missing or empty at server.host"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("expected error '%s', got '%v'", expectedError, err)
+	}
+}
+
+// TestByteSliceField는 []byte 필드가 쉼표로 나뉘지 않고 원본 바이트로 로드되는지 테스트합니다
+func TestByteSliceField(t *testing.T) {
+	type ByteConfig struct {
+		TLS struct {
+			Cert []byte `toml:"cert" env:"CERT"`
+		} `toml:"tls" env:"TLS"`
+		Key []byte `toml:"key" env:"KEY" encoding:"base64"`
+	}
+
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB,abc/def+ghi=\nxyz\n-----END CERTIFICATE-----\n"
+
+	t.Run("from env", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "BYTEAPP"
+		t.Setenv("BYTEAPP_TLS_CERT", pem)
+
+		cfg := new(ByteConfig)
+		if err := loadConfigEnv(cfg); err != nil {
+			t.Fatalf("loadConfigEnv failed: %v", err)
+		}
+		if string(cfg.TLS.Cert) != pem {
+			t.Errorf("expected PEM to be loaded intact, got %q", cfg.TLS.Cert)
+		}
+	})
+
+	t.Run("from TOML", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "bytetoml"
+		_, cleanup := createTestTomlFile(t, appName, "key = \"aGVsbG8=\"\n[tls]\ncert = \"\"\"\n"+pem+"\"\"\"\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[ByteConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[ByteConfig]()
+		if string(cfg.TLS.Cert) != pem {
+			t.Errorf("expected PEM to be loaded intact, got %q", cfg.TLS.Cert)
+		}
+		if string(cfg.Key) != "hello" {
+			t.Errorf("expected base64 key to be decoded, got %q", cfg.Key)
+		}
+	})
+}
+
+// TestMinElemsTag는 minelems 태그가 기본값만으로 이루어진 슬라이스 요소를 유지하는지 테스트합니다
+func TestMinElemsTag(t *testing.T) {
+	type Listener struct {
+		Host string `toml:"host" env:"HOST" default:"0.0.0.0"`
+		Port int    `toml:"port" env:"PORT" default:"8080"`
+	}
+	type MinElemsConfig struct {
+		Listeners []Listener `toml:"listeners" env:"LISTENERS" minelems:"1"`
+		Backends  []Listener `toml:"backends" env:"BACKENDS"`
+	}
+
+	t.Run("default-only element retained", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "MINELEMS"
+
+		cfg := new(MinElemsConfig)
+		if err := loadConfigEnv(cfg); err != nil {
+			t.Fatalf("loadConfigEnv failed: %v", err)
+		}
+		expected := []Listener{{Host: "0.0.0.0", Port: 8080}}
+		if !reflect.DeepEqual(cfg.Listeners, expected) {
+			t.Errorf("expected %+v, got %+v", expected, cfg.Listeners)
+		}
+		if len(cfg.Backends) != 0 {
+			t.Errorf("expected default-only backends to be dropped without minelems, got %+v", cfg.Backends)
+		}
+	})
+
+	t.Run("env elements beyond minimum", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "MINELEMS"
+		t.Setenv("MINELEMS_LISTENERS_0_PORT", "9000")
+		t.Setenv("MINELEMS_LISTENERS_1_HOST", "127.0.0.1")
+
+		cfg := new(MinElemsConfig)
+		if err := loadConfigEnv(cfg); err != nil {
+			t.Fatalf("loadConfigEnv failed: %v", err)
+		}
+		expected := []Listener{{Host: "0.0.0.0", Port: 9000}, {Host: "127.0.0.1", Port: 8080}}
+		if !reflect.DeepEqual(cfg.Listeners, expected) {
+			t.Errorf("expected %+v, got %+v", expected, cfg.Listeners)
+		}
+	})
+
+	t.Run("file slice takes precedence", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "minelemsfile"
+		_, cleanup := createTestTomlFile(t, appName, "[[listeners]]\nhost = \"10.0.0.1\"\nport = 7000\n[[listeners]]\nhost = \"10.0.0.2\"\nport = 7001\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[MinElemsConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		expected := []Listener{{Host: "10.0.0.1", Port: 7000}, {Host: "10.0.0.2", Port: 7001}}
+		if got := GetConfig[MinElemsConfig]().Listeners; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %+v, got %+v", expected, got)
+		}
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		type BadMinElemsConfig struct {
+			Server struct {
+				Listeners []Listener `toml:"listeners" env:"LISTENERS" minelems:"one"`
+			} `toml:"server" env:"SERVER"`
+		}
+
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "MINELEMSBAD"
+		t.Setenv("MINELEMSBAD_SERVER_LISTENERS_0_HOST", "10.0.0.1")
+		err := LoadConfig[BadMinElemsConfig]()
+		if err == nil || !strings.Contains(err.Error(), "invalid minelems tag 'one' on field server.listeners") {
+			t.Errorf("expected invalid minelems error, got %v", err)
+		}
+	})
+}
+
+// TestGetConfigPanicMessages는 GetConfig의 panic 메시지에 요청 타입과 저장된 타입 이름이 포함되는지 테스트합니다
+func TestGetConfigPanicMessages(t *testing.T) {
+	type PanicConfig struct {
+		Host string `env:"HOST"`
+	}
+	type OtherConfig struct {
+		Port int `env:"PORT"`
+	}
+
+	recoverMessage := func(fn func()) (msg string) {
+		defer func() {
+			msg, _ = recover().(string)
+		}()
+		fn()
+		return ""
+	}
+
+	resetGlobalConfig()
+	msg := recoverMessage(func() { GetConfig[PanicConfig]() })
+	if !strings.Contains(msg, "not initialized") || !strings.Contains(msg, "*ahatconfig.PanicConfig") {
+		t.Errorf("expected not-initialized panic naming the requested type, got %q", msg)
+	}
+
+	instance = &OtherConfig{}
+	msg = recoverMessage(func() { GetConfig[PanicConfig]() })
+	if !strings.Contains(msg, "requested *ahatconfig.PanicConfig") || !strings.Contains(msg, "loaded as *ahatconfig.OtherConfig") {
+		t.Errorf("expected type mismatch panic naming both types, got %q", msg)
+	}
+
+	if _, err := GetConfigSafe[PanicConfig](); err == nil || err.Error() != msg {
+		t.Errorf("expected GetConfigSafe to return the same message, got %v", err)
+	}
+}
+
+// TestExplicitZeroEnvOverridesTOML는 0이나 false로 명시된 환경변수가 TOML 값을 덮어쓰는지 테스트합니다
+func TestExplicitZeroEnvOverridesTOML(t *testing.T) {
+	type ZeroConfig struct {
+		Server struct {
+			Port    int     `toml:"port" env:"PORT"`
+			Enabled bool    `toml:"enabled" env:"ENABLED"`
+			Ratio   float64 `toml:"ratio" env:"RATIO"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	appName := "zeroapp"
+	_, cleanup := createTestTomlFile(t, appName, "[server]\nport = 8000\nenabled = true\nratio = 0.5\n")
+	defer cleanup()
+
+	t.Setenv("ZEROAPP_SERVER_PORT", "0")
+	t.Setenv("ZEROAPP_SERVER_ENABLED", "false")
+	t.Setenv("ZEROAPP_SERVER_RATIO", "0")
+
+	AppName = appName
+	if err := LoadConfig[ZeroConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	cfg := GetConfig[ZeroConfig]()
+	if cfg.Server.Port != 0 {
+		t.Errorf("expected env PORT=0 to override TOML port, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.Enabled {
+		t.Error("expected env ENABLED=false to override TOML enabled")
+	}
+	if cfg.Server.Ratio != 0 {
+		t.Errorf("expected env RATIO=0 to override TOML ratio, got %v", cfg.Server.Ratio)
+	}
+}
+
+// TestPointerStructSlice는 []*struct 슬라이스를 환경변수와 TOML에서 로드하고 검증하는지 테스트합니다
+func TestPointerStructSlice(t *testing.T) {
+	type PtrServer struct {
+		Name  string `toml:"name" env:"NAME" required:"true"`
+		Port  int    `toml:"port" env:"PORT" default:"80"`
+		Token string `toml:"token" env:"TOKEN" secret:"true"`
+	}
+	type PtrSliceConfig struct {
+		Servers []*PtrServer `toml:"servers" env:"SERVERS"`
+	}
+
+	t.Run("from env", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "PTRSLICE"
+		t.Setenv("PTRSLICE_SERVERS_0_NAME", "alpha")
+		t.Setenv("PTRSLICE_SERVERS_0_TOKEN", "secret")
+		t.Setenv("PTRSLICE_SERVERS_1_NAME", "beta")
+		t.Setenv("PTRSLICE_SERVERS_1_PORT", "8080")
+
+		if err := LoadConfig[PtrSliceConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[PtrSliceConfig]()
+		if len(cfg.Servers) != 2 {
+			t.Fatalf("expected 2 servers, got %d", len(cfg.Servers))
+		}
+		if *cfg.Servers[0] != (PtrServer{Name: "alpha", Port: 80, Token: "secret"}) {
+			t.Errorf("unexpected first server: %+v", *cfg.Servers[0])
+		}
+		if *cfg.Servers[1] != (PtrServer{Name: "beta", Port: 8080}) {
+			t.Errorf("unexpected second server: %+v", *cfg.Servers[1])
+		}
+
+		masked := maskedFields(maskSecrets(cfg))
+		first := maskedFields(masked["Servers"].([]interface{})[0])
+		if first["Token"] != "****" {
+			t.Errorf("expected token in pointer element to be masked, got %v", first["Token"])
+		}
+	})
+
+	t.Run("required field in element", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "PTRSLICEREQ"
+		t.Setenv("PTRSLICEREQ_SERVERS_0_PORT", "8080")
+
+		err := LoadConfig[PtrSliceConfig]()
+		expectedError := "required field 'NAME' is missing or empty at servers[0].name"
+		if err == nil || err.Error() != expectedError {
+			t.Errorf("expected error '%s', got '%v'", expectedError, err)
+		}
+	})
+
+	t.Run("from TOML", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "ptrslicetoml"
+		_, cleanup := createTestTomlFile(t, appName, "[[servers]]\nname = \"alpha\"\n\n[[servers]]\nport = 9000\n")
+		defer cleanup()
+
+		AppName = appName
+		err := LoadConfig[PtrSliceConfig]()
+		expectedError := "required field 'NAME' is missing or empty at servers[1].name"
+		if err == nil || err.Error() != expectedError {
+			t.Errorf("expected error '%s', got '%v'", expectedError, err)
+		}
+	})
+}
+
+// TestStructSliceMergeExtend는 merge:"extend" 태그에서 환경변수 요소가 TOML 슬라이스에 덧붙는지 테스트합니다
+func TestStructSliceMergeExtend(t *testing.T) {
+	type MergeServer struct {
+		Name string `toml:"name" env:"NAME"`
+		Port int    `toml:"port" env:"PORT"`
+	}
+	type MergeConfig struct {
+		Servers  []MergeServer  `toml:"servers" env:"SERVERS" merge:"extend"`
+		Backends []*MergeServer `toml:"backends" env:"BACKENDS" merge:"extend"`
+		Replaced []MergeServer  `toml:"replaced" env:"REPLACED"`
+	}
+
+	resetGlobalConfig()
+	appName := "mergeapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+[[servers]]
+name = "alpha"
+port = 8000
+
+[[servers]]
+name = "beta"
+port = 8001
+
+[[backends]]
+name = "db"
+port = 5432
+
+[[replaced]]
+name = "old"
+port = 1
+`)
+	defer cleanup()
+
+	t.Setenv("MERGEAPP_SERVERS_1_PORT", "9001")
+	t.Setenv("MERGEAPP_SERVERS_2_NAME", "gamma")
+	t.Setenv("MERGEAPP_SERVERS_2_PORT", "8002")
+	t.Setenv("MERGEAPP_BACKENDS_1_NAME", "cache")
+	t.Setenv("MERGEAPP_REPLACED_0_NAME", "new")
+
+	AppName = appName
+	if err := LoadConfig[MergeConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[MergeConfig]()
+
+	expectedServers := []MergeServer{{"alpha", 8000}, {"beta", 9001}, {"gamma", 8002}}
+	if !reflect.DeepEqual(cfg.Servers, expectedServers) {
+		t.Errorf("expected servers %+v, got %+v", expectedServers, cfg.Servers)
+	}
+	if len(cfg.Backends) != 2 || *cfg.Backends[0] != (MergeServer{"db", 5432}) || *cfg.Backends[1] != (MergeServer{"cache", 0}) {
+		t.Errorf("unexpected backends: %+v", cfg.Backends)
+	}
+	expectedReplaced := []MergeServer{{"new", 0}}
+	if !reflect.DeepEqual(cfg.Replaced, expectedReplaced) {
+		t.Errorf("expected env to replace the slice without merge tag, got %+v", cfg.Replaced)
+	}
+}
+
+// TestRemainField는 구조체 필드와 맞지 않는 TOML 키가 toml:",remain" 맵에 보존되는지 테스트합니다
+func TestRemainField(t *testing.T) {
+	type RemainConfig struct {
+		Server struct {
+			Host  string            `toml:"host"`
+			Extra map[string]string `toml:",remain"`
+		} `toml:"server"`
+		Name  string                 `toml:"name"`
+		Extra map[string]interface{} `toml:",remain"`
+	}
+
+	resetGlobalConfig()
+	appName := "remainapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+name = "app"
+feature_flag = true
+retries = 3
+
+[server]
+host = "localhost"
+zone = "eu-1"
+
+[future]
+mode = "fast"
+`)
+	defer cleanup()
+
+	AppName = appName
+	if err := LoadConfig[RemainConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[RemainConfig]()
+
+	expected := map[string]interface{}{
+		"feature_flag": true,
+		"retries":      int64(3),
+		"future":       map[string]interface{}{"mode": "fast"},
+	}
+	if !reflect.DeepEqual(cfg.Extra, expected) {
+		t.Errorf("expected overflow keys %v, got %v", expected, cfg.Extra)
+	}
+	if cfg.Name != "app" || cfg.Server.Host != "localhost" {
+		t.Errorf("expected known keys to decode normally, got %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Server.Extra, map[string]string{"zone": "eu-1"}) {
+		t.Errorf("expected nested overflow key, got %v", cfg.Server.Extra)
+	}
+}
+
+// TestInitConfigWithExactFile는 지정한 파일 경로를 확장자에 맞는 형식으로 읽고 환경변수를 적용하는지 테스트합니다
+func TestInitConfigWithExactFile(t *testing.T) {
+	type ExactConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+		return path
+	}
+
+	t.Run("JSON file", func(t *testing.T) {
+		resetGlobalConfig()
+		path := writeFile("settings.json", `{"server": {"host": "jsonhost", "port": 8080}}`)
+		t.Setenv("EXACTJSON_SERVER_PORT", "9090")
+
+		if err := InitConfigWithExactFile[ExactConfig]("exactjson", path); err != nil {
+			t.Fatalf("InitConfigWithExactFile failed: %v", err)
+		}
+		cfg := GetConfig[ExactConfig]()
+		if cfg.Server.Host != "jsonhost" || cfg.Server.Port != 9090 {
+			t.Errorf("expected jsonhost:9090 with env override, got %+v", cfg.Server)
+		}
+	})
+
+	t.Run("TOML file with any extension", func(t *testing.T) {
+		resetGlobalConfig()
+		path := writeFile("settings.conf", "[server]\nhost = \"tomlhost\"\nport = 7000\n")
+
+		if err := InitConfigWithExactFile[ExactConfig]("exacttoml", path); err != nil {
+			t.Fatalf("InitConfigWithExactFile failed: %v", err)
+		}
+		cfg := GetConfig[ExactConfig]()
+		if cfg.Server.Host != "tomlhost" || cfg.Server.Port != 7000 {
+			t.Errorf("expected tomlhost:7000, got %+v", cfg.Server)
+		}
+	})
+
+	t.Run("nonexistent path", func(t *testing.T) {
+		resetGlobalConfig()
+		missing := filepath.Join(dir, "missing.toml")
+
+		err := InitConfigWithExactFile[ExactConfig]("exactmissing", missing)
+		if err == nil || !strings.Contains(err.Error(), "failed to read config file "+missing) {
+			t.Errorf("expected a read error naming the path, got %v", err)
+		}
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		resetGlobalConfig()
+		path := writeFile("broken.json", `{"server": `)
+
+		err := InitConfigWithExactFile[ExactConfig]("exactbroken", path)
+		if err == nil || !strings.Contains(err.Error(), "failed to decode config file") {
+			t.Errorf("expected a decode error, got %v", err)
+		}
+	})
+}
+
+// TestConfigTypeSelection은 {APPNAME}_CONFIG_TYPE 값에 따라 설정 파일 형식을 선택하는지 테스트합니다
+func TestConfigTypeSelection(t *testing.T) {
+	type TypeConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	setup := func(t *testing.T, appName string) {
+		t.Helper()
+		resetGlobalConfig()
+		filePath, cleanup := createTestTomlFile(t, appName, "[server]\nhost = \"tomlhost\"\nport = 1000\n")
+		t.Cleanup(cleanup)
+		jsonPath := filepath.Join(filepath.Dir(filePath), appName+".json")
+		if err := os.WriteFile(jsonPath, []byte(`{"server": {"host": "jsonhost", "port": 2000}}`), 0644); err != nil {
+			t.Fatalf("failed to write json file: %v", err)
+		}
+		AppName = appName
+	}
+
+	tests := []struct {
+		configType   string
+		expectedHost string
+		expectedPort int
+	}{
+		{"", "tomlhost", 1000},
+		{"toml", "tomlhost", 1000},
+		{"JSON", "jsonhost", 2000},
+		{"env", "envhost", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("CONFIG_TYPE="+tt.configType, func(t *testing.T) {
+			setup(t, "typeapp")
+			if tt.configType != "" {
+				t.Setenv("TYPEAPP_CONFIG_TYPE", tt.configType)
+			}
+			if tt.configType == "env" {
+				t.Setenv("TYPEAPP_SERVER_HOST", "envhost")
+			}
+
+			if err := LoadConfig[TypeConfig](); err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			cfg := GetConfig[TypeConfig]()
+			if cfg.Server.Host != tt.expectedHost || cfg.Server.Port != tt.expectedPort {
+				t.Errorf("expected %s:%d, got %s:%d", tt.expectedHost, tt.expectedPort, cfg.Server.Host, cfg.Server.Port)
+			}
+		})
+	}
+}
+
+// TestConfigFileEnv는 {APPNAME}_CONFIG_FILE이 설정되면 검색 경로 대신 정확히 그 파일을 로드하는지 테스트합니다
+func TestConfigFileEnv(t *testing.T) {
+	type FileEnvConfig struct {
+		Name string `toml:"name" env:"NAME"`
+		Port int    `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "fileenvapp", "name = \"fromcwd\"\nport = 1000\n")
+	defer cleanup()
+	AppName = "fileenvapp"
+
+	custom := filepath.Join(t.TempDir(), "custom.toml")
+	if err := os.WriteFile(custom, []byte("name = \"fromcustom\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write custom config: %v", err)
+	}
+	t.Setenv("FILEENVAPP_CONFIG_FILE", custom)
+
+	if err := LoadConfig[FileEnvConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[FileEnvConfig]()
+	if cfg.Name != "fromcustom" || cfg.Port != 0 {
+		t.Errorf("expected only the values of the custom file, got %+v", cfg)
+	}
+
+	t.Setenv("FILEENVAPP_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.toml"))
+	if err := LoadConfig[FileEnvConfig](); err == nil || !strings.Contains(err.Error(), "missing.toml") {
+		t.Errorf("expected an error for a missing CONFIG_FILE, got %v", err)
+	}
+}
+
+// TestStrictField는 strict 태그가 붙은 필드의 환경변수가 비어있거나 파싱할 수 없으면 TOML 값이나 기본값으로 대체하지 않고 오류를 반환하는지 테스트합니다
+func TestStrictField(t *testing.T) {
+	type StrictConfig struct {
+		Port    int `toml:"port" env:"PORT" strict:"true"`
+		Workers int `toml:"workers" env:"WORKERS" default:"4"`
+	}
+
+	setup := func(t *testing.T) {
+		t.Helper()
+		resetGlobalConfig()
+		t.Cleanup(resetGlobalConfig)
+		_, cleanup := createTestTomlFile(t, "strictapp", "port = 8080\n")
+		t.Cleanup(cleanup)
+		AppName = "strictapp"
+	}
+
+	t.Run("present but empty", func(t *testing.T) {
+		setup(t)
+		t.Setenv("STRICTAPP_PORT", "")
+		err := LoadConfig[StrictConfig]()
+		if err == nil || !strings.Contains(err.Error(), "STRICTAPP_PORT of strict field port is set but empty") {
+			t.Errorf("expected an empty strict env var error, got %v", err)
+		}
+	})
+
+	t.Run("unparseable", func(t *testing.T) {
+		setup(t)
+		SetOnParseError(SkipAndCount)
+		t.Setenv("STRICTAPP_PORT", "eighty")
+		if err := LoadConfig[StrictConfig](); err == nil || !strings.Contains(err.Error(), "port") {
+			t.Errorf("expected a parse error for the strict field even in SkipAndCount mode, got %v", err)
+		}
+	})
+
+	t.Run("unset falls back", func(t *testing.T) {
+		setup(t)
+		t.Setenv("STRICTAPP_WORKERS", "")
+		if err := LoadConfig[StrictConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg := GetConfig[StrictConfig](); cfg.Port != 8080 || cfg.Workers != 4 {
+			t.Errorf("expected the TOML port and default workers, got %+v", cfg)
+		}
+	})
+
+	t.Run("valid value", func(t *testing.T) {
+		setup(t)
+		t.Setenv("STRICTAPP_PORT", "9090")
+		if err := LoadConfig[StrictConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg := GetConfig[StrictConfig](); cfg.Port != 9090 {
+			t.Errorf("expected port 9090 from the env var, got %d", cfg.Port)
+		}
+	})
+}
+
+// TestConfigTypeEnvIgnoresFile는 CONFIG_TYPE=env일 때 TOML 파일이 있어도 무시하는지 테스트합니다
+func TestConfigTypeEnvIgnoresFile(t *testing.T) {
+	type EnvOnlyConfig struct {
+		Name string `toml:"name" env:"NAME"`
+		Port int    `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "envonlyapp", "name = \"fromfile\"\nport = 9999\n")
+	defer cleanup()
+	AppName = "envonlyapp"
+	t.Setenv("ENVONLYAPP_CONFIG_TYPE", "env")
+	t.Setenv("ENVONLYAPP_NAME", "fromenv")
+
+	if err := LoadConfig[EnvOnlyConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[EnvOnlyConfig]()
+	if cfg.Name != "fromenv" {
+		t.Errorf("expected name 'fromenv', got '%s'", cfg.Name)
+	}
+	if cfg.Port != 0 {
+		t.Errorf("expected TOML file to be ignored, got port %d", cfg.Port)
+	}
+}
+
+// TestConfigTypeUnknown는 인식할 수 없는 CONFIG_TYPE 값이 에러를 반환하는지 테스트합니다
+func TestConfigTypeUnknown(t *testing.T) {
+	type UnknownTypeConfig struct {
+		Name string `toml:"name" env:"NAME"`
+	}
+
+	resetGlobalConfig()
+	AppName = "unknowntypeapp"
+	t.Setenv("UNKNOWNTYPEAPP_CONFIG_TYPE", "yaml")
+
+	err := LoadConfig[UnknownTypeConfig]()
+	if err == nil {
+		t.Fatal("expected an error for an unknown config type")
+	}
+	if !strings.Contains(err.Error(), "unknown config type 'yaml'") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestInitConfigFromFS는 fs.FS에서 설정 파일을 읽고 환경변수로 덮어쓰는지 테스트합니다
+func TestInitConfigFromFS(t *testing.T) {
+	type FSConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+		Name string `toml:"name" env:"NAME"`
+	}
+
+	fsys := fstest.MapFS{
+		"config/defaults.toml": {Data: []byte("include = [\"base.toml\"]\n\n[server]\nport = 8080\n")},
+		"config/base.toml":     {Data: []byte("name = \"base\"\n\n[server]\nhost = \"embedded\"\nport = 1\n")},
+		"config/defaults.json": {Data: []byte(`{"server": {"host": "json", "port": 9000}}`)},
+	}
+
+	t.Run("toml with includes", func(t *testing.T) {
+		resetGlobalConfig()
+		t.Setenv("FSAPP_SERVER_HOST", "envhost")
+
+		if err := InitConfigFromFS[FSConfig]("fsapp", fsys, "config/defaults.toml"); err != nil {
+			t.Fatalf("InitConfigFromFS failed: %v", err)
+		}
+		cfg := GetConfig[FSConfig]()
+		if cfg.Server.Host != "envhost" {
+			t.Errorf("expected env var to override the embedded host, got '%s'", cfg.Server.Host)
+		}
+		if cfg.Server.Port != 8080 || cfg.Name != "base" {
+			t.Errorf("expected port 8080 and name 'base', got %d and '%s'", cfg.Server.Port, cfg.Name)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		resetGlobalConfig()
+
+		if err := InitConfigFromFS[FSConfig]("fsapp", fsys, "config/defaults.json"); err != nil {
+			t.Fatalf("InitConfigFromFS failed: %v", err)
+		}
+		cfg := GetConfig[FSConfig]()
+		if cfg.Server.Host != "json" || cfg.Server.Port != 9000 {
+			t.Errorf("expected json:9000, got %s:%d", cfg.Server.Host, cfg.Server.Port)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		resetGlobalConfig()
+
+		err := InitConfigFromFS[FSConfig]("fsapp", fsys, "config/missing.toml")
+		if err == nil || !strings.Contains(err.Error(), "failed to load config file config/missing.toml") {
+			t.Errorf("expected a missing file error, got %v", err)
+		}
+	})
+}
+
+// TestFprintConfig는 FprintConfig가 기본적으로 시크릿을 마스킹하고 허용된 경우에만 평문으로 출력하는지 테스트합니다
+func TestFprintConfig(t *testing.T) {
+	type PrintConfigStruct struct {
+		User     string `toml:"user" env:"USER"`
+		Password string `toml:"password" env:"PASSWORD" secret:"true"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "fprintapp"
+	t.Setenv("FPRINTAPP_USER", "admin")
+	t.Setenv("FPRINTAPP_PASSWORD", "hunter2")
+	if err := LoadConfig[PrintConfigStruct](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	t.Run("masked by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := FprintConfig(&buf, true); err != nil {
+			t.Fatalf("FprintConfig failed: %v", err)
+		}
+		out := buf.String()
+		if !strings.Contains(out, `"Password": "****"`) || strings.Contains(out, "hunter2") {
+			t.Errorf("expected the password to be masked, got:\n%s", out)
+		}
+		if !strings.Contains(out, `"User": "admin"`) {
+			t.Errorf("expected the user in the output, got:\n%s", out)
+		}
+	})
+
+	t.Run("unmasked requires opt-in", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := FprintConfig(&buf, false)
+		if err == nil || !strings.Contains(err.Error(), "AllowUnmaskedSecrets") {
+			t.Errorf("expected an error without AllowUnmaskedSecrets, got %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("expected no output, got:\n%s", buf.String())
+		}
+	})
+
+	t.Run("unmasked after opt-in", func(t *testing.T) {
+		AllowUnmaskedSecrets(true)
+		defer AllowUnmaskedSecrets(false)
+
+		var buf bytes.Buffer
+		if err := FprintConfig(&buf, false); err != nil {
+			t.Fatalf("FprintConfig failed: %v", err)
+		}
+		if !strings.Contains(buf.String(), `"Password": "hunter2"`) {
+			t.Errorf("expected the password in clear text, got:\n%s", buf.String())
+		}
+	})
+}
+
+// TestPrintConfigUnmaskGates는 PrintConfig와 PrintConfigFlat이 AllowUnmaskedSecrets(true)와 {APPNAME}_CONFIG_UNMASK=1이 모두 설정된 경우에만 시크릿을 노출하는지 테스트합니다
+func TestPrintConfigUnmaskGates(t *testing.T) {
+	type UnmaskConfig struct {
+		Password string `toml:"password" env:"PASSWORD" secret:"true"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "unmaskapp"
+	t.Setenv("UNMASKAPP_PASSWORD", "hunter2")
+	if err := LoadConfig[UnmaskConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	capture := func(print func()) string {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		print()
+		w.Close()
+		os.Stdout = old
+
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	tests := []struct {
+		name     string
+		allow    bool
+		env      string
+		unmasked bool
+	}{
+		{"no gate", false, "", false},
+		{"env only", false, "1", false},
+		{"code only", true, "", false},
+		{"env not 1", true, "true", false},
+		{"both gates", true, "1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			AllowUnmaskedSecrets(tt.allow)
+			defer AllowUnmaskedSecrets(false)
+			t.Setenv("UNMASKAPP_CONFIG_UNMASK", tt.env)
+
+			out := capture(PrintConfig)
+			if revealed := strings.Contains(out, "hunter2"); revealed != tt.unmasked {
+				t.Errorf("expected unmasked=%v, got output:\n%s", tt.unmasked, out)
+			}
+			if !tt.unmasked && !strings.Contains(out, `"Password": "****"`) {
+				t.Errorf("expected the password to be masked, got:\n%s", out)
+			}
+
+			flat := capture(PrintConfigFlat)
+			if revealed := strings.Contains(flat, "hunter2"); revealed != tt.unmasked {
+				t.Errorf("expected unmasked=%v from PrintConfigFlat, got output:\n%s", tt.unmasked, flat)
+			}
+		})
+	}
+}
+
+// flatConfig is a 20-field config without nested structs, loaded through the
+// flat fast path.
+type flatConfig struct {
+	Host     string        `env:"HOST" default:"localhost"`
+	Port     int           `env:"PORT" default:"8080"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"30s"`
+	Ratio    float64       `env:"RATIO"`
+	Tags     []string      `env:"TAGS"`
+	Ports    []int         `env:"PORTS"`
+	User     string        `env:"USER"`
+	Password string        `env:"PASSWORD" secret:"true"`
+	Name     string        `env:"NAME"`
+	Region   string        `env:"REGION" default:"eu"`
+	Zone     string        `env:"ZONE"`
+	Workers  int           `env:"WORKERS" default:"4"`
+	Retries  int           `env:"RETRIES"`
+	Level    string        `env:"LEVEL" default:"info"`
+	Format   string        `env:"FORMAT"`
+	Cache    bool          `env:"CACHE"`
+	MaxConns int64         `env:"MAX_CONNS"`
+	Weight   float32       `env:"WEIGHT"`
+	Labels   []string      `env:"LABELS" delim:";"`
+}
+
+// flatEnv is the environment flatConfig is loaded from in tests and benchmarks.
+var flatEnv = map[string]string{
+	"FLATAPP_HOST":      "example.com",
+	"FLATAPP_DEBUG":     "true",
+	"FLATAPP_RATIO":     "0.5",
+	"FLATAPP_TAGS":      "a,b,c",
+	"FLATAPP_PORTS":     "80,443",
+	"FLATAPP_USER":      "admin",
+	"FLATAPP_PASSWORD":  "secret",
+	"FLATAPP_RETRIES":   "3",
+	"FLATAPP_MAX_CONNS": "100",
+	"FLATAPP_LABELS":    "x;y",
+}
+
+func flatLookup(key string) (string, bool) {
+	value, ok := flatEnv[key]
+	return value, ok
+}
+
+// TestFlatStructFastPath는 중첩 없는 구조체가 빠른 경로로 로드되고 일반 경로와 같은 결과를 내는지 테스트합니다
+func TestFlatStructFastPath(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+
+	if !getCachedTypeInfo(reflect.TypeOf(flatConfig{})).Flat {
+		t.Fatal("expected flatConfig to be detected as flat")
+	}
+	if getCachedTypeInfo(reflect.TypeOf(TestConfig{})).Flat {
+		t.Error("expected TestConfig with nested structs not to be flat")
+	}
+
+	var flat, general flatConfig
+	if err := loadFlatStructEnv(flatLookup, reflect.ValueOf(&flat).Elem(), "flatapp", ""); err != nil {
+		t.Fatalf("flat load failed: %v", err)
+	}
+	if err := loadNestedStructEnv(flatLookup, reflect.ValueOf(&general).Elem(), "flatapp", ""); err != nil {
+		t.Fatalf("general load failed: %v", err)
+	}
+	if !reflect.DeepEqual(flat, general) {
+		t.Errorf("expected identical results, got\nflat:    %+v\ngeneral: %+v", flat, general)
+	}
+	if flat.Host != "example.com" || flat.Port != 8080 || !reflect.DeepEqual(flat.Labels, []string{"x", "y"}) {
+		t.Errorf("unexpected flat result: %+v", flat)
+	}
+}
+
+// BenchmarkFlatStructEnv는 20개 필드의 평면 구조체에 대해 빠른 경로와 일반 경로를 비교합니다
+func BenchmarkFlatStructEnv(b *testing.B) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+
+	b.Run("flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var cfg flatConfig
+			if err := loadFlatStructEnv(flatLookup, reflect.ValueOf(&cfg).Elem(), "flatapp", ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("general", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var cfg flatConfig
+			if err := loadNestedStructEnv(flatLookup, reflect.ValueOf(&cfg).Elem(), "flatapp", ""); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// TestLoadWithPrefix는 같은 환경에서 서로 다른 접두사로 로드한 설정이 각자의 값을 가지며 전역 상태를 바꾸지 않는지 테스트합니다
+func TestLoadWithPrefix(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "mainapp"
+	t.Setenv("TENANTA_SERVER_HOST", "a.example.com")
+	t.Setenv("TENANTA_DATABASE_USER", "alice")
+	t.Setenv("TENANTB_SERVER_HOST", "b.example.com")
+	t.Setenv("TENANTB_SERVER_PORT", "9090")
+	t.Setenv("TENANTB_DATABASE_USER", "bob")
+
+	tenantA, err := LoadWithPrefix[TestConfig]("TENANTA")
+	if err != nil {
+		t.Fatalf("LoadWithPrefix failed: %v", err)
+	}
+	tenantB, err := LoadWithPrefix[TestConfig]("TENANTB_")
+	if err != nil {
+		t.Fatalf("LoadWithPrefix failed: %v", err)
+	}
+
+	if tenantA.Server.Host != "a.example.com" || tenantA.Server.Port != 8080 || tenantA.Database.User != "alice" {
+		t.Errorf("unexpected tenant A config: %+v", tenantA)
+	}
+	if tenantB.Server.Host != "b.example.com" || tenantB.Server.Port != 9090 || tenantB.Database.User != "bob" {
+		t.Errorf("unexpected tenant B config: %+v", tenantB)
+	}
+	if AppName != "mainapp" || instance != nil {
+		t.Errorf("expected globals to be untouched, got AppName '%s' and instance %v", AppName, instance)
+	}
+
+	if _, err := LoadWithPrefix[TestConfig]("TENANTC"); err == nil {
+		t.Error("expected a validation error for a tenant without required values")
+	}
+}
+
+// TestInitConfigForce는 InitConfig 후 InitConfigForce로 다른 타입을 다시 초기화할 수 있는지 테스트합니다
+func TestInitConfigForce(t *testing.T) {
+	type FirstConfig struct {
+		Host string `env:"HOST"`
+	}
+	type SecondConfig struct {
+		Port int `env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	t.Setenv("FIRSTAPP_HOST", "first.example.com")
+	t.Setenv("SECONDAPP_PORT", "9090")
+
+	InitConfig[FirstConfig]("firstapp")
+	if cfg := GetConfig[FirstConfig](); cfg.Host != "first.example.com" {
+		t.Errorf("expected host first.example.com, got %q", cfg.Host)
+	}
+
+	// A second InitConfig does nothing, even for another type
+	InitConfig[SecondConfig]("secondapp")
+	if _, err := GetConfigSafe[SecondConfig](); err == nil {
+		t.Error("expected InitConfig not to replace an initialized config")
+	}
+	if AppName != "firstapp" {
+		t.Errorf("expected an ignored InitConfig to keep AppName firstapp, got %q", AppName)
+	}
+
+	// A failed InitConfigForce keeps the current config and AppName
+	t.Setenv("BROKENAPP_CONFIG_TYPE", "yaml")
+	if err := InitConfigForce[SecondConfig]("brokenapp"); err == nil {
+		t.Fatal("expected InitConfigForce to fail")
+	}
+	if _, err := GetConfigSafe[FirstConfig](); err != nil || AppName != "firstapp" {
+		t.Errorf("expected a failed InitConfigForce to keep the first config and AppName, got %q (%v)", AppName, err)
+	}
+
+	if err := InitConfigForce[SecondConfig]("secondapp"); err != nil {
+		t.Fatalf("InitConfigForce failed: %v", err)
+	}
+	if cfg := GetConfig[SecondConfig](); cfg.Port != 9090 {
+		t.Errorf("expected port 9090, got %d", cfg.Port)
+	}
+
+	InitConfig[FirstConfig]("firstapp")
+	if _, err := GetConfigSafe[SecondConfig](); err != nil {
+		t.Errorf("expected InitConfig not to replace the forced config, got %v", err)
+	}
+	if AppName != "secondapp" {
+		t.Errorf("expected the forced config to keep AppName secondapp, got %q", AppName)
+	}
+}
@@ -0,0 +1,117 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pelletier/go-toml"
+)
+
+// ConflictPolicy controls what happens when the config file and an
+// environment variable set the same field to different values.
+type ConflictPolicy int
+
+const (
+	// EnvWins lets the environment variable override the file silently (the
+	// default).
+	EnvWins ConflictPolicy = iota
+	// ErrorOnConflict fails the load with an error naming every field set to
+	// different values by the file and the environment.
+	ErrorOnConflict
+)
+
+var (
+	conflictPolicy = EnvWins
+	// fileKeys holds the paths of the values set by the config documents of
+	// the load in progress, collected under ErrorOnConflict
+	fileKeys map[string]bool
+)
+
+// SetConflictPolicy sets how subsequent loads treat a field that the config
+// file and an environment variable set to different values. With
+// ErrorOnConflict the load fails in every validation mode, and the error
+// names the field and both values, with secrets masked. Every key written in
+// the config file counts, including one set to the field's default or to an
+// empty value; defaults of keys missing from the file do not. The files of
+// InitConfigFromDir are not compared.
+//
+// Example:
+//
+//	ahatconfig.SetConflictPolicy(ahatconfig.ErrorOnConflict)
+//	// port = 8080 in myapp.toml and MYAPP_PORT=9090 now fail the load
+func SetConflictPolicy(policy ConflictPolicy) {
+	conflictPolicy = policy
+}
+
+// envConflicts compares the values of the config cfg, a pointer to a struct,
+// with base, its values before environment variables were applied, and
+// returns a problem for every value set by the file that an environment
+// variable changed.
+func envConflicts(cfg interface{}, base []PathValue) []error {
+	if len(base) == 0 {
+		return nil
+	}
+
+	v := reflect.ValueOf(cfg)
+	current := make(map[string]interface{})
+	for _, pv := range appendValues(nil, v.Elem(), "", false) {
+		current[pv.Path] = pv.Value
+	}
+
+	var problems []error
+	for _, pv := range base {
+		value, ok := current[pv.Path]
+		if !ok || !fileKeys[pv.Path] || reflect.DeepEqual(pv.Value, value) {
+			continue
+		}
+
+		fileValue, envValue := pv.Value, value
+		if _, fieldInfo, ok := fieldAtPath(v, pv.Path); ok && isSecretField(fieldInfo, pv.Path) {
+			fileValue, envValue = "****", "****"
+		}
+		problems = append(problems, fmt.Errorf("field %s is set to %v in the config file and to %v by the environment", pv.Path, fileValue, envValue))
+	}
+	return problems
+}
+
+// recordFileKeys adds the paths of the values of type t set in tree to
+// fileKeys, in the form used by Values. path is the dotted path of t.
+func recordFileKeys(tree *toml.Tree, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		if !tree.HasPath([]string{fieldInfo.Key}) {
+			continue
+		}
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch value := tree.GetPath([]string{fieldInfo.Key}).(type) {
+		case *toml.Tree:
+			if isNestedStruct(fieldInfo.Type) {
+				recordFileKeys(value, fieldInfo.Type, fieldPath)
+				continue
+			}
+			if fieldInfo.Type.Kind() == reflect.Map && isNestedStruct(fieldInfo.Type.Elem()) {
+				for _, key := range value.Keys() {
+					if elem, ok := value.GetPath([]string{key}).(*toml.Tree); ok {
+						recordFileKeys(elem, fieldInfo.Type.Elem(), joinPath(fieldPath, key))
+					}
+				}
+				continue
+			}
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for i, elem := range value {
+					recordFileKeys(elem, elemType, indexPath(fieldPath, i))
+				}
+				continue
+			}
+		}
+		fileKeys[fieldPath] = true
+	}
+}
@@ -0,0 +1,140 @@
+package ahatconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestConflictPolicy는 ErrorOnConflict 정책에서 TOML과 환경변수가 같은 필드를 다르게 설정하면 오류가 되는지 테스트합니다
+func TestConflictPolicy(t *testing.T) {
+	type ConflictConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT" default:"8080"`
+		} `toml:"server" env:"SERVER"`
+		Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		Region   string `toml:"region" env:"REGION"`
+	}
+
+	setup := func(t *testing.T) {
+		resetGlobalConfig()
+		t.Cleanup(resetGlobalConfig)
+		appName := "conflictapp"
+		_, cleanup := createTestTomlFile(t, appName, `
+password = "from-file"
+region = "eu"
+
+[server]
+host = "file.example.com"
+`)
+		t.Cleanup(cleanup)
+		AppName = appName
+
+		t.Setenv("CONFLICTAPP_SERVER_HOST", "env.example.com")
+		t.Setenv("CONFLICTAPP_SERVER_PORT", "9090")
+		t.Setenv("CONFLICTAPP_PASSWORD", "from-env")
+		t.Setenv("CONFLICTAPP_REGION", "eu")
+	}
+
+	t.Run("env wins", func(t *testing.T) {
+		setup(t)
+		if err := LoadConfig[ConflictConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg := GetConfig[ConflictConfig](); cfg.Server.Host != "env.example.com" {
+			t.Errorf("expected the env value to win, got %q", cfg.Server.Host)
+		}
+	})
+
+	t.Run("error on conflict", func(t *testing.T) {
+		setup(t)
+		SetConflictPolicy(ErrorOnConflict)
+
+		err := LoadConfig[ConflictConfig]()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected a ValidationError, got %v", err)
+		}
+		if len(verr.Problems) != 2 {
+			t.Errorf("expected conflicts for server.host and password only, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "field server.host is set to file.example.com in the config file and to env.example.com by the environment") {
+			t.Errorf("expected the host conflict with both values, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "field password is set to **** in the config file and to **** by the environment") {
+			t.Errorf("expected the password conflict to be masked, got %v", err)
+		}
+		if strings.Contains(err.Error(), "from-file") || strings.Contains(err.Error(), "from-env") {
+			t.Errorf("expected secret values to be masked, got %v", err)
+		}
+	})
+}
+
+// TestConflictPolicyExplicitValues는 파일에 명시적으로 적힌 기본값과 빈 값도 환경변수와 다르면 충돌로 보고되는지 테스트합니다
+func TestConflictPolicyExplicitValues(t *testing.T) {
+	type ExplicitConfig struct {
+		Port  int    `toml:"port" env:"PORT" default:"8080"`
+		Name  string `toml:"name" env:"NAME"`
+		Debug bool   `toml:"debug" env:"DEBUG"`
+		Level string `toml:"level" env:"LEVEL" default:"info"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "explicitapp", "port = 8080\nname = \"\"\n")
+	defer cleanup()
+	AppName = "explicitapp"
+	t.Setenv("EXPLICITAPP_PORT", "9090")
+	t.Setenv("EXPLICITAPP_NAME", "env")
+	// 파일에 없는 키의 기본값과 제로값은 충돌이 아니다
+	t.Setenv("EXPLICITAPP_DEBUG", "true")
+	t.Setenv("EXPLICITAPP_LEVEL", "warn")
+	SetConflictPolicy(ErrorOnConflict)
+
+	err := LoadConfig[ExplicitConfig]()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(verr.Problems) != 2 {
+		t.Errorf("expected conflicts for port and name only, got %v", err)
+	}
+	for _, want := range []string{
+		"field port is set to 8080 in the config file and to 9090 by the environment",
+		"field name is set to  in the config file and to env by the environment",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q, got %v", want, err)
+		}
+	}
+}
+
+// TestConflictPolicyKeySpellings는 go-toml이 대소문자나 필드 이름으로 매칭하는 파일 키도 충돌로 보고되는지 테스트합니다
+func TestConflictPolicyKeySpellings(t *testing.T) {
+	type SpellingConfig struct {
+		Server struct {
+			Port    int    `toml:"port" env:"PORT"`
+			Timeout string `env:"TIMEOUT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "spellingapp", "[server]\nPORT = 8080\nTimeout = \"5s\"\n")
+	defer cleanup()
+	AppName = "spellingapp"
+	t.Setenv("SPELLINGAPP_SERVER_PORT", "9090")
+	t.Setenv("SPELLINGAPP_SERVER_TIMEOUT", "10s")
+	SetConflictPolicy(ErrorOnConflict)
+
+	err := LoadConfig[SpellingConfig]()
+	for _, want := range []string{
+		"field server.port is set to 8080 in the config file and to 9090 by the environment",
+		"field server.timeout is set to 5s in the config file and to 10s by the environment",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q, got %v", want, err)
+		}
+	}
+}
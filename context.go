@@ -0,0 +1,35 @@
+package ahatconfig
+
+import (
+	"context"
+)
+
+// configContextKey is the context key of a config of type T. Each config type
+// has its own key, so configs of different types can share a context.
+type configContextKey[T any] struct{}
+
+// ContextWithConfig returns a copy of ctx that carries cfg, for request-scoped
+// access to a configuration without the shared instance.
+//
+// Example:
+//
+//	func withConfig(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        ctx := ahatconfig.ContextWithConfig(r.Context(), ahatconfig.GetConfig[MyConfig]())
+//	        next.ServeHTTP(w, r.WithContext(ctx))
+//	    })
+//	}
+func ContextWithConfig[T any](ctx context.Context, cfg *T) context.Context {
+	return context.WithValue(ctx, configContextKey[T]{}, cfg)
+}
+
+// ConfigFromContext returns the config of type T stored in ctx with
+// ContextWithConfig. It reports false when ctx carries no config of that type.
+//
+// Example:
+//
+//	cfg, ok := ahatconfig.ConfigFromContext[MyConfig](r.Context())
+func ConfigFromContext[T any](ctx context.Context) (*T, bool) {
+	cfg, ok := ctx.Value(configContextKey[T]{}).(*T)
+	return cfg, ok
+}
@@ -0,0 +1,31 @@
+package ahatconfig
+
+import (
+	"context"
+	"testing"
+)
+
+// TestConfigContext는 ContextWithConfig로 저장한 설정을 ConfigFromContext로 같은 타입으로만 꺼낼 수 있는지 테스트합니다
+func TestConfigContext(t *testing.T) {
+	type ServerConfig struct {
+		Port int `toml:"port"`
+	}
+	type WorkerConfig struct {
+		Queue string `toml:"queue"`
+	}
+
+	server := &ServerConfig{Port: 8080}
+	worker := &WorkerConfig{Queue: "jobs"}
+	ctx := ContextWithConfig(context.Background(), server)
+	ctx = ContextWithConfig(ctx, worker)
+
+	if got, ok := ConfigFromContext[ServerConfig](ctx); !ok || got != server {
+		t.Errorf("expected the stored server config, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := ConfigFromContext[WorkerConfig](ctx); !ok || got != worker {
+		t.Errorf("expected the stored worker config, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := ConfigFromContext[ServerConfig](context.Background()); ok || got != nil {
+		t.Errorf("expected no config in an empty context, got %v (ok=%v)", got, ok)
+	}
+}
@@ -0,0 +1,32 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lookupCredential reads the systemd credential name from the directory in
+// $CREDENTIALS_DIRECTORY, which systemd sets for services with
+// LoadCredential= or SetCredential=. Trailing newlines are removed. It
+// reports false when the variable is not set or the credential does not
+// exist. path is the dotted path of the field the credential is read for.
+func lookupCredential(name, path string) (string, bool, error) {
+	dir, ok := lookupEnv("CREDENTIALS_DIRECTORY")
+	if !ok || dir == "" {
+		return "", false, nil
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", false, fmt.Errorf("invalid credential tag '%s' on field %s, expected a file name", name, path)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read credential %s for field %s: %w", name, path, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
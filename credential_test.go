@@ -0,0 +1,78 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCredentialTag는 credential 태그가 붙은 필드를 $CREDENTIALS_DIRECTORY의 파일에서 읽고, 환경변수가 우선하는지 테스트합니다
+func TestCredentialTag(t *testing.T) {
+	type CredentialConfig struct {
+		Database struct {
+			User     string `toml:"user" env:"USER"`
+			Password string `toml:"password" env:"PASSWORD" secret:"true" credential:"db-password"`
+		} `toml:"database" env:"DATABASE"`
+		APIKey string `toml:"api_key" env:"API_KEY" credential:"api-key" default:"none"`
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db-password"), []byte("hunter2\n"), 0600); err != nil {
+		t.Fatalf("failed to write credential: %v", err)
+	}
+
+	t.Run("read from the credentials directory", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "credapp"
+		t.Setenv("CREDENTIALS_DIRECTORY", dir)
+
+		if err := LoadConfig[CredentialConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[CredentialConfig]()
+		if cfg.Database.Password != "hunter2" {
+			t.Errorf("expected the password from the credential file, got %q", cfg.Database.Password)
+		}
+		if cfg.APIKey != "none" {
+			t.Errorf("expected the default for a missing credential, got %q", cfg.APIKey)
+		}
+	})
+
+	t.Run("env var wins", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "credapp"
+		t.Setenv("CREDENTIALS_DIRECTORY", dir)
+		t.Setenv("CREDAPP_DATABASE_PASSWORD", "from-env")
+
+		if err := LoadConfig[CredentialConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := GetConfig[CredentialConfig]().Database.Password; got != "from-env" {
+			t.Errorf("expected the env var to take precedence, got %q", got)
+		}
+	})
+
+	t.Run("no credentials directory", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "credapp"
+		os.Unsetenv("CREDENTIALS_DIRECTORY")
+
+		if err := LoadConfig[CredentialConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := GetConfig[CredentialConfig]().Database.Password; got != "" {
+			t.Errorf("expected no password without CREDENTIALS_DIRECTORY, got %q", got)
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		t.Setenv("CREDENTIALS_DIRECTORY", dir)
+		if _, _, err := lookupCredential("../db-password", "database.password"); err == nil || !strings.Contains(err.Error(), "invalid credential tag") {
+			t.Errorf("expected an invalid credential name error, got %v", err)
+		}
+	})
+}
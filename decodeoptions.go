@@ -0,0 +1,47 @@
+package ahatconfig
+
+import (
+	"bytes"
+
+	"github.com/pelletier/go-toml"
+)
+
+// TOMLDecodeOption configures the go-toml decoder used for config files, e.g.
+// func(d *toml.Decoder) *toml.Decoder { return d.Strict(true) }.
+type TOMLDecodeOption func(*toml.Decoder) *toml.Decoder
+
+var tomlDecodeOptions []TOMLDecodeOption
+
+// SetTOMLDecodeOptions sets go-toml decoder options applied when subsequent
+// loads decode a config file, for decoding needs the package does not cover
+// itself. Calling it without options restores the default decoding.
+//
+// Example:
+//
+//	// Fail on keys that match no field
+//	ahatconfig.SetTOMLDecodeOptions(func(d *toml.Decoder) *toml.Decoder {
+//	    return d.Strict(true)
+//	})
+func SetTOMLDecodeOptions(opts ...TOMLDecodeOption) {
+	tomlDecodeOptions = opts
+}
+
+// unmarshalTree unmarshals tree into cfg with the configured decoder options.
+// go-toml decoders only read documents, so with options set the tree is
+// encoded again and decoded from the result, padded like a config file
+// read by loadTOML so that local dates still parse.
+func unmarshalTree(tree *toml.Tree, cfg interface{}) error {
+	if len(tomlDecodeOptions) == 0 {
+		return tree.Unmarshal(cfg)
+	}
+
+	data, err := tree.Marshal()
+	if err != nil {
+		return err
+	}
+	decoder := toml.NewDecoder(bytes.NewReader(padLocalDates(data)))
+	for _, opt := range tomlDecodeOptions {
+		decoder = opt(decoder)
+	}
+	return decoder.Decode(cfg)
+}
@@ -0,0 +1,79 @@
+package ahatconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// TestTOMLDecodeOptions는 SetTOMLDecodeOptions로 전달한 go-toml 디코더 옵션(strict)이 설정 파일 디코딩에 적용되는지 테스트합니다
+func TestTOMLDecodeOptions(t *testing.T) {
+	type DecodeOptionsConfig struct {
+		Server struct {
+			Host    string        `toml:"host"`
+			Port    int           `toml:"port"`
+			Timeout time.Duration `toml:"timeout"`
+		} `toml:"server"`
+		Started time.Time `toml:"started"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "decodeopts", `started = 2024-03-15T10:00:00
+[server]
+host = "localhost"
+port = 8080
+timeout = "5s"
+`)
+	defer cleanup()
+	AppName = "decodeopts"
+
+	SetTOMLDecodeOptions(func(d *toml.Decoder) *toml.Decoder {
+		return d.Strict(true)
+	})
+	if err := LoadConfig[DecodeOptionsConfig](); err != nil {
+		t.Fatalf("expected a document without unknown keys to load in strict mode, got %v", err)
+	}
+	cfg := GetConfig[DecodeOptionsConfig]()
+	if cfg.Server.Host != "localhost" || cfg.Server.Port != 8080 || cfg.Server.Timeout != 5*time.Second {
+		t.Errorf("expected values from the file, got %+v", cfg.Server)
+	}
+	if !cfg.Started.Equal(time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the local date-time to survive re-encoding, got %v", cfg.Started)
+	}
+
+	path, cleanup := createTestTomlFile(t, "decodeopts", "[server]\nhost = \"localhost\"\nprot = 8080\n")
+	defer cleanup()
+	err := InitConfigWithExactFile[DecodeOptionsConfig]("decodeopts", path)
+	if err == nil || !strings.Contains(err.Error(), "prot") {
+		t.Errorf("expected an unknown key error for server.prot, got %v", err)
+	}
+
+	SetTOMLDecodeOptions()
+	if err := InitConfigWithExactFile[DecodeOptionsConfig]("decodeopts", path); err != nil {
+		t.Errorf("expected the default decoding to ignore unknown keys, got %v", err)
+	}
+}
+
+// TestTOMLDecodeOptionsLocalDate는 디코더 옵션이 설정된 경우에도 맵 필드의 로컬 날짜가 디코딩되는지 테스트합니다
+func TestTOMLDecodeOptionsLocalDate(t *testing.T) {
+	type LocalDateConfig struct {
+		Extra map[string]interface{} `toml:"extra"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	path, cleanup := createTestTomlFile(t, "decodedate", "[extra]\nd = 2024-03-04\n")
+	defer cleanup()
+
+	SetTOMLDecodeOptions(func(d *toml.Decoder) *toml.Decoder { return d })
+	if err := InitConfigWithExactFile[LocalDateConfig]("decodedate", path); err != nil {
+		t.Fatalf("expected a local date to load with decode options set, got %v", err)
+	}
+	cfg := GetConfig[LocalDateConfig]()
+	if d, ok := cfg.Extra["d"].(toml.LocalDate); !ok || d.String() != "2024-03-04" {
+		t.Errorf("expected local date 2024-03-04, got %#v", cfg.Extra["d"])
+	}
+}
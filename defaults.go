@@ -0,0 +1,188 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// runtimeDefaults holds the defaults set with SetDefaults, keyed by field path.
+var runtimeDefaults map[string]string
+
+// SetDefaults sets default values by dotted field path (e.g. "server.port")
+// for subsequent loads. They have the same precedence as default tags: a value
+// from the config file or the environment wins. A runtime default replaces
+// the default tag of its field. Paths use the TOML keys and cannot reach into
+// slice elements; an unknown path makes loading fail. Pass nil to clear them.
+//
+// Example:
+//
+//	ahatconfig.SetDefaults(map[string]string{
+//	    "server.port":   "8080",
+//	    "database.host": defaultDBHost(),
+//	})
+func SetDefaults(defaults map[string]string) {
+	runtimeDefaults = make(map[string]string, len(defaults))
+	for path, value := range defaults {
+		runtimeDefaults[path] = value
+	}
+}
+
+// fieldDefault returns the default value of the field at path: its runtime
+// default, else its default tag. Default tags referencing other fields are
+// resolved after loading, so they are not returned here.
+func fieldDefault(fieldInfo FieldInfo, path string) string {
+	if value, ok := runtimeDefaults[path]; ok {
+		return value
+	}
+	if fieldInfo.DefaultRefs {
+		return ""
+	}
+	return fieldInfo.DefaultValue
+}
+
+// hasRuntimeDefaults reports whether a runtime default is set for a field
+// nested under path.
+func hasRuntimeDefaults(path string) bool {
+	for defaultPath := range runtimeDefaults {
+		if strings.HasPrefix(defaultPath, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRuntimeDefaults returns an error for the first runtime default whose
+// path names no field of the struct type t.
+func checkRuntimeDefaults(t reflect.Type) error {
+	for path := range runtimeDefaults {
+		if _, _, ok := fieldAtPath(reflect.New(t).Elem(), path); !ok {
+			return fmt.Errorf("unknown field path '%s' in SetDefaults", path)
+		}
+	}
+	return nil
+}
+
+// applyTreeDefaults sets the fields of cfg whose runtime default is set and
+// whose key is missing from the decoded document tree. go-toml only knows
+// about default tags, so without this they would win over runtime defaults.
+func applyTreeDefaults(tree *toml.Tree, cfg reflect.Value) error {
+	for path, def := range runtimeDefaults {
+		if tree.HasPath(strings.Split(path, ".")) {
+			continue
+		}
+		field, fieldInfo, ok := fieldAtPath(cfg, path)
+		if !ok {
+			continue
+		}
+		parsed, err := parseFieldValue(def, fieldInfo, path)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+	}
+	return nil
+}
+
+// fieldAtPath returns the field of the struct v at the dotted path of TOML
+// keys, along with its field info. Segments may index into slices and arrays
+// of structs, e.g. "users[1].role".
+func fieldAtPath(v reflect.Value, path string) (reflect.Value, FieldInfo, bool) {
+	segments := strings.Split(path, ".")
+	for depth, segment := range segments {
+		key, index, err := splitIndex(segment)
+		if err != nil {
+			return reflect.Value{}, FieldInfo{}, false
+		}
+
+		for v.Kind() == reflect.Ptr && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, FieldInfo{}, false
+		}
+
+		found := false
+		for i, fieldInfo := range getCachedTypeInfo(v.Type()).Fields {
+			if fieldInfo.Key != key {
+				continue
+			}
+			v = v.Field(i)
+			if index >= 0 {
+				if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || index >= v.Len() {
+					return reflect.Value{}, FieldInfo{}, false
+				}
+				v = v.Index(index)
+			} else if depth == len(segments)-1 {
+				return v, fieldInfo, true
+			}
+			found = true
+			break
+		}
+		if !found {
+			return reflect.Value{}, FieldInfo{}, false
+		}
+	}
+	// The path ends in an element, not a field
+	return reflect.Value{}, FieldInfo{}, false
+}
+
+// splitIndex splits a path segment such as "users[1]" into its key and
+// index. The index is -1 when the segment has none.
+func splitIndex(segment string) (string, int, error) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 || !strings.HasSuffix(segment, "]") {
+		return segment, -1, nil
+	}
+	index, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid index in path segment '%s'", segment)
+	}
+	return segment[:open], index, nil
+}
+
+// ResetFieldToDefault sets the field at path (e.g. "server.port" or
+// "users[0].role") of the loaded configuration back to its default: the value
+// given to SetDefaults, else its default tag, else the zero value. The
+// instance is updated in place under the config lock, so the change is
+// visible through pointers already returned by GetConfig.
+//
+// Example:
+//
+//	if err := ahatconfig.ResetFieldToDefault[MyConfig]("server.port"); err != nil {
+//	    log.Printf("reset failed: %v", err)
+//	}
+func ResetFieldToDefault[T any](path string) error {
+	instanceMu.Lock()
+	defer instanceMu.Unlock()
+
+	cfg, err := instanceAs[T](instance)
+	if err != nil {
+		return err
+	}
+
+	field, fieldInfo, ok := fieldAtPath(reflect.ValueOf(cfg), path)
+	if !ok {
+		return fmt.Errorf("unknown field path '%s'", path)
+	}
+
+	def := fieldDefault(fieldInfo, path)
+	if def == "" {
+		field.Set(reflect.Zero(field.Type()))
+		if fieldInfo.DefaultRefs {
+			// Expand the default from the current values of its references
+			return resolveDefaultRefs(reflect.ValueOf(cfg), "")
+		}
+		return nil
+	}
+
+	parsed, err := parseFieldValue(def, fieldInfo, path)
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(parsed))
+	return nil
+}
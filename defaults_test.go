@@ -0,0 +1,146 @@
+package ahatconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSetDefaults는 SetDefaults로 지정한 기본값이 비어있는 필드를 채우고 환경변수와 파일 값보다 우선순위가 낮은지 테스트합니다
+func TestSetDefaults(t *testing.T) {
+	type DefaultsConfig struct {
+		Server struct {
+			Host    string `toml:"host" env:"HOST"`
+			Port    int    `toml:"port" env:"PORT" default:"8080"`
+			Timeout int    `toml:"timeout" env:"TIMEOUT"`
+		} `toml:"server" env:"SERVER"`
+		Name string `toml:"name" env:"NAME"`
+	}
+
+	defaults := map[string]string{
+		"server.host":    "runtimehost",
+		"server.port":    "9000",
+		"server.timeout": "30",
+		"name":           "runtime",
+	}
+
+	t.Run("env only", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "defaultsapp"
+		SetDefaults(defaults)
+		t.Setenv("DEFAULTSAPP_SERVER_HOST", "envhost")
+
+		if err := LoadConfig[DefaultsConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DefaultsConfig]()
+		if cfg.Server.Host != "envhost" {
+			t.Errorf("expected env var to override runtime default, got '%s'", cfg.Server.Host)
+		}
+		if cfg.Server.Port != 9000 {
+			t.Errorf("expected runtime default to replace default tag, got %d", cfg.Server.Port)
+		}
+		if cfg.Server.Timeout != 30 || cfg.Name != "runtime" {
+			t.Errorf("expected runtime defaults to fill unset fields, got timeout %d and name '%s'", cfg.Server.Timeout, cfg.Name)
+		}
+	})
+
+	t.Run("with config file", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "defaultsapp", "[server]\ntimeout = 5\n")
+		defer cleanup()
+		AppName = "defaultsapp"
+		SetDefaults(defaults)
+		t.Setenv("DEFAULTSAPP_NAME", "envname")
+
+		if err := LoadConfig[DefaultsConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DefaultsConfig]()
+		if cfg.Server.Timeout != 5 {
+			t.Errorf("expected file value to override runtime default, got %d", cfg.Server.Timeout)
+		}
+		if cfg.Server.Port != 9000 || cfg.Server.Host != "runtimehost" {
+			t.Errorf("expected runtime defaults for keys missing from the file, got %s:%d", cfg.Server.Host, cfg.Server.Port)
+		}
+		if cfg.Name != "envname" {
+			t.Errorf("expected env var to override runtime default, got '%s'", cfg.Name)
+		}
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "defaultsapp"
+		SetDefaults(map[string]string{"server.hots": "typo"})
+
+		err := LoadConfig[DefaultsConfig]()
+		if err == nil || !strings.Contains(err.Error(), "unknown field path 'server.hots'") {
+			t.Errorf("expected an unknown field path error, got %v", err)
+		}
+	})
+}
+
+// TestResetFieldToDefault는 ResetFieldToDefault가 필드를 기본값(없으면 0값)으로 되돌리는지 테스트합니다
+func TestResetFieldToDefault(t *testing.T) {
+	type ResetConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" default:"localhost"`
+			Port int    `toml:"port" env:"PORT" default:"8080"`
+			URL  string `toml:"url" env:"URL" default:"http://{Host}:{Port}"`
+		} `toml:"server" env:"SERVER"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+			Role string `toml:"role" env:"ROLE" default:"viewer"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "resetapp"
+	t.Setenv("RESETAPP_SERVER_PORT", "9090")
+	t.Setenv("RESETAPP_USERS_0_NAME", "alice")
+	t.Setenv("RESETAPP_USERS_0_ROLE", "admin")
+
+	if err := LoadConfig[ResetConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[ResetConfig]()
+	cfg.Server.Host = "edited"
+	cfg.Server.URL = "http://edited"
+
+	if err := ResetFieldToDefault[ResetConfig]("server.port"); err != nil {
+		t.Fatalf("ResetFieldToDefault failed: %v", err)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Errorf("expected port to return to 8080, got %d", cfg.Server.Port)
+	}
+
+	if err := ResetFieldToDefault[ResetConfig]("users[0].role"); err != nil {
+		t.Fatalf("ResetFieldToDefault failed: %v", err)
+	}
+	if cfg.Users[0].Role != "viewer" {
+		t.Errorf("expected role to return to 'viewer', got '%s'", cfg.Users[0].Role)
+	}
+
+	if err := ResetFieldToDefault[ResetConfig]("users[0].name"); err != nil {
+		t.Fatalf("ResetFieldToDefault failed: %v", err)
+	}
+	if cfg.Users[0].Name != "" {
+		t.Errorf("expected a field without default to be zeroed, got '%s'", cfg.Users[0].Name)
+	}
+
+	if err := ResetFieldToDefault[ResetConfig]("server.url"); err != nil {
+		t.Fatalf("ResetFieldToDefault failed: %v", err)
+	}
+	if cfg.Server.URL != "http://edited:8080" {
+		t.Errorf("expected url to be rebuilt from current values, got '%s'", cfg.Server.URL)
+	}
+
+	for _, path := range []string{"server.missing", "users[5].role", "users[x].role"} {
+		if err := ResetFieldToDefault[ResetConfig](path); err == nil || !strings.Contains(err.Error(), "unknown field path") {
+			t.Errorf("expected an unknown field path error for '%s', got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldDescriptor describes a single configuration field for tooling such as
+// documentation generators and admin UIs.
+type FieldDescriptor struct {
+	Path        string   // Dotted path from the config root, e.g. "server.port" or "users[].name"
+	Type        string   // Go type, e.g. "int" or "[]string"
+	EnvKey      string   // Environment variable read for the field; "{INDEX}" marks a slice index
+	Default     string   // Value of the default tag, "****" for secret fields
+	Required    bool     // Required tag
+	Secret      bool     // Secret tag
+	OneOf       []string // Allowed values from the oneof tag
+	Min         string   // Value of the min tag
+	Max         string   // Value of the max tag
+	Description string   // Value of the desc tag
+}
+
+// Describe returns a descriptor for every field of the config type T,
+// recursing into nested structs and struct slices. Sections themselves are
+// not listed, only the fields that hold values. Env keys use the current
+// AppName as prefix.
+//
+// Example:
+//
+//	for _, f := range ahatconfig.Describe[MyConfig]() {
+//	    fmt.Printf("%s\t%s\t%s\t%s\n", f.EnvKey, f.Type, f.Default, f.Description)
+//	}
+func Describe[T any]() []FieldDescriptor {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return describeStruct(t, AppName, "", map[reflect.Type]bool{}, nil)
+}
+
+// SecretFields returns the dotted paths of the fields of the config type T
+// that are masked as secrets, either by the secret tag or by the predicate set
+// with SetSecretPredicate, in the order of Describe. Fields of struct slice
+// elements appear as "users[].password". Security scanners can use it to
+// verify that every sensitive field is annotated.
+//
+// Example:
+//
+//	for _, path := range ahatconfig.SecretFields[MyConfig]() {
+//	    fmt.Println(path)
+//	}
+func SecretFields[T any]() []string {
+	var paths []string
+	for _, f := range Describe[T]() {
+		if f.Secret {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// EnvKeyFor returns the name of the environment variable the loader reads
+// for the field of the config type T at the dotted path of TOML keys, such as
+// "database.password", "users[0].name" or "hosts[1]", for the application
+// appname. Env tags, envabs tags and the slice index style are honored, so
+// deploy manifests can be generated instead of written by hand. Sections
+// have no variable of their own unless they are inline or have a format tag.
+//
+// Example:
+//
+//	key, err := ahatconfig.EnvKeyFor[MyConfig]("myapp", "database.password")
+//	// key == "MYAPP_DATABASE_PASSWORD"
+func EnvKeyFor[T any](appname, fieldPath string) (string, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	prefix := appname
+
+	segments := strings.Split(fieldPath, ".")
+	for depth, segment := range segments {
+		key, index, err := splitIndex(segment)
+		if err != nil {
+			return "", err
+		}
+		fieldInfo, ok := fieldInfoByKey(t, key)
+		if !ok {
+			return "", fmt.Errorf("unknown field path '%s'", fieldPath)
+		}
+
+		envKey := fieldEnvKey(prefix, fieldInfo)
+		last := depth == len(segments)-1
+		switch {
+		case isStructList(fieldInfo.Type):
+			if index < 0 {
+				return "", fmt.Errorf("field path '%s' needs an index for %s", fieldPath, key)
+			}
+			if last {
+				return "", fmt.Errorf("field path '%s' names a section, not a value", fieldPath)
+			}
+			t, _ = structElem(fieldInfo.Type)
+			prefix = indexEnvKey(envKey, index)
+		case index >= 0:
+			if !isScalarSlice(fieldInfo.Type) || !last {
+				return "", fmt.Errorf("unknown field path '%s'", fieldPath)
+			}
+			return indexEnvKey(envKey, index), nil
+		case isNestedStruct(fieldInfo.Type):
+			if last {
+				if fieldInfo.Inline || fieldInfo.Format != "" {
+					return envKey, nil
+				}
+				return "", fmt.Errorf("field path '%s' names a section, not a value", fieldPath)
+			}
+			t = fieldInfo.Type
+			prefix = envKey
+		default:
+			if !last {
+				return "", fmt.Errorf("unknown field path '%s'", fieldPath)
+			}
+			return envKey, nil
+		}
+	}
+	return "", fmt.Errorf("unknown field path '%s'", fieldPath)
+}
+
+// fieldInfoByKey returns the info of the field of t with the TOML key key.
+func fieldInfoByKey(t reflect.Type, key string) (FieldInfo, bool) {
+	if t.Kind() != reflect.Struct {
+		return FieldInfo{}, false
+	}
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		if fieldInfo.Key == key {
+			return fieldInfo, true
+		}
+	}
+	return FieldInfo{}, false
+}
+
+// describeStruct appends the descriptors of the fields of struct type t.
+// visiting holds the struct types being described further up; a field of one
+// of those types is described as a single field instead of being expanded
+// again, so self-referential types terminate.
+func describeStruct(t reflect.Type, prefix, path string, visiting map[reflect.Type]bool, out []FieldDescriptor) []FieldDescriptor {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		fieldPath := joinPath(path, fieldInfo.Key)
+		envKey := fieldEnvKey(prefix, fieldInfo)
+		var elemType reflect.Type
+		if isStructList(fieldInfo.Type) {
+			elemType, _ = structElem(fieldInfo.Type)
+		}
+
+		switch {
+		case isNestedStruct(fieldInfo.Type) && !visiting[fieldInfo.Type]:
+			out = describeStruct(fieldInfo.Type, envKey, fieldPath, visiting, out)
+		case fieldInfo.Type.Kind() == reflect.Slice && elemType != nil && !visiting[elemType]:
+			out = describeStruct(elemType, indexedEnvName(envKey, "{INDEX}"), fieldPath+"[]", visiting, out)
+		default:
+			var oneOf []string
+			if len(fieldInfo.OneOf) > 0 {
+				oneOf = fieldInfo.OneOf
+			}
+			secret := isSecretField(fieldInfo, fieldPath)
+			def := fieldInfo.DefaultValue
+			if secret && def != "" {
+				// Generated docs must not expose a secret default
+				def = "****"
+			}
+			out = append(out, FieldDescriptor{
+				Path:        fieldPath,
+				Type:        typeName(fieldInfo.Type),
+				EnvKey:      envKey,
+				Default:     def,
+				Required:    fieldInfo.Required,
+				Secret:      secret,
+				OneOf:       oneOf,
+				Min:         fieldInfo.Min,
+				Max:         fieldInfo.Max,
+				Description: fieldInfo.Desc,
+			})
+		}
+	}
+	return out
+}
+
+// typeName returns a readable name for t, e.g. "time.Duration" or "[]string".
+// Anonymous struct types are shown as "struct".
+func typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.String()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Elem())
+	default:
+		return strings.SplitN(t.String(), " ", 2)[0]
+	}
+}
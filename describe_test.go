@@ -0,0 +1,180 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDescribe는 Describe가 중첩 구조체와 슬라이스의 필드 메타데이터를 반환하는지 테스트합니다
+func TestDescribe(t *testing.T) {
+	type DescribeConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" default:"localhost"`
+			Port int    `toml:"port" env:"PORT" min:"1" max:"65535" required:"true"`
+		} `toml:"server" env:"SERVER"`
+		Users []struct {
+			Name     string `toml:"name" env:"NAME"`
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		} `toml:"users" env:"USERS"`
+		LogLevel string `toml:"log_level" env:"LOG_LEVEL" oneof:"debug info"`
+		Tags     []string
+	}
+
+	resetGlobalConfig()
+	AppName = "describe-app"
+
+	expected := []FieldDescriptor{
+		{Path: "server.host", Type: "string", EnvKey: "DESCRIBE_APP_SERVER_HOST", Default: "localhost"},
+		{Path: "server.port", Type: "int", EnvKey: "DESCRIBE_APP_SERVER_PORT", Required: true, Min: "1", Max: "65535"},
+		{Path: "users[].name", Type: "string", EnvKey: "DESCRIBE_APP_USERS_{INDEX}_NAME"},
+		{Path: "users[].password", Type: "string", EnvKey: "DESCRIBE_APP_USERS_{INDEX}_PASSWORD", Secret: true},
+		{Path: "log_level", Type: "string", EnvKey: "DESCRIBE_APP_LOG_LEVEL", OneOf: []string{"debug", "info"}},
+		{Path: "tags", Type: "[]string", EnvKey: "DESCRIBE_APP_TAGS"},
+	}
+
+	got := Describe[DescribeConfig]()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected descriptors:\n got: %+v\nwant: %+v", got, expected)
+	}
+}
+
+// TestDescribeNonStruct는 구조체가 아닌 타입에 대해 nil을 반환하는지 테스트합니다
+func TestDescribeNonStruct(t *testing.T) {
+	if got := Describe[string](); got != nil {
+		t.Errorf("expected nil for non-struct type, got %+v", got)
+	}
+}
+
+// TestDescribeDescription은 desc 태그의 설명이 Describe 결과와 JSON 스키마에 포함되는지 테스트합니다
+func TestDescribeDescription(t *testing.T) {
+	type DescConfig struct {
+		Server struct {
+			Port int `toml:"port" env:"PORT" default:"8080" desc:"Port the HTTP server listens on"`
+		} `toml:"server" env:"SERVER"`
+		Name string `toml:"name" env:"NAME"`
+	}
+
+	resetGlobalConfig()
+	AppName = "descapp"
+
+	got := Describe[DescConfig]()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 descriptors, got %+v", got)
+	}
+	if got[0].EnvKey != "DESCAPP_SERVER_PORT" || got[0].Description != "Port the HTTP server listens on" {
+		t.Errorf("expected the desc text on server.port, got %+v", got[0])
+	}
+	if got[1].Description != "" {
+		t.Errorf("expected no description without a desc tag, got '%s'", got[1].Description)
+	}
+
+	schema, err := JSONSchema[DescConfig]()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	if !strings.Contains(string(schema), `"description": "Port the HTTP server listens on"`) {
+		t.Errorf("expected the description in the schema, got %s", schema)
+	}
+}
+
+// TestSecretFields는 SecretFields가 중첩 구조체와 슬라이스 요소를 포함한 모든 시크릿 필드의 경로를 반환하는지 테스트합니다
+func TestSecretFields(t *testing.T) {
+	type Credential struct {
+		Name  string `toml:"name"`
+		Token string `toml:"token" secret:"true"`
+	}
+	type SecretFieldsConfig struct {
+		Server struct {
+			Host string `toml:"host"`
+			TLS  struct {
+				Key string `toml:"key" secret:"true"`
+			} `toml:"tls"`
+		} `toml:"server"`
+		Database struct {
+			User     string `toml:"user"`
+			Password string `toml:"password" secret:"true"`
+		} `toml:"database"`
+		Credentials []Credential  `toml:"credentials"`
+		Backups     []*Credential `toml:"backups"`
+		APIKey      string        `toml:"api_key" secret:"true"`
+	}
+
+	want := []string{"server.tls.key", "database.password", "credentials[].token", "backups[].token", "api_key"}
+	if got := SecretFields[SecretFieldsConfig](); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := SecretFields[string](); got != nil {
+		t.Errorf("expected no secret fields for a non-struct type, got %v", got)
+	}
+}
+
+// TestEnvKeyFor는 EnvKeyFor가 반환한 환경변수 이름이 로더가 실제로 읽는 이름과 같은지 테스트합니다
+func TestEnvKeyFor(t *testing.T) {
+	type EnvKeyUser struct {
+		Name    string `toml:"name" env:"NAME"`
+		Profile struct {
+			Email string `toml:"email"`
+		} `toml:"profile" env:"PROFILE"`
+	}
+	type EnvKeyConfig struct {
+		Database struct {
+			Password string `toml:"password" env:"PASS" secret:"true"`
+			Port     int    `toml:"port"`
+		} `toml:"database" env:"DB"`
+		URL   string       `toml:"url" envabs:"ENVKEY_TEST_URL"`
+		Hosts []string     `toml:"hosts" env:"HOSTS"`
+		Users []EnvKeyUser `toml:"users" env:"USERS"`
+	}
+
+	defer resetGlobalConfig()
+
+	values := map[string]string{
+		"database.password":      "hunter2",
+		"database.port":          "5432",
+		"url":                    "http://example.com",
+		"hosts[0]":               "a.example.com",
+		"hosts[1]":               "b.example.com",
+		"users[0].name":          "alice",
+		"users[0].profile.email": "alice@example.com",
+	}
+
+	for _, style := range []SliceIndexStyle{UnderscoreIndex, BracketIndex} {
+		t.Run(fmt.Sprintf("style %d", style), func(t *testing.T) {
+			resetGlobalConfig()
+			SetSliceIndexStyle(style)
+			for path, value := range values {
+				key, err := EnvKeyFor[EnvKeyConfig]("env-key", path)
+				if err != nil {
+					t.Fatalf("EnvKeyFor(%s) failed: %v", path, err)
+				}
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadWithPrefix[EnvKeyConfig]("env-key")
+			if err != nil {
+				t.Fatalf("LoadWithPrefix failed: %v", err)
+			}
+			if cfg.Database.Password != "hunter2" || cfg.Database.Port != 5432 || cfg.URL != "http://example.com" {
+				t.Errorf("unexpected values %+v", *cfg)
+			}
+			if !reflect.DeepEqual(cfg.Hosts, []string{"a.example.com", "b.example.com"}) {
+				t.Errorf("unexpected hosts %v", cfg.Hosts)
+			}
+			if len(cfg.Users) != 1 || cfg.Users[0].Name != "alice" || cfg.Users[0].Profile.Email != "alice@example.com" {
+				t.Errorf("unexpected users %+v", cfg.Users)
+			}
+		})
+	}
+
+	if key, _ := EnvKeyFor[EnvKeyConfig]("env-key", "database.password"); key != "ENV_KEY_DB_PASS" {
+		t.Errorf("expected ENV_KEY_DB_PASS, got %s", key)
+	}
+	for _, path := range []string{"database", "users.name", "users[0]", "missing", "database.port.x"} {
+		if _, err := EnvKeyFor[EnvKeyConfig]("env-key", path); err == nil {
+			t.Errorf("expected an error for path %q", path)
+		}
+	}
+}
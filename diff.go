@@ -0,0 +1,129 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldChange describes a field whose value differs between two configs.
+// Values of secret fields are reported as "****".
+type FieldChange struct {
+	Path string      // Dotted path from the config root, e.g. "server.port" or "users[1].role"
+	Old  interface{} // Value in the old config, nil for an added slice element
+	New  interface{} // Value in the new config, nil for a removed slice element
+}
+
+// Diff returns the fields whose values differ between old and new, in field
+// order. Nested structs are compared field by field and struct slices element
+// by element; other values are compared as a whole.
+//
+// Example:
+//
+//	for _, change := range ahatconfig.Diff(previous, current) {
+//	    log.Printf("%s: %v -> %v", change.Path, change.Old, change.New)
+//	}
+func Diff[T any](old, new *T) []FieldChange {
+	return diffValues(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem(), "", nil)
+}
+
+// diffValues appends the changes between the struct values old and new,
+// whose dotted path is path.
+func diffValues(old, new reflect.Value, path string, changes []FieldChange) []FieldChange {
+	for i, fieldInfo := range getCachedTypeInfo(old.Type()).Fields {
+		oldField, newField := old.Field(i), new.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch {
+		case isNestedStruct(fieldInfo.Type):
+			changes = diffValues(oldField, newField, fieldPath, changes)
+		case isStructList(fieldInfo.Type):
+			for j := 0; j < oldField.Len() || j < newField.Len(); j++ {
+				elemPath := indexPath(fieldPath, j)
+				switch {
+				case j >= newField.Len():
+					changes = append(changes, FieldChange{Path: elemPath, Old: changeValue(oldField.Index(j), fieldPath, fieldInfo)})
+				case j >= oldField.Len():
+					changes = append(changes, FieldChange{Path: elemPath, New: changeValue(newField.Index(j), fieldPath, fieldInfo)})
+				default:
+					oldElem, newElem := derefElem(oldField.Index(j)), derefElem(newField.Index(j))
+					if oldElem.IsValid() && newElem.IsValid() {
+						changes = diffValues(oldElem, newElem, elemPath, changes)
+					} else if oldElem.IsValid() || newElem.IsValid() {
+						// One of the elements is a nil pointer
+						changes = append(changes, FieldChange{
+							Path: elemPath,
+							Old:  changeValue(oldField.Index(j), fieldPath, fieldInfo),
+							New:  changeValue(newField.Index(j), fieldPath, fieldInfo),
+						})
+					}
+				}
+			}
+		case !oldField.CanInterface():
+			// Unexported fields are not part of the configuration
+		case !reflect.DeepEqual(oldField.Interface(), newField.Interface()):
+			changes = append(changes, FieldChange{
+				Path: fieldPath,
+				Old:  changeValue(oldField, fieldPath, fieldInfo),
+				New:  changeValue(newField, fieldPath, fieldInfo),
+			})
+		}
+	}
+	return changes
+}
+
+// derefElem returns the struct held by a slice element, looking through
+// pointers. The result is invalid for a nil pointer.
+func derefElem(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// changeValue returns the value of v for a FieldChange, masked when the field
+// at path is secret.
+func changeValue(v reflect.Value, path string, fieldInfo FieldInfo) interface{} {
+	if isSecretField(fieldInfo, path) {
+		return "****"
+	}
+	return v.Interface()
+}
+
+// EqualsTOML compares the loaded configuration with a golden TOML document,
+// built into the same type like a load but without environment overrides,
+// and returns the fields that differ. Defaults, default references, secret
+// references and transforms apply to the golden document too, and it must
+// pass validation. Old is the loaded value and New the value from data.
+// It is meant for config regression tests that catch unintended drift.
+//
+// Example:
+//
+//	golden, _ := os.ReadFile("testdata/myapp.golden.toml")
+//	equal, changes, err := ahatconfig.EqualsTOML(golden)
+//	if err != nil || !equal {
+//	    t.Errorf("config drifted from golden file: %v %+v", err, changes)
+//	}
+func EqualsTOML(data []byte) (bool, []FieldChange, error) {
+	current := currentInstance()
+	if current == nil {
+		return false, nil, fmt.Errorf("config not initialized, call InitConfig first")
+	}
+
+	loaded := reflect.ValueOf(current)
+	golden := reflect.New(loaded.Type().Elem())
+	// The golden config is built like the loaded one, minus the environment
+	_, err := buildInto(golden.Interface(), AppName, "", nil, func() error {
+		return decodeDocument(data, "toml", golden.Interface())
+	})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to decode golden TOML: %w", err)
+	}
+
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	changes := diffValues(loaded.Elem(), golden.Elem(), "", nil)
+	return len(changes) == 0, changes, nil
+}
@@ -0,0 +1,127 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestEqualsTOML은 로드된 설정을 골든 TOML과 비교해 일치 여부와 달라진 필드를 보고하는지 테스트합니다
+func TestEqualsTOML(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "goldenapp", `[server]
+host = "localhost"
+port = 8080
+
+[database]
+user = "admin"
+password = "secret"
+
+[[users]]
+name = "alice"
+role = "admin"
+`)
+	defer cleanup()
+	AppName = "goldenapp"
+	if err := LoadConfig[TestConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		equal, changes, err := EqualsTOML([]byte(`[server]
+host = "localhost"
+
+[database]
+user = "admin"
+password = "secret"
+
+[[users]]
+name = "alice"
+role = "admin"
+`))
+		if err != nil {
+			t.Fatalf("EqualsTOML failed: %v", err)
+		}
+		if !equal || len(changes) != 0 {
+			t.Errorf("expected the golden file to match, got changes %+v", changes)
+		}
+	})
+
+	t.Run("mismatching", func(t *testing.T) {
+		equal, changes, err := EqualsTOML([]byte(`[server]
+host = "localhost"
+port = 9090
+
+[database]
+user = "admin"
+password = "other"
+
+[[users]]
+name = "alice"
+role = "viewer"
+
+[[users]]
+name = "bob"
+`))
+		if err != nil {
+			t.Fatalf("EqualsTOML failed: %v", err)
+		}
+		if equal {
+			t.Error("expected the golden file not to match")
+		}
+
+		expected := []FieldChange{
+			{Path: "server.port", Old: 8080, New: 9090},
+			{Path: "database.password", Old: "****", New: "****"},
+			{Path: "users[0].role", Old: "admin", New: "viewer"},
+			{Path: "users[1]", New: struct {
+				Name string `toml:"name" env:"NAME"`
+				Role string `toml:"role" env:"ROLE"`
+			}{Name: "bob"}},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Errorf("unexpected changes:\n got: %+v\nwant: %+v", changes, expected)
+		}
+	})
+
+	t.Run("invalid golden", func(t *testing.T) {
+		if _, _, err := EqualsTOML([]byte("[server")); err == nil || !strings.Contains(err.Error(), "golden") {
+			t.Errorf("expected a decode error, got %v", err)
+		}
+	})
+}
+
+// TestEqualsTOMLPipeline는 골든 문서에도 변환, 기본값 참조, 런타임 기본값이 적용되고 환경변수는 적용되지 않는지 테스트합니다
+func TestEqualsTOMLPipeline(t *testing.T) {
+	type PipelineConfig struct {
+		Role    string `toml:"role" env:"ROLE" transform:"lower"`
+		Host    string `toml:"host" env:"HOST"`
+		BindTo  string `toml:"bind_to" env:"BIND_TO" default:"{Host}"`
+		Timeout int    `toml:"timeout" env:"TIMEOUT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "goldenpipe", "role = \"Admin\"\nhost = \"db.local\"\n")
+	defer cleanup()
+	AppName = "goldenpipe"
+	SetDefaults(map[string]string{"timeout": "30"})
+	if err := LoadConfig[PipelineConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	equal, changes, err := EqualsTOML([]byte("role = \"ADMIN\"\nhost = \"db.local\"\n"))
+	if err != nil {
+		t.Fatalf("EqualsTOML failed: %v", err)
+	}
+	if !equal {
+		t.Errorf("expected the golden file to match after the pipeline, got changes %+v", changes)
+	}
+
+	// 환경변수는 골든 문서에 적용되지 않는다
+	t.Setenv("GOLDENPIPE_HOST", "env.local")
+	if equal, changes, err := EqualsTOML([]byte("role = \"admin\"\nhost = \"env.local\"\n")); err != nil || equal {
+		t.Errorf("expected the golden host to be compared without env overrides, got %v %+v %v", equal, changes, err)
+	}
+}
@@ -0,0 +1,78 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// InitConfigFromDir initializes configuration from a directory holding one
+// file per value, such as a mounted Kubernetes ConfigMap. Each file name is
+// an environment-style key without the app prefix (SERVER_HOST sets the
+// server.host field), and the file content is the value with trailing
+// newlines removed. Hidden entries and subdirectories are ignored.
+// The directory is used as the base layer instead of the TOML file, and
+// environment variables are applied on top of it.
+//
+// Example:
+//
+//	err := ahatconfig.InitConfigFromDir[MyConfig]("myapp", "/etc/myapp")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func InitConfigFromDir[T any](appname, dir string) error {
+	AppName = appname
+
+	return loadConfig(func(cfg *T) error {
+		values, err := readKeyFiles(dir)
+		if err != nil {
+			return err
+		}
+		return loadStructEnv(dirLookup(values, AppName), reflect.ValueOf(cfg).Elem(), AppName, "")
+	})
+}
+
+// readKeyFiles reads the files of dir into a map keyed by the normalized
+// file name.
+func readKeyFiles(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		// Kubernetes keeps its bookkeeping in hidden entries such as ..data
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", entry.Name(), err)
+		}
+		values[normalizeKeyFileName(entry.Name())] = strings.TrimRight(string(data), "\r\n")
+	}
+	return values, nil
+}
+
+// normalizeKeyFileName converts a file name to an environment-style key:
+// upper case with hyphens and dots replaced by underscores.
+func normalizeKeyFileName(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
+}
+
+// dirLookup looks up env keys in values, which are keyed without the app
+// prefix. Keys from envabs tags are looked up as they are.
+func dirLookup(values map[string]string, appname string) envLookup {
+	prefix := strings.ReplaceAll(strings.ToUpper(appname), "-", "_") + "_"
+	return func(key string) (string, bool) {
+		if value, ok := values[strings.TrimPrefix(key, prefix)]; ok {
+			return value, true
+		}
+		value, ok := values[key]
+		return value, ok
+	}
+}
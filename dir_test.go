@@ -0,0 +1,105 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeKeyFiles는 디렉토리에 키별 파일을 생성합니다
+func writeKeyFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write key file %s: %v", name, err)
+		}
+	}
+}
+
+// TestInitConfigFromDir는 키별 파일 디렉토리에서 설정을 로드하고 환경변수가 덮어쓰는지 테스트합니다
+func TestInitConfigFromDir(t *testing.T) {
+	type DirConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT" default:"8080"`
+		} `toml:"server" env:"SERVER"`
+		Database struct {
+			User     string `toml:"user" env:"USER"`
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		} `toml:"database" env:"DATABASE"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+		} `toml:"users" env:"USERS"`
+		Region string `toml:"region" envabs:"REGION"`
+	}
+
+	resetGlobalConfig()
+	dir := t.TempDir()
+	writeKeyFiles(t, dir, map[string]string{
+		"SERVER_HOST":       "dirhost\n",
+		"database-user":     "admin",
+		"DATABASE_PASSWORD": "hunter2\n",
+		"USERS_0_NAME":      "alice",
+		"USERS_1_NAME":      "bob",
+		"REGION":            "eu-west-1",
+		".hidden":           "ignored",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "..data"), 0755); err != nil {
+		t.Fatalf("failed to create hidden dir: %v", err)
+	}
+
+	t.Setenv("DIRAPP_DATABASE_USER", "envuser")
+
+	if err := InitConfigFromDir[DirConfig]("dirapp", dir); err != nil {
+		t.Fatalf("InitConfigFromDir failed: %v", err)
+	}
+
+	cfg := GetConfig[DirConfig]()
+	if cfg.Server.Host != "dirhost" {
+		t.Errorf("expected host 'dirhost' without trailing newline, got %q", cfg.Server.Host)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Errorf("expected default port 8080, got %d", cfg.Server.Port)
+	}
+	if cfg.Database.User != "envuser" {
+		t.Errorf("expected env var to override directory value, got %q", cfg.Database.User)
+	}
+	if cfg.Database.Password != "hunter2" {
+		t.Errorf("expected password 'hunter2', got %q", cfg.Database.Password)
+	}
+	var names []string
+	for _, u := range cfg.Users {
+		names = append(names, u.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"alice", "bob"}) {
+		t.Errorf("expected users [alice bob], got %v", names)
+	}
+	if cfg.Region != "eu-west-1" {
+		t.Errorf("expected envabs field to read REGION file, got %q", cfg.Region)
+	}
+}
+
+// TestInitConfigFromDirErrors는 디렉토리가 없거나 필수 필드가 빠진 경우 오류를 반환하는지 테스트합니다
+func TestInitConfigFromDirErrors(t *testing.T) {
+	type DirRequiredConfig struct {
+		Host string `toml:"host" env:"HOST" required:"true"`
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		resetGlobalConfig()
+		err := InitConfigFromDir[DirRequiredConfig]("dirmissing", filepath.Join(t.TempDir(), "missing"))
+		if err == nil || !strings.Contains(err.Error(), "failed to read config directory") {
+			t.Errorf("expected a directory read error, got %v", err)
+		}
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		resetGlobalConfig()
+		err := InitConfigFromDir[DirRequiredConfig]("dirrequired", t.TempDir())
+		if err == nil || !strings.Contains(err.Error(), "required field 'HOST' is missing or empty") {
+			t.Errorf("expected a required field error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package ahatconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+)
+
+// decodeEncodedValue decodes raw according to the encoding tag of a field.
+func decodeEncodedValue(raw, encoding string) ([]byte, error) {
+	switch encoding {
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 value: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding '%s'", encoding)
+	}
+}
+
+// parseEncodedValue decodes an encoded env or default value into the string
+// or []byte type of fieldInfo.
+func parseEncodedValue(raw string, fieldInfo FieldInfo) (interface{}, error) {
+	decoded, err := decodeEncodedValue(raw, fieldInfo.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case fieldInfo.Type.Kind() == reflect.String:
+		return reflect.ValueOf(string(decoded)).Convert(fieldInfo.Type).Interface(), nil
+	case isByteSlice(fieldInfo.Type):
+		return reflect.ValueOf(decoded).Convert(fieldInfo.Type).Interface(), nil
+	default:
+		return nil, fmt.Errorf("encoding '%s' requires a string or []byte field", fieldInfo.Encoding)
+	}
+}
+
+// isByteSlice reports whether t is a byte slice such as []byte.
+func isByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// decodeEncodedFields decodes, in place, the values of fields with an
+// encoding tag that were read from a config document.
+func decodeEncodedFields(v reflect.Value, path string) error {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	typeInfo := getCachedTypeInfo(v.Type())
+
+	for i, fieldInfo := range typeInfo.Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		if isNestedStruct(value.Type()) {
+			if err := decodeEncodedFields(value, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if isStructList(fieldInfo.Type) {
+			for j := 0; j < value.Len(); j++ {
+				if err := decodeEncodedFields(value.Index(j), indexPath(fieldPath, j)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if fieldInfo.Encoding == "" || isZero(value) {
+			continue
+		}
+
+		var raw string
+		switch {
+		case value.Kind() == reflect.String:
+			raw = value.String()
+		case isByteSlice(fieldInfo.Type):
+			raw = string(value.Bytes())
+		}
+
+		decoded, err := parseEncodedValue(raw, fieldInfo)
+		if err != nil {
+			return fmt.Errorf("failed to decode field %s: %w", fieldPath, err)
+		}
+		value.Set(reflect.ValueOf(decoded))
+	}
+
+	return nil
+}
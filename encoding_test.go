@@ -0,0 +1,75 @@
+package ahatconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBase64Encoding은 encoding:"base64" 태그가 환경변수와 TOML 값을 디코딩하는지 테스트합니다
+func TestBase64Encoding(t *testing.T) {
+	type EncodedConfig struct {
+		Password string `toml:"password" env:"PASSWORD" encoding:"base64" secret:"true"`
+		Key      []byte `toml:"key" env:"KEY" encoding:"base64"`
+		Users    []struct {
+			Token string `toml:"token" env:"TOKEN" encoding:"base64"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	t.Run("env values", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "BASE64APP"
+		t.Setenv("BASE64APP_PASSWORD", "aHVudGVyMg==")
+		t.Setenv("BASE64APP_KEY", "AAEC/w==")
+		t.Setenv("BASE64APP_USERS_0_TOKEN", "dG9rZW4=")
+
+		cfg := new(EncodedConfig)
+		if err := loadConfigEnv(cfg); err != nil {
+			t.Fatalf("loadConfigEnv failed: %v", err)
+		}
+		if cfg.Password != "hunter2" {
+			t.Errorf("expected decoded password 'hunter2', got %q", cfg.Password)
+		}
+		if string(cfg.Key) != "\x00\x01\x02\xff" {
+			t.Errorf("expected decoded key bytes, got %v", cfg.Key)
+		}
+		if len(cfg.Users) != 1 || cfg.Users[0].Token != "token" {
+			t.Errorf("expected decoded slice element token, got %+v", cfg.Users)
+		}
+	})
+
+	t.Run("TOML values", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "base64toml"
+		_, cleanup := createTestTomlFile(t, appName, "password = \"aHVudGVyMg==\"\n[[users]]\ntoken = \"dG9rZW4=\"\n")
+		defer cleanup()
+
+		AppName = appName
+		if err := LoadConfig[EncodedConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[EncodedConfig]()
+		if cfg.Password != "hunter2" {
+			t.Errorf("expected decoded password 'hunter2', got %q", cfg.Password)
+		}
+		if len(cfg.Users) != 1 || cfg.Users[0].Token != "token" {
+			t.Errorf("expected decoded slice element token, got %+v", cfg.Users)
+		}
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "BASE64BAD"
+		t.Setenv("BASE64BAD_PASSWORD", "not base64!")
+
+		err := loadConfigEnv(new(EncodedConfig))
+		if err == nil {
+			t.Fatal("expected an error for invalid base64, but got nil")
+		}
+		if !strings.Contains(err.Error(), "field password: invalid base64 value") {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if strings.Contains(err.Error(), "not base64!") {
+			t.Errorf("error leaks the raw value: %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package ahatconfig
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// envSnapshot is a copy of the environment variables named after a prefix,
+// or after one of the prefixes set with SetEnvPrefixFallbacks, taken when a
+// load starts. A load reads its variables through the snapshot, so a
+// variable changed while it runs cannot leave it with a mix of old and new
+// values. Other names, such as those of envabs tags, are looked up when they
+// are needed.
+type envSnapshot struct {
+	prefixes []string
+	vars     map[string]string
+}
+
+// takeEnvSnapshot copies the environment variables named after prefix and
+// its fallback prefixes.
+func takeEnvSnapshot(prefix string) *envSnapshot {
+	s := &envSnapshot{
+		prefixes: []string{envPrefix(prefix)},
+		vars:     make(map[string]string),
+	}
+	for _, fallback := range envPrefixFallbacks {
+		s.prefixes = append(s.prefixes, envPrefix(fallback))
+	}
+
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok && s.scoped(name) {
+			s.vars[name] = value
+		}
+	}
+	return s
+}
+
+// scoped reports whether name is one of the names the snapshot copies.
+func (s *envSnapshot) scoped(name string) bool {
+	for _, p := range s.prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup is the envLookup of the snapshot: it answers for the names the
+// snapshot copies from the copy and looks up other names.
+func (s *envSnapshot) lookup(key string) (string, bool) {
+	if !s.scoped(key) {
+		return lookupEnv(key)
+	}
+	value, ok := s.vars[key]
+	return value, ok
+}
+
+// names returns the sorted names of the copied variables.
+func (s *envSnapshot) names() []string {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildEnv is the snapshot of the build in progress, guarded by buildMu.
+var buildEnv *envSnapshot
+
+// buildLookup looks up an environment variable for the build in progress,
+// through its snapshot. Outside builds it reads the environment.
+func buildLookup(key string) (string, bool) {
+	if buildEnv == nil {
+		return lookupEnv(key)
+	}
+	return buildEnv.lookup(key)
+}
+
+// buildGetenv is getEnv for the build in progress, see buildLookup.
+func buildGetenv(key string) string {
+	value, _ := buildLookup(key)
+	return value
+}
+
+// envPrefix returns the start of the environment variable names under
+// prefix, e.g. "MY_APP_" for "my-app".
+func envPrefix(prefix string) string {
+	return strings.ReplaceAll(strings.ToUpper(prefix), "-", "_") + "_"
+}
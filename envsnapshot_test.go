@@ -0,0 +1,98 @@
+package ahatconfig
+
+import (
+	"os"
+	"testing"
+)
+
+// TestEnvSnapshot는 로드 중에 바뀐 환경변수가 아니라 로드 시작 시점의 값이 사용되는지 테스트합니다
+func TestEnvSnapshot(t *testing.T) {
+	type SnapshotConfig struct {
+		First  string `env:"FIRST" transformfn:"mutateEnv"`
+		Second string `env:"SECOND"`
+		Third  string `env:"THIRD"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	t.Setenv("SNAPSHOTAPP_FIRST", "first")
+	t.Setenv("SNAPSHOTAPP_SECOND", "before")
+
+	// The transform of the first field runs mid-load and changes the
+	// variables of the fields loaded after it
+	RegisterTransform("mutateEnv", func(s string) (string, error) {
+		os.Setenv("SNAPSHOTAPP_SECOND", "after")
+		os.Setenv("SNAPSHOTAPP_THIRD", "added")
+		return s, nil
+	})
+	t.Setenv("SNAPSHOTAPP_THIRD", "")
+	os.Unsetenv("SNAPSHOTAPP_THIRD")
+
+	if err := InitConfigSafe[SnapshotConfig]("snapshotapp"); err != nil {
+		t.Fatalf("InitConfigSafe failed: %v", err)
+	}
+	cfg := GetConfig[SnapshotConfig]()
+
+	if cfg.Second != "before" {
+		t.Errorf("expected the value at the start of the load, got %q", cfg.Second)
+	}
+	if cfg.Third != "" {
+		t.Errorf("expected a variable set mid-load to be ignored, got %q", cfg.Third)
+	}
+
+	// The next load sees the changes
+	if err := InitConfigSafe[SnapshotConfig]("snapshotapp"); err != nil {
+		t.Fatalf("InitConfigSafe failed: %v", err)
+	}
+	if cfg := GetConfig[SnapshotConfig](); cfg.Second != "after" || cfg.Third != "added" {
+		t.Errorf("expected the next load to read the new values, got %+v", *cfg)
+	}
+}
+
+// TestEnvSnapshotSources는 파일 선택, ${VAR} 참조, 시크릿 참조, 보고서의 알 수 없는 변수도 로드 시작 시점의 스냅샷을 사용하는지 테스트합니다
+func TestEnvSnapshotSources(t *testing.T) {
+	type SourcesConfig struct {
+		Region string `toml:"region" env:"REGION" transformfn:"mutateSources"`
+		URL    string `toml:"url" env:"URL"`
+		Token  string `toml:"token" env:"TOKEN" secret:"true"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	path, cleanup := createTestTomlFile(t, "snapsources", "region = \"eu\"\nurl = \"https://${SNAPSOURCES_HOST}\"\ntoken = \"vault://${SNAPSOURCES_TOKEN_NAME}\"\n")
+	defer cleanup()
+	t.Setenv("SNAPSOURCES_CONFIG_FILE", path)
+	t.Setenv("SNAPSOURCES_HOST", "before.example.com")
+	t.Setenv("SNAPSOURCES_TOKEN_NAME", "before")
+	t.Setenv("SNAPSOURCES_EXTRA", "")
+	os.Unsetenv("SNAPSOURCES_EXTRA")
+
+	RegisterSecretProvider("vault", func(ref string) (string, error) {
+		return "token-" + ref, nil
+	})
+	// The transform of the region runs while the file is decoded, before
+	// the references are expanded and the secret is resolved
+	RegisterTransform("mutateSources", func(s string) (string, error) {
+		os.Setenv("SNAPSOURCES_CONFIG_FILE", path+".missing")
+		os.Setenv("SNAPSOURCES_HOST", "after.example.com")
+		os.Setenv("SNAPSOURCES_TOKEN_NAME", "after")
+		os.Setenv("SNAPSOURCES_EXTRA", "added")
+		return s, nil
+	})
+
+	cfg, report, err := LoadWithReport[SourcesConfig]("snapsources")
+	if err != nil {
+		t.Fatalf("LoadWithReport failed: %v", err)
+	}
+	if cfg.URL != "https://before.example.com" {
+		t.Errorf("expected the reference to use the snapshot, got %q", cfg.URL)
+	}
+	if cfg.Token != "token-vault://before" {
+		t.Errorf("expected the secret reference to use the snapshot, got %q", cfg.Token)
+	}
+	for _, name := range report.UnknownEnv {
+		if name == "SNAPSOURCES_EXTRA" {
+			t.Errorf("expected a variable set mid-load to be left out of the report, got %v", report.UnknownEnv)
+		}
+	}
+}
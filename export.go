@@ -0,0 +1,221 @@
+package ahatconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AsMap converts the loaded configuration into a nested map keyed by the TOML
+// keys of the fields, the same keys as in the config file and in the paths of
+// Values and Describe. Struct slices become slices of maps. It returns nil
+// when the config is not initialized. This eases interop with libraries that
+// expect a generic map, such as viper.
+//
+// Example:
+//
+//	settings := ahatconfig.AsMap()
+//	port := settings["server"].(map[string]interface{})["port"]
+func AsMap() map[string]interface{} {
+	return configAsMap(false)
+}
+
+// AsMaskedMap is like AsMap but replaces the values of secret fields with "****".
+func AsMaskedMap() map[string]interface{} {
+	return configAsMap(true)
+}
+
+func configAsMap(mask bool) map[string]interface{} {
+	current := currentInstance()
+	if current == nil {
+		return nil
+	}
+	m, _ := valueAsMap(reflect.ValueOf(current), "", mask).(map[string]interface{})
+	return m
+}
+
+// valueAsMap converts v, whose dotted path from the config root is path,
+// into nested maps and slices of plain values.
+func valueAsMap(v reflect.Value, path string, mask bool) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface()
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		typeInfo := getCachedTypeInfo(t)
+		result := make(map[string]interface{}, len(typeInfo.Fields))
+
+		for i, fieldInfo := range typeInfo.Fields {
+			fieldPath := joinPath(path, fieldInfo.Key)
+			if mask && isSecretField(fieldInfo, fieldPath) {
+				result[fieldInfo.Key] = "****"
+				continue
+			}
+			result[fieldInfo.Key] = valueAsMap(v.Field(i), fieldPath, mask)
+		}
+		return result
+
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		// Byte slices and slices of scalars are kept as they are
+		if !isCompositeKind(v.Type().Elem().Kind()) {
+			return v.Interface()
+		}
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			result[i] = valueAsMap(v.Index(i), indexPath(path, i), mask)
+		}
+		return result
+
+	case v.Kind() == reflect.Map:
+		if !isCompositeKind(v.Type().Elem().Kind()) {
+			return v.Interface()
+		}
+		result := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			result[key] = valueAsMap(iter.Value(), joinPath(path, key), mask)
+		}
+		return result
+
+	default:
+		return v.Interface()
+	}
+}
+
+// isCompositeKind reports whether values of kind k are converted to maps or
+// slices by valueAsMap.
+func isCompositeKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// PathValue is a configuration value with its dotted path from the config
+// root, e.g. "server.port" or "users[1].role".
+type PathValue struct {
+	Path  string
+	Value interface{}
+}
+
+// orderedFields holds the fields of a struct as name/value pairs in
+// declaration order, the representation PrintConfig and FprintConfig print
+// structs in. It marshals to a JSON object that keeps that order, so the
+// printed config lists fields in the order of Values and Describe.
+type orderedFields []PathValue
+
+// MarshalJSON writes the fields as a JSON object in declaration order.
+func (f orderedFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.Path)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Values returns every value of the loaded configuration as path/value
+// pairs. The order is deterministic: fields in declaration order (the order
+// of Describe), slice elements by index and map entries by sorted key, so the
+// result can be diffed or written to docs as is. It returns nil when the
+// config is not initialized.
+//
+// Example:
+//
+//	for _, v := range ahatconfig.MaskedValues() {
+//	    fmt.Printf("%s = %v\n", v.Path, v.Value)
+//	}
+func Values() []PathValue {
+	return configValues(false)
+}
+
+// MaskedValues is like Values but replaces the values of secret fields with "****".
+func MaskedValues() []PathValue {
+	return configValues(true)
+}
+
+func configValues(mask bool) []PathValue {
+	current := currentInstance()
+	if current == nil {
+		return nil
+	}
+	return appendValues(nil, reflect.ValueOf(current).Elem(), "", mask)
+}
+
+// appendValues appends the values held by the struct v, whose dotted path is
+// path. Nested structs, struct slices and maps of structs are expanded, also
+// through pointers; nil sections are single nil values, as are other fields.
+func appendValues(out []PathValue, v reflect.Value, path string, mask bool) []PathValue {
+	if err := checkDepth(path); err != nil {
+		return append(out, PathValue{Path: path, Value: err.Error()})
+	}
+
+	for i, fieldInfo := range getCachedTypeInfo(v.Type()).Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch {
+		case !value.CanInterface():
+			// Unexported fields are not part of the configuration
+		case mask && isSecretField(fieldInfo, fieldPath):
+			out = append(out, PathValue{Path: fieldPath, Value: "****"})
+		case isNestedStruct(fieldInfo.Type):
+			out = appendValues(out, value, fieldPath, mask)
+		case fieldInfo.Type.Kind() == reflect.Ptr && isNestedStruct(fieldInfo.Type.Elem()):
+			// Pointer sections are expanded unless nil
+			if value.IsNil() {
+				out = append(out, PathValue{Path: fieldPath, Value: nil})
+			} else {
+				out = appendValues(out, value.Elem(), fieldPath, mask)
+			}
+		case isStructList(fieldInfo.Type):
+			for j := 0; j < value.Len(); j++ {
+				if elem := derefElem(value.Index(j)); elem.IsValid() {
+					out = appendValues(out, elem, indexPath(fieldPath, j), mask)
+				}
+			}
+		case value.Kind() == reflect.Map && isStructMapElem(fieldInfo.Type.Elem()):
+			keys := value.MapKeys()
+			sort.Slice(keys, func(a, b int) bool {
+				return fmt.Sprint(keys[a].Interface()) < fmt.Sprint(keys[b].Interface())
+			})
+			for _, key := range keys {
+				keyPath := joinPath(fieldPath, fmt.Sprint(key.Interface()))
+				if elem := derefElem(value.MapIndex(key)); elem.IsValid() {
+					out = appendValues(out, elem, keyPath, mask)
+				} else {
+					out = append(out, PathValue{Path: keyPath, Value: nil})
+				}
+			}
+		default:
+			out = append(out, PathValue{Path: fieldPath, Value: value.Interface()})
+		}
+	}
+	return out
+}
@@ -0,0 +1,297 @@
+package ahatconfig
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAsMap는 AsMap이 태그 계층을 따르는 중첩 맵을 반환하는지 테스트합니다
+func TestAsMap(t *testing.T) {
+	type MapExportConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+		Database struct {
+			Password string   `toml:"password" env:"PASSWORD" secret:"true"`
+			Hosts    []string `toml:"hosts" env:"HOSTS"`
+		} `env:"DATABASE"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+		} `toml:"users" env:"USERS"`
+		Enabled    bool
+		MaxConns   int `toml:"maxConns" env:"MAX_CONNS"`
+		RetryCount int `env:"RETRIES"`
+	}
+
+	resetGlobalConfig()
+	if AsMap() != nil {
+		t.Error("expected AsMap to return nil before initialization")
+	}
+
+	AppName = "MAPEXPORT"
+	t.Setenv("MAPEXPORT_SERVER_HOST", "localhost")
+	t.Setenv("MAPEXPORT_SERVER_PORT", "8080")
+	t.Setenv("MAPEXPORT_DATABASE_PASSWORD", "hunter2")
+	t.Setenv("MAPEXPORT_DATABASE_HOSTS", "db1,db2")
+	t.Setenv("MAPEXPORT_USERS_0_NAME", "alice")
+	t.Setenv("MAPEXPORT_ENABLED", "true")
+	t.Setenv("MAPEXPORT_MAX_CONNS", "10")
+	t.Setenv("MAPEXPORT_RETRIES", "3")
+
+	if err := LoadConfig[MapExportConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"server": map[string]interface{}{
+			"host": "localhost",
+			"port": 8080,
+		},
+		"database": map[string]interface{}{
+			"password": "hunter2",
+			"hosts":    []string{"db1", "db2"},
+		},
+		"users": []interface{}{
+			map[string]interface{}{"name": "alice"},
+		},
+		"enabled":    true,
+		"maxConns":   10,
+		"retrycount": 3,
+	}
+
+	if got := AsMap(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("AsMap() = %#v\nexpected %#v", got, expected)
+	}
+
+	// 키는 Values 경로와 같다
+	for _, pv := range Values() {
+		if key := strings.FieldsFunc(pv.Path, func(r rune) bool { return r == '.' || r == '[' })[0]; AsMap()[key] == nil {
+			t.Errorf("expected AsMap to have the key %q of path %s", key, pv.Path)
+		}
+	}
+
+	masked := AsMaskedMap()
+	if password := masked["database"].(map[string]interface{})["password"]; password != "****" {
+		t.Errorf("expected masked password, got %v", password)
+	}
+}
+
+// TestValuesOrdering은 Values가 선언 순서(Describe와 같은 순서)로 경로/값 쌍을 반환하고 여러 번 호출해도 순서가 같은지 테스트합니다
+func TestValuesOrdering(t *testing.T) {
+	type Backend struct {
+		Host     string `toml:"host"`
+		Password string `toml:"password" secret:"true"`
+	}
+	type OrderConfig struct {
+		Zeta   string `toml:"zeta"`
+		Server struct {
+			Port int    `toml:"port"`
+			Host string `toml:"host"`
+		} `toml:"server"`
+		Users []struct {
+			Name string `toml:"name"`
+		} `toml:"users"`
+		Backends map[string]Backend `toml:"backends"`
+		Alpha    []string           `toml:"alpha"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "orderapp", `zeta = "z"
+alpha = ["a", "b"]
+
+[server]
+port = 8080
+host = "localhost"
+
+[[users]]
+name = "alice"
+
+[[users]]
+name = "bob"
+
+[backends.secondary]
+host = "db2"
+password = "p2"
+
+[backends.primary]
+host = "db1"
+password = "p1"
+`)
+	defer cleanup()
+	AppName = "orderapp"
+	if err := LoadConfig[OrderConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	expected := []PathValue{
+		{Path: "zeta", Value: "z"},
+		{Path: "server.port", Value: 8080},
+		{Path: "server.host", Value: "localhost"},
+		{Path: "users[0].name", Value: "alice"},
+		{Path: "users[1].name", Value: "bob"},
+		{Path: "backends.primary.host", Value: "db1"},
+		{Path: "backends.primary.password", Value: "****"},
+		{Path: "backends.secondary.host", Value: "db2"},
+		{Path: "backends.secondary.password", Value: "****"},
+		{Path: "alpha", Value: []string{"a", "b"}},
+	}
+	for run := 0; run < 20; run++ {
+		if got := MaskedValues(); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("run %d: unexpected values:\n got: %+v\nwant: %+v", run, got, expected)
+		}
+	}
+
+	if got := Values(); got[6].Value != "p1" {
+		t.Errorf("expected unmasked password from Values, got %v", got[6].Value)
+	}
+
+	// FprintConfig도 선언 순서대로 출력한다
+	var first bytes.Buffer
+	if err := FprintConfig(&first, true); err != nil {
+		t.Fatalf("FprintConfig failed: %v", err)
+	}
+	printed := first.String()
+	last := -1
+	for _, key := range []string{`"Zeta"`, `"Server"`, `"Port"`, `"Host"`, `"Users"`, `"Backends"`, `"Alpha"`} {
+		i := strings.Index(printed, key)
+		if i <= last {
+			t.Fatalf("expected %s after the previous field in printed config:\n%s", key, printed)
+		}
+		last = i
+	}
+	for run := 0; run < 20; run++ {
+		var out bytes.Buffer
+		if err := FprintConfig(&out, true); err != nil {
+			t.Fatalf("FprintConfig failed: %v", err)
+		}
+		if out.String() != printed {
+			t.Fatalf("run %d: printed config changed:\n%s\nwant:\n%s", run, out.String(), printed)
+		}
+	}
+}
+
+// TestMaskedValuesPointerSections는 MaskedValues가 포인터 섹션과 구조체 포인터 맵 값 안의 시크릿도 마스킹하는지 테스트합니다
+func TestMaskedValuesPointerSections(t *testing.T) {
+	type Section struct {
+		Cert string `toml:"cert"`
+		Key  string `toml:"key" secret:"true"`
+	}
+	type PointerConfig struct {
+		TLS      *Section            `toml:"tls"`
+		Proxy    *Section            `toml:"proxy"`
+		Backends map[string]*Section `toml:"backends"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "ptrvalues", `[tls]
+cert = "c"
+key = "SUPERSECRET"
+
+[backends.primary]
+cert = "c1"
+key = "BACKENDSECRET"
+`)
+	defer cleanup()
+	AppName = "ptrvalues"
+	if err := LoadConfig[PointerConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	expected := []PathValue{
+		{Path: "tls.cert", Value: "c"},
+		{Path: "tls.key", Value: "****"},
+		{Path: "proxy", Value: nil},
+		{Path: "backends.primary.cert", Value: "c1"},
+		{Path: "backends.primary.key", Value: "****"},
+	}
+	if got := MaskedValues(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected masked values:\n got: %+v\nwant: %+v", got, expected)
+	}
+}
+
+// TestFprintConfigFlat는 FprintConfigFlat이 점 경로별로 한 줄씩 출력하고 시크릿을 마스킹하는지 테스트합니다
+func TestFprintConfigFlat(t *testing.T) {
+	type FlatConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+		Database struct {
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		} `toml:"database" env:"DATABASE"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	resetGlobalConfig()
+	AppName = "FLATPRINT"
+	t.Setenv("FLATPRINT_SERVER_HOST", "localhost")
+	t.Setenv("FLATPRINT_SERVER_PORT", "8080")
+	t.Setenv("FLATPRINT_DATABASE_PASSWORD", "hunter2")
+	t.Setenv("FLATPRINT_USERS_0_NAME", "alice")
+
+	if err := LoadConfig[FlatConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := FprintConfigFlat(&buf, true); err != nil {
+		t.Fatalf("FprintConfigFlat failed: %v", err)
+	}
+	output := buf.String()
+	for _, line := range []string{
+		"server.host = localhost\n",
+		"server.port = 8080\n",
+		"database.password = ****\n",
+		"users[0].name = alice\n",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+	if strings.Contains(output, "hunter2") {
+		t.Errorf("expected the secret to be masked, got:\n%s", output)
+	}
+
+	if err := FprintConfigFlat(&buf, false); err == nil {
+		t.Error("expected unmasked output to be refused without AllowUnmaskedSecrets")
+	}
+}
+
+// TestFprintConfigFlatPointerSection는 FprintConfigFlat이 포인터 섹션 안의 시크릿을 마스킹하는지 테스트합니다
+func TestFprintConfigFlatPointerSection(t *testing.T) {
+	type Section struct {
+		Cert string `toml:"cert" env:"CERT"`
+		Key  string `toml:"key" env:"KEY" secret:"true"`
+	}
+	type FlatPointerConfig struct {
+		TLS *Section `toml:"tls" env:"TLS"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "flatptr", "[tls]\ncert = \"c\"\nkey = \"SUPERSECRET\"\n")
+	defer cleanup()
+	AppName = "flatptr"
+	if err := LoadConfig[FlatPointerConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := FprintConfigFlat(&buf, true); err != nil {
+		t.Fatalf("FprintConfigFlat failed: %v", err)
+	}
+	output := buf.String()
+	if strings.Contains(output, "SUPERSECRET") || !strings.Contains(output, "tls.key = ****\n") {
+		t.Errorf("expected the key in the pointer section to be masked, got:\n%s", output)
+	}
+	if !strings.Contains(output, "tls.cert = c\n") {
+		t.Errorf("expected tls.cert on its own line, got:\n%s", output)
+	}
+}
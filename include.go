@@ -0,0 +1,149 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml"
+)
+
+// includeKey is the top-level TOML key listing the files a config file
+// includes.
+const includeKey = "include"
+
+// fileSource reads config files and resolves the files they include, either
+// from the OS file system or from an fs.FS.
+type fileSource struct {
+	readFile func(name string) ([]byte, error)
+	// resolve returns the name of include, relative to the file from
+	resolve func(from, include string) string
+	// key identifies a file for circular include detection
+	key func(name string) (string, error)
+}
+
+// osFiles reads files from the OS file system.
+var osFiles = fileSource{
+	readFile: os.ReadFile,
+	resolve: func(from, include string) string {
+		if filepath.IsAbs(include) {
+			return include
+		}
+		return filepath.Join(filepath.Dir(from), include)
+	},
+	key: filepath.Abs,
+}
+
+// fsFiles reads files from fsys, using slash-separated names.
+func fsFiles(fsys fs.FS) fileSource {
+	return fileSource{
+		readFile: func(name string) ([]byte, error) {
+			return fs.ReadFile(fsys, name)
+		},
+		resolve: func(from, include string) string {
+			return path.Join(path.Dir(from), include)
+		},
+		key: func(name string) (string, error) {
+			return path.Clean(name), nil
+		},
+	}
+}
+
+// loadTOMLFile reads the TOML file name from src and resolves its include
+// directives. Each file in `include = ["base.toml", "secrets.toml"]` is read
+// relative to the including file's directory and deep-merged in order, so
+// later includes override earlier ones and the including file's own keys
+// override them all. Included files may include other files; a circular
+// include or a missing included file is an error.
+func loadTOMLFile(src fileSource, name string) (*toml.Tree, error) {
+	return loadTOMLFileWithIncludes(src, name, map[string]bool{})
+}
+
+// loadTOMLFileWithIncludes loads path and its includes. active holds the
+// files currently being loaded further up the include chain.
+func loadTOMLFileWithIncludes(src fileSource, path string, active map[string]bool) (*toml.Tree, error) {
+	key, err := src.key(path)
+	if err != nil {
+		return nil, err
+	}
+	if active[key] {
+		return nil, fmt.Errorf("circular include of %s", path)
+	}
+	active[key] = true
+	defer delete(active, key)
+
+	data, err := src.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := loadTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	includes, err := includedFiles(tree, path)
+	if err != nil {
+		return nil, err
+	}
+	if includes == nil {
+		return tree, nil
+	}
+	if err := tree.DeletePath([]string{includeKey}); err != nil {
+		return nil, err
+	}
+
+	merged, err := toml.TreeFromMap(map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	for _, include := range includes {
+		include = src.resolve(path, include)
+		included, err := loadTOMLFileWithIncludes(src, include, active)
+		if err != nil {
+			return nil, fmt.Errorf("failed to include %s from %s: %w", include, path, err)
+		}
+		mergeTrees(merged, included)
+	}
+	mergeTrees(merged, tree)
+	return merged, nil
+}
+
+// includedFiles returns the file names listed by the include key of tree,
+// or nil when the key is not set.
+func includedFiles(tree *toml.Tree, path string) ([]string, error) {
+	raw := tree.GetPath([]string{includeKey})
+	if raw == nil {
+		return nil, nil
+	}
+
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("include in %s must be an array of file names", path)
+	}
+	files := []string{}
+	for _, item := range list {
+		file, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("include in %s must be an array of file names", path)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// mergeTrees deep-merges src into dst. Tables present in both are merged key
+// by key; any other value in src replaces the one in dst.
+func mergeTrees(dst, src *toml.Tree) {
+	for _, key := range src.Keys() {
+		value := src.GetPath([]string{key})
+		if srcTable, ok := value.(*toml.Tree); ok {
+			if dstTable, ok := dst.GetPath([]string{key}).(*toml.Tree); ok {
+				mergeTrees(dstTable, srcTable)
+				continue
+			}
+		}
+		dst.SetPath([]string{key}, value)
+	}
+}
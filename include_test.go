@@ -0,0 +1,168 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestIncludeMergesFiles는 include로 지정한 파일들이 순서대로 병합되고 메인 파일 키가 우선하는지 테스트합니다
+func TestIncludeMergesFiles(t *testing.T) {
+	type IncludeConfig struct {
+		Server struct {
+			Host    string `toml:"host"`
+			Port    int    `toml:"port"`
+			Timeout int    `toml:"timeout"`
+		} `toml:"server"`
+		Database struct {
+			User     string `toml:"user"`
+			Password string `toml:"password"`
+		} `toml:"database"`
+		Name string `toml:"name"`
+	}
+
+	resetGlobalConfig()
+	filePath, cleanup := createTestTomlFile(t, "includeapp", `include = ["base.toml", "conf.d/secrets.toml"]
+name = "main"
+
+[server]
+port = 9000
+`)
+	defer cleanup()
+	dir := filepath.Dir(filePath)
+
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("base.toml", `name = "base"
+
+[server]
+host = "basehost"
+port = 8000
+timeout = 30
+
+[database]
+user = "baseuser"
+password = "basepass"
+`)
+	writeFile("conf.d/secrets.toml", `[database]
+password = "secretpass"
+`)
+
+	if err := InitConfigSafe[IncludeConfig]("includeapp"); err != nil {
+		t.Fatalf("InitConfigSafe failed: %v", err)
+	}
+	cfg := GetConfig[IncludeConfig]()
+
+	if cfg.Name != "main" {
+		t.Errorf("expected main file to win for name, got '%s'", cfg.Name)
+	}
+	if cfg.Server.Host != "basehost" || cfg.Server.Timeout != 30 {
+		t.Errorf("expected server values from base.toml, got host '%s' timeout %d", cfg.Server.Host, cfg.Server.Timeout)
+	}
+	if cfg.Server.Port != 9000 {
+		t.Errorf("expected main file port 9000, got %d", cfg.Server.Port)
+	}
+	if cfg.Database.User != "baseuser" {
+		t.Errorf("expected user 'baseuser', got '%s'", cfg.Database.User)
+	}
+	if cfg.Database.Password != "secretpass" {
+		t.Errorf("expected later include to override password, got '%s'", cfg.Database.Password)
+	}
+}
+
+// TestIncludeErrors는 순환 include와 존재하지 않는 include 파일이 에러를 반환하는지 테스트합니다
+func TestIncludeErrors(t *testing.T) {
+	type IncludeConfig struct {
+		Name string `toml:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectedErr string
+	}{
+		{
+			name: "circular",
+			files: map[string]string{
+				"main.toml": `include = ["a.toml"]`,
+				"a.toml":    `include = ["b.toml"]`,
+				"b.toml":    `include = ["main.toml"]`,
+			},
+			expectedErr: "circular include",
+		},
+		{
+			name: "missing",
+			files: map[string]string{
+				"main.toml": `include = ["missing.toml"]`,
+			},
+			expectedErr: "missing.toml",
+		},
+		{
+			name: "not an array",
+			files: map[string]string{
+				"main.toml": `include = "base.toml"`,
+			},
+			expectedErr: "must be an array of file names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalConfig()
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+
+			err := InitConfigWithExactFile[IncludeConfig]("includeapp", filepath.Join(dir, "main.toml"))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestIncludeShared는 같은 파일을 여러 경로로 include해도 순환으로 간주하지 않는지 테스트합니다
+func TestIncludeShared(t *testing.T) {
+	type IncludeConfig struct {
+		Name  string `toml:"name"`
+		Level string `toml:"level"`
+	}
+
+	resetGlobalConfig()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.toml":   `include = ["a.toml", "b.toml"]`,
+		"a.toml":      `include = ["common.toml"]` + "\nname = \"a\"\n",
+		"b.toml":      `include = ["common.toml"]` + "\nlevel = \"b\"\n",
+		"common.toml": "name = \"common\"\nlevel = \"common\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := InitConfigWithExactFile[IncludeConfig]("includeapp", filepath.Join(dir, "main.toml")); err != nil {
+		t.Fatalf("InitConfigWithExactFile failed: %v", err)
+	}
+	cfg := GetConfig[IncludeConfig]()
+	// b.toml re-includes common.toml after a.toml, so common's name wins again
+	if cfg.Name != "common" || cfg.Level != "b" {
+		t.Errorf("expected name 'common' and level 'b', got '%s' and '%s'", cfg.Name, cfg.Level)
+	}
+}
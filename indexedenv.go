@@ -0,0 +1,48 @@
+package ahatconfig
+
+import (
+	"reflect"
+)
+
+// isScalarSlice reports whether t is a slice of plain values that can also be
+// read from indexed environment variables: not []byte and not a struct slice.
+func isScalarSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !isByteSlice(t) && !isStructList(t)
+}
+
+// lookupIndexedEnv returns the values of the environment variables
+// {envKey}_0, {envKey}_1, ... up to the first index that is not set.
+func lookupIndexedEnv(lookup envLookup, envKey string) []string {
+	var values []string
+	for i := 0; ; i++ {
+		value, ok := lookup(indexEnvKey(envKey, i))
+		if !ok {
+			return values
+		}
+		values = append(values, value)
+	}
+}
+
+// loadIndexedSlice sets the scalar slice value from the indexed environment
+// variables of envKey, e.g. MYAPP_HOSTS_0 and MYAPP_HOSTS_1. It reports
+// whether any of them was set. Each value is one element, so it is not split
+// on the delimiter.
+func loadIndexedSlice(lookup envLookup, value reflect.Value, fieldInfo FieldInfo, envKey, path string) (bool, error) {
+	elems := lookupIndexedEnv(lookup, envKey)
+	if len(elems) == 0 {
+		return false, nil
+	}
+
+	elemInfo := fieldInfo
+	elemInfo.Type = fieldInfo.Type.Elem()
+	slice := reflect.MakeSlice(fieldInfo.Type, 0, len(elems))
+	for i, elem := range elems {
+		parsed, err := parseFieldValue(elem, elemInfo, indexPath(path, i))
+		if err != nil {
+			return true, err
+		}
+		slice = reflect.Append(slice, reflect.ValueOf(parsed).Convert(elemInfo.Type))
+	}
+	value.Set(slice)
+	return true, nil
+}
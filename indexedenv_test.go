@@ -0,0 +1,79 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestIndexedScalarSliceEnv는 인덱스가 붙은 환경변수(APP_HOSTS_0, APP_HOSTS_1)로 스칼라 슬라이스를 구성하는지 테스트합니다
+func TestIndexedScalarSliceEnv(t *testing.T) {
+	type IndexedConfig struct {
+		Hosts    []string `toml:"hosts" env:"HOSTS"`
+		Ports    []int    `toml:"ports" env:"PORTS"`
+		Database struct {
+			Replicas []string `toml:"replicas" env:"REPLICAS"`
+		} `toml:"database" env:"DATABASE"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	t.Run("indexed vars", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "indexedapp"
+		t.Setenv("INDEXEDAPP_HOSTS_0", "a.example.com")
+		t.Setenv("INDEXEDAPP_HOSTS_1", "b,c.example.com")
+		t.Setenv("INDEXEDAPP_HOSTS_3", "skipped after the gap")
+		t.Setenv("INDEXEDAPP_PORTS_0", "80")
+		t.Setenv("INDEXEDAPP_PORTS_1", "443")
+		t.Setenv("INDEXEDAPP_DATABASE_REPLICAS_0", "replica1")
+		t.Setenv("INDEXEDAPP_USERS_0_NAME", "alice")
+
+		if err := LoadConfig[IndexedConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[IndexedConfig]()
+
+		if !reflect.DeepEqual(cfg.Hosts, []string{"a.example.com", "b,c.example.com"}) {
+			t.Errorf("expected hosts from indexed vars, got %v", cfg.Hosts)
+		}
+		if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+			t.Errorf("expected ports [80 443], got %v", cfg.Ports)
+		}
+		if !reflect.DeepEqual(cfg.Database.Replicas, []string{"replica1"}) {
+			t.Errorf("expected nested replicas from indexed vars, got %v", cfg.Database.Replicas)
+		}
+		if len(cfg.Users) != 1 || cfg.Users[0].Name != "alice" {
+			t.Errorf("expected struct slice to be unaffected, got %+v", cfg.Users)
+		}
+	})
+
+	t.Run("list var wins", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "indexedapp"
+		t.Setenv("INDEXEDAPP_HOSTS", "x,y")
+		t.Setenv("INDEXEDAPP_HOSTS_0", "a")
+
+		if err := LoadConfig[IndexedConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg := GetConfig[IndexedConfig](); !reflect.DeepEqual(cfg.Hosts, []string{"x", "y"}) {
+			t.Errorf("expected the delimited var to win, got %v", cfg.Hosts)
+		}
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+		var cfg IndexedConfig
+		lookup := func(key string) (string, bool) {
+			value, ok := map[string]string{"INDEXEDAPP_PORTS_0": "80", "INDEXEDAPP_PORTS_1": "http"}[key]
+			return value, ok
+		}
+		err := loadStructEnv(lookup, reflect.ValueOf(&cfg).Elem(), "indexedapp", "")
+		if err == nil || !strings.Contains(err.Error(), "ports[1]") {
+			t.Errorf("expected a parse error naming ports[1], got %v", err)
+		}
+	})
+}
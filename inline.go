@@ -0,0 +1,92 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// loadInlineStruct sets the fields of the struct v from a comma-separated
+// key=value list, such as APP_CACHE=size=100,ttl=60. Keys name the fields by
+// env tag or field name, case-insensitively. path is the dotted path of v.
+// Values may be double-quoted to contain commas (tags="a,b"); errors name
+// the key but never the value.
+func loadInlineStruct(v reflect.Value, envValue, path string) error {
+	pairs, err := splitPairs(envValue)
+	if err != nil {
+		return fmt.Errorf("failed to parse env value for field %s: %w", path, err)
+	}
+
+	typeInfo := getCachedTypeInfo(v.Type())
+	for _, pair := range pairs {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, raw, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("failed to parse env value for field %s: expected key=value pairs", path)
+		}
+
+		i := inlineFieldIndex(typeInfo, key)
+		if i < 0 {
+			return fmt.Errorf("failed to parse env value for field %s: unknown key '%s'", path, key)
+		}
+		fieldInfo := typeInfo.Fields[i]
+		fieldPath := joinPath(path, fieldInfo.Key)
+		if isNestedStruct(fieldInfo.Type) {
+			return fmt.Errorf("failed to parse env value for field %s: nested section '%s' cannot be set inline", path, key)
+		}
+
+		raw = strings.TrimSpace(raw)
+		if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+			raw = raw[1 : len(raw)-1]
+		}
+
+		parsed, err := parseFieldValue(raw, fieldInfo, fieldPath)
+		if err != nil {
+			return err
+		}
+		v.Field(i).Set(reflect.ValueOf(parsed))
+	}
+	return nil
+}
+
+// inlineFieldIndex returns the index of the field named key by its env tag
+// or, without one, its field name, or -1 when no field matches.
+func inlineFieldIndex(typeInfo *TypeInfo, key string) int {
+	for i, fieldInfo := range typeInfo.Fields {
+		name := fieldInfo.EnvTag
+		if name == "" {
+			name = fieldInfo.Name
+		}
+		if strings.EqualFold(name, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitPairs splits a key=value list on the commas outside double quotes.
+func splitPairs(s string) ([]string, error) {
+	var pairs []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ',':
+			if !inQuotes {
+				pairs = append(pairs, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted value")
+	}
+	return append(pairs, s[start:]), nil
+}
@@ -0,0 +1,93 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestInlineStruct는 inline 태그가 있는 구조체를 key=value 목록 하나로 채우는지 테스트합니다
+func TestInlineStruct(t *testing.T) {
+	type InlineConfig struct {
+		Cache struct {
+			Size int           `toml:"size" env:"SIZE" default:"10"`
+			TTL  time.Duration `toml:"ttl" env:"TTL"`
+			Tags []string      `toml:"tags" env:"TAGS"`
+		} `toml:"cache" env:"CACHE" inline:"true"`
+	}
+
+	t.Run("key=value list", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "inlineapp"
+		t.Setenv("INLINEAPP_CACHE", `size=100, TTL=1m, tags="a,b"`)
+
+		if err := LoadConfig[InlineConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[InlineConfig]()
+		if cfg.Cache.Size != 100 || cfg.Cache.TTL != time.Minute {
+			t.Errorf("expected size 100 and ttl 1m, got %d and %v", cfg.Cache.Size, cfg.Cache.TTL)
+		}
+		if len(cfg.Cache.Tags) != 2 || cfg.Cache.Tags[1] != "b" {
+			t.Errorf("expected tags [a b], got %v", cfg.Cache.Tags)
+		}
+	})
+
+	t.Run("prefixed vars override and defaults fill", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "inlineapp"
+		t.Setenv("INLINEAPP_CACHE", "ttl=30s")
+		t.Setenv("INLINEAPP_CACHE_TTL", "45s")
+
+		if err := LoadConfig[InlineConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[InlineConfig]()
+		if cfg.Cache.TTL != 45*time.Second {
+			t.Errorf("expected INLINEAPP_CACHE_TTL to win, got %v", cfg.Cache.TTL)
+		}
+		if cfg.Cache.Size != 10 {
+			t.Errorf("expected default size 10, got %d", cfg.Cache.Size)
+		}
+	})
+
+	t.Run("time.Time fields are values", func(t *testing.T) {
+		type inlineStruct struct {
+			Expires time.Time `env:"EXPIRES"`
+			Nested  struct {
+				Size int `env:"SIZE"`
+			} `env:"NESTED"`
+		}
+		var v inlineStruct
+		if err := loadInlineStruct(reflect.ValueOf(&v).Elem(), "expires=2026-01-02T03:04:05Z", "cache"); err != nil {
+			t.Fatalf("loadInlineStruct failed: %v", err)
+		}
+		if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !v.Expires.Equal(want) {
+			t.Errorf("expected expires %v, got %v", want, v.Expires)
+		}
+		err := loadInlineStruct(reflect.ValueOf(&v).Elem(), "nested=1", "cache")
+		if err == nil || !strings.Contains(err.Error(), "nested section 'nested' cannot be set inline") {
+			t.Errorf("expected nested sections to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("invalid lists", func(t *testing.T) {
+		type inlineStruct struct {
+			Size int `env:"SIZE"`
+		}
+		tests := map[string]string{
+			"size":       "expected key=value pairs",
+			"count=1":    "unknown key 'count'",
+			"size=large": "failed to parse env value for field cache.size",
+			`size="1`:    "unterminated quoted value",
+		}
+		for input, expected := range tests {
+			var v inlineStruct
+			err := loadInlineStruct(reflect.ValueOf(&v).Elem(), input, "cache")
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("loadInlineStruct(%q): expected error containing '%s', got %v", input, expected, err)
+			}
+		}
+	})
+}
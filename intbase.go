@@ -0,0 +1,100 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/pelletier/go-toml"
+)
+
+// baseTag returns the base of the base tag of field, 0 when the tag is not
+// set and -1 when it is not a base from 2 to 36.
+func baseTag(field reflect.StructField) int {
+	tag := field.Tag.Get("base")
+	if tag == "" {
+		return 0
+	}
+	base, err := strconv.Atoi(tag)
+	if err != nil || base < 2 || base > 36 {
+		return -1
+	}
+	return base
+}
+
+// parseBaseField parses the value of an integer field with a base tag, e.g.
+// "644" with base:"8" is 420. A leading sign and underscore digit separators
+// are accepted, base prefixes such as 0o are not. path is the dotted path of
+// the field.
+func parseBaseField(value string, fieldInfo FieldInfo, path string) (interface{}, error) {
+	switch {
+	case fieldInfo.Base < 0:
+		return nil, fmt.Errorf("invalid base tag on field %s, expected a base from 2 to 36", path)
+	case !isIntKind(fieldInfo.Type.Kind()):
+		return nil, fmt.Errorf("base tag on field %s requires an integer field", path)
+	case value == "":
+		return getZeroValue(fieldInfo.Type), nil
+	}
+
+	s, err := normalizeNumber(value)
+	if err == nil {
+		var n int64
+		if n, err = strconv.ParseInt(s, fieldInfo.Base, fieldInfo.Type.Bits()); err == nil {
+			return reflect.ValueOf(n).Convert(fieldInfo.Type).Interface(), nil
+		}
+	}
+	if isSecretField(fieldInfo, path) {
+		return nil, fmt.Errorf("failed to parse value for field %s: invalid base %d integer", path, fieldInfo.Base)
+	}
+	return nil, fmt.Errorf("failed to parse value for field %s: invalid base %d integer '%s'", path, fieldInfo.Base, value)
+}
+
+// isIntKind reports whether k is a signed integer kind.
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8:
+		return true
+	}
+	return false
+}
+
+// convertTOMLBaseInts replaces string values assigned to fields with a base
+// tag with the integers they denote, which go-toml can decode. Integer values
+// are left as they are. It descends into tables and arrays of tables; path is
+// the dotted path of tree.
+func convertTOMLBaseInts(tree *toml.Tree, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		fieldPath := joinPath(path, fieldInfo.Key)
+		switch value := tree.GetPath([]string{fieldInfo.Key}).(type) {
+		case string:
+			if fieldInfo.Base == 0 {
+				continue
+			}
+			parsed, err := parseBaseField(value, fieldInfo, fieldPath)
+			if err != nil {
+				return err
+			}
+			tree.SetPath([]string{fieldInfo.Key}, reflect.ValueOf(parsed).Int())
+		case *toml.Tree:
+			if err := convertTOMLBaseInts(value, fieldInfo.Type, fieldPath); err != nil {
+				return err
+			}
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for i, elem := range value {
+					if err := convertTOMLBaseInts(elem, elemType, indexPath(fieldPath, i)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
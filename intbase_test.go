@@ -0,0 +1,67 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestBaseTag는 base 태그가 붙은 정수 필드를 환경변수와 TOML 문자열에서 해당 진법으로 파싱하는지 테스트합니다
+func TestBaseTag(t *testing.T) {
+	type BaseConfig struct {
+		FileMode int   `toml:"file_mode" env:"FILE_MODE" base:"8"`
+		DirMode  int   `toml:"dir_mode" env:"DIR_MODE" base:"8" default:"755"`
+		Mask     int64 `toml:"mask" env:"MASK" base:"2"`
+		Color    int32 `toml:"color" env:"COLOR" base:"16"`
+	}
+
+	t.Run("env and defaults", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "baseapp"
+		t.Setenv("BASEAPP_FILE_MODE", "644")
+		t.Setenv("BASEAPP_MASK", "1010_1010")
+
+		if err := LoadConfig[BaseConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[BaseConfig]()
+		if cfg.FileMode != 420 {
+			t.Errorf("expected 644 in base 8 to be 420, got %d", cfg.FileMode)
+		}
+		if cfg.DirMode != 493 {
+			t.Errorf("expected the default 755 in base 8 to be 493, got %d", cfg.DirMode)
+		}
+		if cfg.Mask != 170 {
+			t.Errorf("expected 1010_1010 in base 2 to be 170, got %d", cfg.Mask)
+		}
+	})
+
+	t.Run("TOML strings and integers", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "basetoml", "file_mode = \"600\"\ncolor = \"ff00ff\"\nmask = 12\n")
+		defer cleanup()
+		AppName = "basetoml"
+
+		if err := LoadConfig[BaseConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[BaseConfig]()
+		if cfg.FileMode != 384 || cfg.Color != 0xff00ff || cfg.Mask != 12 {
+			t.Errorf("expected file_mode 384, color 16711935 and mask 12, got %+v", cfg)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		if _, err := parseBaseField("9", FieldInfo{Type: reflect.TypeOf(0), Base: 8}, "file_mode"); err == nil || !strings.Contains(err.Error(), "invalid base 8 integer '9'") {
+			t.Errorf("expected an invalid digit error, got %v", err)
+		}
+		if _, err := parseBaseField("1", FieldInfo{Type: reflect.TypeOf(0), Base: -1}, "file_mode"); err == nil || !strings.Contains(err.Error(), "invalid base tag") {
+			t.Errorf("expected an invalid base tag error, got %v", err)
+		}
+		if _, err := parseBaseField("1", FieldInfo{Type: reflect.TypeOf(""), Base: 8}, "name"); err == nil || !strings.Contains(err.Error(), "requires an integer field") {
+			t.Errorf("expected an integer field error, got %v", err)
+		}
+	})
+}
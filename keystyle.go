@@ -0,0 +1,159 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/pelletier/go-toml"
+)
+
+// TOMLKeyStyle controls how TOML keys are matched to struct fields that have
+// no toml tag.
+type TOMLKeyStyle int
+
+const (
+	// DefaultKeyStyle matches keys to field names case-insensitively, as
+	// go-toml does (the default).
+	DefaultKeyStyle TOMLKeyStyle = iota
+	// KebabCase also matches kebab-case keys such as max-connections to
+	// CamelCase fields such as MaxConnections.
+	KebabCase
+	// SnakeCase also matches snake_case keys such as max_connections to
+	// CamelCase fields such as MaxConnections.
+	SnakeCase
+)
+
+var tomlKeyStyle = DefaultKeyStyle
+
+// SetTOMLKeyStyle sets how config file keys are matched to struct fields
+// without a toml tag in subsequent loads. Fields with a toml tag always use
+// the key from the tag.
+//
+// Example:
+//
+//	ahatconfig.SetTOMLKeyStyle(ahatconfig.KebabCase)
+//	// max-connections = 10 now sets MaxConnections
+func SetTOMLKeyStyle(style TOMLKeyStyle) {
+	tomlKeyStyle = style
+}
+
+// renameTOMLKeys renames the keys of tree that go-toml would decode into the
+// fields of type t to the fields' keys (FieldInfo.Key), so that every walker
+// over the tree finds a value under the key of its field, exactly where
+// go-toml reads it from. go-toml matches a key written as the toml tag or
+// field name, lowercased, uppercased or with a lowercase first letter (see
+// tomlKeyCandidates); keys in the key style are matched after those. It
+// descends into tables, arrays of tables and maps of tables.
+func renameTOMLKeys(tree *toml.Tree, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i, fieldInfo := range getCachedTypeInfo(t).Fields {
+		candidates := tomlKeyCandidates(t.Field(i))
+		if styled := styledKey(t.Field(i)); styled != "" && tomlKeyStyle != DefaultKeyStyle {
+			candidates = append(candidates, styled)
+		}
+
+		// go-toml decodes the first candidate present and ignores the others
+		found := ""
+		for _, key := range candidates {
+			if tree.HasPath([]string{key}) {
+				found = key
+				break
+			}
+		}
+		if found == "" {
+			continue
+		}
+		if found != fieldInfo.Key {
+			value := tree.GetPath([]string{found})
+			for _, key := range candidates {
+				if tree.HasPath([]string{key}) {
+					tree.DeletePath([]string{key})
+				}
+			}
+			tree.SetPath([]string{fieldInfo.Key}, value)
+		}
+
+		switch value := tree.GetPath([]string{fieldInfo.Key}).(type) {
+		case *toml.Tree:
+			if fieldInfo.Type.Kind() == reflect.Map && isStructMapElem(fieldInfo.Type.Elem()) {
+				for _, key := range value.Keys() {
+					if elem, ok := value.GetPath([]string{key}).(*toml.Tree); ok {
+						renameTOMLKeys(elem, fieldInfo.Type.Elem())
+					}
+				}
+				continue
+			}
+			renameTOMLKeys(value, fieldInfo.Type)
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for _, elem := range value {
+					renameTOMLKeys(elem, elemType)
+				}
+			}
+		}
+	}
+}
+
+// tomlKeyCandidates returns the keys go-toml decodes into field, in the order
+// it tries them: the toml tag name or the field name as written, lowercased,
+// uppercased and with a lowercase first letter. It returns nil for fields
+// go-toml skips.
+func tomlKeyCandidates(field reflect.StructField) []string {
+	if field.PkgPath != "" {
+		return nil
+	}
+	tag := strings.Split(field.Tag.Get("toml"), ",")
+	base := field.Name
+	if tag[0] == "-" && len(tag) == 1 {
+		return nil
+	} else if tag[0] != "" {
+		base = strings.TrimSpace(tag[0])
+	}
+	if base == "" {
+		return nil
+	}
+	return []string{base, strings.ToLower(base), strings.ToTitle(base), strings.ToLower(base[:1]) + base[1:]}
+}
+
+// styledKey returns the key of an untagged field in the active key style, or
+// "" when the field has a toml tag or the key equals its default key.
+func styledKey(field reflect.StructField) string {
+	if name := strings.Split(field.Tag.Get("toml"), ",")[0]; name != "" {
+		return ""
+	}
+
+	separator := "-"
+	if tomlKeyStyle == SnakeCase {
+		separator = "_"
+	}
+	key := strings.Join(splitWords(field.Name), separator)
+	if key == strings.ToLower(field.Name) {
+		return ""
+	}
+	return key
+}
+
+// splitWords splits a CamelCase name into lowercase words, keeping acronyms
+// together: "HTTPPort" becomes "http" and "port".
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		upper := unicode.IsUpper(runes[i])
+		// A word starts at an upper case letter after a lower case letter or
+		// digit, or at the last upper case letter of an acronym
+		if upper && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			words = append(words, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	return append(words, strings.ToLower(string(runes[start:])))
+}
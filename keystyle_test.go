@@ -0,0 +1,76 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTOMLKeyStyleKebabCase는 KebabCase 설정 시 kebab-case TOML 키가 태그 없는 CamelCase 필드에 매핑되는지 테스트합니다
+func TestTOMLKeyStyleKebabCase(t *testing.T) {
+	type Pool struct {
+		MaxConnections int
+		IdleTimeout    string
+	}
+	type KebabConfig struct {
+		ServiceName string
+		HTTPPort    int
+		Pool        Pool
+		Replicas    []Pool
+		LogLevel    string `toml:"log-level-name"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "kebabapp", `service-name = "api"
+http-port = 8080
+log-level-name = "debug"
+
+[pool]
+max-connections = 10
+idle-timeout = "30s"
+
+[[replicas]]
+max-connections = 2
+
+[[replicas]]
+MaxConnections = 3
+`)
+	defer cleanup()
+	AppName = "kebabapp"
+	SetTOMLKeyStyle(KebabCase)
+
+	if err := LoadConfig[KebabConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[KebabConfig]()
+
+	if cfg.ServiceName != "api" || cfg.HTTPPort != 8080 {
+		t.Errorf("expected service-name and http-port to be mapped, got '%s' and %d", cfg.ServiceName, cfg.HTTPPort)
+	}
+	if cfg.Pool.MaxConnections != 10 || cfg.Pool.IdleTimeout != "30s" {
+		t.Errorf("expected nested kebab-case keys to be mapped, got %+v", cfg.Pool)
+	}
+	if len(cfg.Replicas) != 2 || cfg.Replicas[0].MaxConnections != 2 || cfg.Replicas[1].MaxConnections != 3 {
+		t.Errorf("expected array of tables to be mapped, got %+v", cfg.Replicas)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("expected the toml tag to be used as is, got '%s'", cfg.LogLevel)
+	}
+}
+
+// TestSplitWords는 CamelCase 이름이 약어를 유지하며 단어로 분리되는지 테스트합니다
+func TestSplitWords(t *testing.T) {
+	tests := map[string][]string{
+		"MaxConnections": {"max", "connections"},
+		"HTTPPort":       {"http", "port"},
+		"DBHost":         {"db", "host"},
+		"ID":             {"id"},
+		"Port2Target":    {"port2", "target"},
+		"Name":           {"name"},
+	}
+	for name, expected := range tests {
+		if got := splitWords(name); !reflect.DeepEqual(got, expected) {
+			t.Errorf("splitWords(%q): expected %v, got %v", name, expected, got)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ahatconfig
+
+import "sync"
+
+// backgroundTask is a goroutine started by the package, such as a watcher or
+// refresher, that must stop when Shutdown is called.
+type backgroundTask struct {
+	done     chan struct{}
+	exited   chan struct{}
+	stopOnce sync.Once
+}
+
+var (
+	backgroundMu    sync.Mutex
+	backgroundTasks = map[*backgroundTask]struct{}{}
+)
+
+// startBackground runs fn in a new goroutine. fn must return once done is
+// closed. The returned stop function closes done and waits for fn to return;
+// it is safe to call multiple times. Shutdown stops every running task.
+func startBackground(fn func(done <-chan struct{})) (stop func()) {
+	task := &backgroundTask{
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	backgroundMu.Lock()
+	backgroundTasks[task] = struct{}{}
+	backgroundMu.Unlock()
+
+	go func() {
+		defer close(task.exited)
+		defer task.remove()
+		fn(task.done)
+	}()
+
+	return task.stop
+}
+
+func (t *backgroundTask) stop() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+	})
+	<-t.exited
+}
+
+// remove takes a task that has finished off the running tasks.
+func (t *backgroundTask) remove() {
+	backgroundMu.Lock()
+	delete(backgroundTasks, t)
+	backgroundMu.Unlock()
+}
+
+// Shutdown stops all background goroutines started by the package (watchers
+// and refreshers) and waits for them to exit. It is safe to call multiple
+// times and from multiple goroutines.
+//
+// Example:
+//
+//	defer ahatconfig.Shutdown()
+func Shutdown() {
+	backgroundMu.Lock()
+	tasks := make([]*backgroundTask, 0, len(backgroundTasks))
+	for task := range backgroundTasks {
+		tasks = append(tasks, task)
+	}
+	backgroundMu.Unlock()
+
+	for _, task := range tasks {
+		task.stop()
+	}
+}
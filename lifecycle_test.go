@@ -0,0 +1,65 @@
+package ahatconfig
+
+import (
+	"testing"
+	"time"
+)
+
+// TestShutdownStopsBackgroundTasks는 Shutdown이 백그라운드 고루틴을 종료시키는지 테스트합니다
+func TestShutdownStopsBackgroundTasks(t *testing.T) {
+	exited := make(chan struct{})
+	ticks := make(chan struct{}, 1)
+
+	startBackground(func(done <-chan struct{}) {
+		defer close(exited)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				select {
+				case ticks <- struct{}{}:
+				default:
+				}
+			}
+		}
+	})
+
+	// 워처가 실제로 동작하는지 확인
+	select {
+	case <-ticks:
+	case <-time.After(time.Second):
+		t.Fatal("background task did not start")
+	}
+
+	Shutdown()
+
+	select {
+	case <-exited:
+	default:
+		t.Fatal("expected background task to have exited after Shutdown")
+	}
+
+	// 여러 번 호출해도 안전해야 한다
+	Shutdown()
+
+	backgroundMu.Lock()
+	remaining := len(backgroundTasks)
+	backgroundMu.Unlock()
+	if remaining != 0 {
+		t.Errorf("expected no registered background tasks, got %d", remaining)
+	}
+}
+
+// TestBackgroundStopIsIdempotent는 개별 stop 함수를 여러 번 호출해도 안전한지 테스트합니다
+func TestBackgroundStopIsIdempotent(t *testing.T) {
+	stop := startBackground(func(done <-chan struct{}) {
+		<-done
+	})
+
+	stop()
+	stop()
+	Shutdown()
+}
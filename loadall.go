@@ -0,0 +1,197 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// LoadAll loads several independent configurations from the same sources in
+// one pass, reading and parsing the config file only once. Each target is a
+// pointer to a struct whose top-level tags select its sections of the file
+// and its environment variables, so plugins can each declare their own
+// config type. Every target is built and validated like LoadConfig would;
+// the targets are only written when all of them load, and none of them is
+// stored as the shared instance.
+//
+// Example:
+//
+//	type CacheConfig struct {
+//	    Cache struct {
+//	        Size int `toml:"size" env:"SIZE"`
+//	    } `toml:"cache" env:"CACHE"`
+//	}
+//	type AuthConfig struct {
+//	    Auth struct {
+//	        Issuer string `toml:"issuer" env:"ISSUER" required:"true"`
+//	    } `toml:"auth" env:"AUTH"`
+//	}
+//
+//	var cache CacheConfig
+//	var auth AuthConfig
+//	err := ahatconfig.LoadAll("myapp", &cache, &auth)
+func LoadAll(appname string, targets ...interface{}) error {
+	AppName = appname
+
+	for i, target := range targets {
+		v := reflect.ValueOf(target)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return fmt.Errorf("LoadAll target %d must be a non-nil pointer to a struct, got %T", i, target)
+		}
+	}
+
+	// Every target reads the environment as it was when the load started
+	env := takeEnvSnapshot(AppName)
+	doc, err := readConfigDocument(env.lookup)
+	if err != nil {
+		log.Printf("Config load failed: %s", err)
+		return err
+	}
+
+	built := make([]reflect.Value, len(targets))
+	for i, target := range targets {
+		cfg := reflect.New(reflect.TypeOf(target).Elem()).Interface()
+		if _, err := buildInto(cfg, AppName, "", env, func() error { return doc.decode(cfg) }); err != nil {
+			return fmt.Errorf("failed to load %T: %w", target, err)
+		}
+		built[i] = reflect.ValueOf(cfg)
+	}
+
+	// Targets are only written once every one of them loaded
+	for i, target := range targets {
+		reflect.ValueOf(target).Elem().Set(built[i].Elem())
+	}
+	return nil
+}
+
+// LoadInto loads the configuration into dst, a pointer to a struct, like
+// LoadAll with a single target. It serves callers that cannot use the
+// generic loaders, such as reflection-based frameworks or code that builds
+// the config type at runtime with reflect.StructOf. dst is written only when
+// the load succeeds and is not stored as the shared instance.
+//
+// Example:
+//
+//	cfg := factory.NewConfig() // returns interface{} holding a *SomeConfig
+//	if err := ahatconfig.LoadInto("myapp", cfg); err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadInto(appname string, dst interface{}) error {
+	if v := reflect.ValueOf(dst); v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("LoadInto requires a non-nil pointer to a struct, got %T", dst)
+	}
+	return LoadAll(appname, dst)
+}
+
+// configDocument is a config file read once and decoded into several
+// configs.
+type configDocument struct {
+	path   string
+	format string
+	tree   *toml.Tree // parsed TOML document, copied for each config
+	data   []byte     // JSON document, converted for each config type
+	strict bool       // decode errors fail the load, as with {APPNAME}_CONFIG_FILE
+}
+
+// readConfigDocument reads the config file LoadConfig would read: the file
+// named by {APPNAME}_CONFIG_FILE, else {AppName}.toml or {AppName}.json by
+// {APPNAME}_CONFIG_TYPE. It returns nil when there is no file to read. As in
+// LoadConfig, only a file named by {APPNAME}_CONFIG_FILE must be readable.
+// The variables are looked up with lookup.
+func readConfigDocument(lookup envLookup) (*configDocument, error) {
+	file, _ := lookup(configFileEnvKey())
+	if file = strings.TrimSpace(file); file != "" {
+		doc, err := readDocument(file, fileFormat(file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", file, err)
+		}
+		doc.strict = true
+		return doc, nil
+	}
+
+	format := configType(lookup)
+	switch format {
+	case "env":
+		return nil, nil
+	case "toml", "json":
+	default:
+		return nil, fmt.Errorf("unknown config type '%s', expected toml, json or env", format)
+	}
+
+	filePath, err := configFilePath(AppName + "." + format)
+	if err == nil {
+		if _, statErr := os.Stat(filePath); os.IsNotExist(statErr) {
+			return nil, nil
+		}
+		var doc *configDocument
+		if doc, err = readDocument(filePath, format); err == nil {
+			return doc, nil
+		}
+	}
+	log.Printf("%s config load failed (this is OK if file doesn't exist): %v", strings.ToUpper(format), err)
+	return nil, nil
+}
+
+// readDocument reads the config file at path in the given format.
+func readDocument(path, format string) (*configDocument, error) {
+	doc := &configDocument{path: path, format: format}
+	var err error
+	if format == "toml" {
+		doc.tree, err = loadTOMLFile(osFiles, path)
+	} else {
+		doc.data, err = osFiles.readFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// decode decodes the document into cfg. A nil document leaves cfg empty.
+func (d *configDocument) decode(cfg interface{}) error {
+	if d == nil {
+		return nil
+	}
+
+	var err error
+	if d.tree != nil {
+		err = decodeTree(cloneTree(d.tree), cfg)
+	} else {
+		err = decodeDocument(d.data, d.format, cfg)
+	}
+	if err == nil {
+		return nil
+	}
+	if d.strict {
+		return fmt.Errorf("failed to decode config file %s: %w", d.path, err)
+	}
+	log.Printf("%s config load failed (this is OK if file doesn't exist): %v", strings.ToUpper(d.format), err)
+	return nil
+}
+
+// cloneTree returns a deep copy of tree, which decodeTree can rewrite
+// without changing tree.
+func cloneTree(tree *toml.Tree) *toml.Tree {
+	clone, _ := toml.TreeFromMap(map[string]interface{}{})
+	for _, key := range tree.Keys() {
+		switch value := tree.GetPath([]string{key}).(type) {
+		case *toml.Tree:
+			clone.SetPath([]string{key}, cloneTree(value))
+		case []*toml.Tree:
+			tables := make([]*toml.Tree, len(value))
+			for i, table := range value {
+				tables[i] = cloneTree(table)
+			}
+			clone.SetPath([]string{key}, tables)
+		case []interface{}:
+			clone.SetPath([]string{key}, append([]interface{}(nil), value...))
+		default:
+			clone.SetPath([]string{key}, value)
+		}
+	}
+	return clone
+}
@@ -0,0 +1,131 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadAll는 LoadAll이 한 번의 호출로 서로 다른 두 구조체를 같은 파일에서 로드하는지 테스트합니다
+func TestLoadAll(t *testing.T) {
+	type CachePluginConfig struct {
+		Cache struct {
+			Size int    `toml:"size" env:"SIZE"`
+			Mode string `toml:"mode" env:"MODE" default:"lru"`
+		} `toml:"cache" env:"CACHE"`
+	}
+	type AuthPluginConfig struct {
+		Auth struct {
+			Issuer   string `toml:"issuer" env:"ISSUER" required:"true"`
+			Audience string `toml:"audience" env:"AUDIENCE"`
+		} `toml:"auth" env:"AUTH"`
+		Cache struct {
+			Size int `toml:"size" env:"SIZE"`
+		} `toml:"cache" env:"CACHE"`
+	}
+
+	resetGlobalConfig()
+	appName := "loadallapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+[cache]
+size = 128
+
+[auth]
+issuer = "https://issuer.example.com"
+audience = "api"
+
+[billing]
+plan = "pro"
+`)
+	defer cleanup()
+
+	t.Setenv("LOADALLAPP_AUTH_AUDIENCE", "web")
+
+	var cache CachePluginConfig
+	var auth AuthPluginConfig
+	if err := LoadAll(appName, &cache, &auth); err != nil {
+		t.Fatalf("LoadAll failed: %v", err)
+	}
+
+	if cache.Cache.Size != 128 || cache.Cache.Mode != "lru" {
+		t.Errorf("unexpected cache config: %+v", cache.Cache)
+	}
+	if auth.Cache.Size != 128 {
+		t.Errorf("expected both targets to read the shared cache section, got %+v", auth.Cache)
+	}
+	if auth.Auth.Issuer != "https://issuer.example.com" || auth.Auth.Audience != "web" {
+		t.Errorf("unexpected auth config: %+v", auth.Auth)
+	}
+	if _, err := GetConfigSafe[CachePluginConfig](); err == nil {
+		t.Error("expected LoadAll not to store a shared instance")
+	}
+
+	t.Run("failed target", func(t *testing.T) {
+		t.Setenv("LOADALLAPP_CONFIG_TYPE", "env")
+
+		var cache CachePluginConfig
+		var auth AuthPluginConfig
+		err := LoadAll(appName, &cache, &auth)
+		if err == nil || !strings.Contains(err.Error(), "ISSUER") {
+			t.Fatalf("expected the missing issuer to fail the load, got %v", err)
+		}
+		if cache.Cache.Mode != "" {
+			t.Errorf("expected no target to be written when one fails, got %+v", cache.Cache)
+		}
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		var cache CachePluginConfig
+		if err := LoadAll(appName, cache); err == nil {
+			t.Error("expected an error for a target that is not a pointer")
+		}
+	})
+}
+
+// TestLoadInto는 LoadInto가 interface{}로 전달된 구조체 포인터와 런타임에 만든 구조체에 설정을 로드하는지 테스트합니다
+func TestLoadInto(t *testing.T) {
+	type SomeStruct struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT" default:"8080"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	appName := "loadintoapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+[server]
+host = "file.example.com"
+`)
+	defer cleanup()
+	t.Setenv("LOADINTOAPP_SERVER_PORT", "9090")
+
+	var dst interface{} = &SomeStruct{}
+	if err := LoadInto(appName, dst); err != nil {
+		t.Fatalf("LoadInto failed: %v", err)
+	}
+	if cfg := dst.(*SomeStruct); cfg.Server.Host != "file.example.com" || cfg.Server.Port != 9090 {
+		t.Errorf("unexpected config: %+v", cfg.Server)
+	}
+
+	// A type built at runtime
+	dynamicType := reflect.StructOf([]reflect.StructField{{
+		Name: "Port",
+		Type: reflect.TypeOf(0),
+		Tag:  `toml:"port" env:"PORT"`,
+	}})
+	dynamic := reflect.New(dynamicType)
+	t.Setenv("LOADINTOAPP_PORT", "7070")
+	if err := LoadInto(appName, dynamic.Interface()); err != nil {
+		t.Fatalf("LoadInto failed for a runtime type: %v", err)
+	}
+	if port := dynamic.Elem().Field(0).Int(); port != 7070 {
+		t.Errorf("expected port 7070, got %d", port)
+	}
+
+	for _, dst := range []interface{}{nil, SomeStruct{}, (*SomeStruct)(nil), new(int)} {
+		if err := LoadInto(appName, dst); err == nil || !strings.Contains(err.Error(), "LoadInto requires") {
+			t.Errorf("expected an error for %T, got %v", dst, err)
+		}
+	}
+}
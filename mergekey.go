@@ -0,0 +1,82 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mergeKeyedSliceEnv merges the env elements of the struct slice value, a
+// field tagged mergekey:"<field>", into the elements loaded from the file.
+// Each env element is matched by the value of the key field rather than by
+// index: a match is overridden in place, other elements are appended. Reading
+// stops at the first index without env vars.
+//
+// Example:
+//
+//	Servers []Server `toml:"servers" env:"SERVERS" mergekey:"name"`
+//
+//	MYAPP_SERVERS_0_NAME=b MYAPP_SERVERS_0_PORT=9001 overrides the server named "b"
+func mergeKeyedSliceEnv(lookup envLookup, value reflect.Value, fieldInfo FieldInfo, envKeyBase, path string) error {
+	if fieldInfo.Merge != "" {
+		return fmt.Errorf("merge and mergekey tags cannot be combined on field %s", path)
+	}
+
+	elemType, _ := structElem(fieldInfo.Type)
+	keyIndex, keyInfo, ok := mergeKeyField(elemType, fieldInfo.MergeKey)
+	if !ok {
+		return fmt.Errorf("mergekey tag '%s' on field %s does not name a string field of %s", fieldInfo.MergeKey, path, elemType)
+	}
+
+	// Index the file's elements by their key
+	positions := make(map[string]int, value.Len())
+	for j := 0; j < value.Len(); j++ {
+		if elem := derefElem(value.Index(j)); elem.IsValid() {
+			positions[elem.Field(keyIndex).String()] = j
+		}
+	}
+
+	for i := 0; ; i++ {
+		prefix := indexEnvKey(envKeyBase, i)
+		if !hasStructEnvValues(lookup, reflect.New(elemType).Elem(), prefix) {
+			return nil
+		}
+
+		key, _ := lookup(fieldEnvKey(prefix, keyInfo))
+		if key == "" {
+			return fmt.Errorf("env var %s is required to match element %d of %s by %s", fieldEnvKey(prefix, keyInfo), i, path, keyInfo.Key)
+		}
+
+		if j, found := positions[key]; found {
+			if err := loadStructEnv(lookup, derefElem(value.Index(j)), prefix, indexPath(path, j)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		elem := reflect.New(elemType)
+		if err := loadStructEnv(lookup, elem.Elem(), prefix, indexPath(path, value.Len())); err != nil {
+			return err
+		}
+		if fieldInfo.Type.Elem().Kind() == reflect.Ptr {
+			value.Set(reflect.Append(value, elem))
+		} else {
+			value.Set(reflect.Append(value, elem.Elem()))
+		}
+		positions[key] = value.Len() - 1
+	}
+}
+
+// mergeKeyField returns the index and info of the string field of t named by
+// a mergekey tag, matching its TOML key or field name case-insensitively.
+func mergeKeyField(t reflect.Type, name string) (int, FieldInfo, bool) {
+	for i, fieldInfo := range getCachedTypeInfo(t).Fields {
+		if fieldInfo.Type.Kind() != reflect.String {
+			continue
+		}
+		if strings.EqualFold(fieldInfo.Key, name) || strings.EqualFold(fieldInfo.Name, name) {
+			return i, fieldInfo, true
+		}
+	}
+	return -1, FieldInfo{}, false
+}
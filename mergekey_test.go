@@ -0,0 +1,77 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestStructSliceMergeKey는 mergekey 태그로 환경변수 요소가 순서와 무관하게 이름이 같은 TOML 요소에 적용되는지 테스트합니다
+func TestStructSliceMergeKey(t *testing.T) {
+	type KeyedServer struct {
+		Name string `toml:"name" env:"NAME"`
+		Port int    `toml:"port" env:"PORT"`
+	}
+	type KeyedConfig struct {
+		Servers  []KeyedServer  `toml:"servers" env:"SERVERS" mergekey:"name"`
+		Backends []*KeyedServer `toml:"backends" env:"BACKENDS" mergekey:"Name"`
+	}
+
+	for _, tc := range []struct {
+		name     string
+		toml     string
+		expected []KeyedServer
+	}{
+		{
+			name:     "a before b",
+			toml:     "[[servers]]\nname = \"a\"\nport = 1\n\n[[servers]]\nname = \"b\"\nport = 2\n",
+			expected: []KeyedServer{{"a", 1}, {"b", 9002}, {"c", 3}},
+		},
+		{
+			name:     "b before a",
+			toml:     "[[servers]]\nname = \"b\"\nport = 2\n\n[[servers]]\nname = \"a\"\nport = 1\n",
+			expected: []KeyedServer{{"b", 9002}, {"a", 1}, {"c", 3}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resetGlobalConfig()
+			appName := "mergekeyapp"
+			_, cleanup := createTestTomlFile(t, appName, tc.toml+"\n[[backends]]\nname = \"db\"\nport = 5432\n")
+			defer cleanup()
+
+			t.Setenv("MERGEKEYAPP_SERVERS_0_NAME", "b")
+			t.Setenv("MERGEKEYAPP_SERVERS_0_PORT", "9002")
+			t.Setenv("MERGEKEYAPP_SERVERS_1_NAME", "c")
+			t.Setenv("MERGEKEYAPP_SERVERS_1_PORT", "3")
+			t.Setenv("MERGEKEYAPP_BACKENDS_0_NAME", "db")
+			t.Setenv("MERGEKEYAPP_BACKENDS_0_PORT", "6432")
+
+			AppName = appName
+			if err := LoadConfig[KeyedConfig](); err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			cfg := GetConfig[KeyedConfig]()
+
+			if !reflect.DeepEqual(cfg.Servers, tc.expected) {
+				t.Errorf("expected servers %+v, got %+v", tc.expected, cfg.Servers)
+			}
+			if len(cfg.Backends) != 1 || *cfg.Backends[0] != (KeyedServer{"db", 6432}) {
+				t.Errorf("expected backend db to be overridden, got %+v", cfg.Backends)
+			}
+		})
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		var cfg KeyedConfig
+		lookup := func(key string) (string, bool) {
+			if key == "APP_SERVERS_0_PORT" {
+				return "80", true
+			}
+			return "", false
+		}
+		err := loadNestedStructEnv(lookup, reflect.ValueOf(&cfg).Elem(), "APP", "")
+		if err == nil || !strings.Contains(err.Error(), "APP_SERVERS_0_NAME") {
+			t.Errorf("expected error about the missing key env var, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// loadNegatedEnv sets the bool field value, tagged negate:"true", to the
+// opposite of envValue. An unparseable value is handled like any other parse
+// error, or recorded as a strict error on a strict field. path is the dotted
+// path of the field.
+func loadNegatedEnv(value reflect.Value, fieldInfo FieldInfo, envValue, path string) error {
+	if fieldInfo.Type.Kind() != reflect.Bool {
+		return fmt.Errorf("negate tag on field %s requires a bool field", path)
+	}
+
+	parsed, err := parseFieldValue(envValue, fieldInfo, path)
+	if err != nil {
+		if fieldInfo.Strict {
+			strictEnvErrors = append(strictEnvErrors, err)
+			return nil
+		}
+		if skipParseError(err) {
+			return nil
+		}
+		return err
+	}
+	value.SetBool(!reflect.ValueOf(parsed).Bool())
+	return nil
+}
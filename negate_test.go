@@ -0,0 +1,55 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestNegateTag는 negate 태그가 있는 bool 필드가 환경변수 값의 반대로 설정되는지 테스트합니다
+func TestNegateTag(t *testing.T) {
+	type NegateConfig struct {
+		EnableCache   bool `toml:"enable_cache" env:"DISABLE_CACHE" negate:"true"`
+		EnableMetrics bool `toml:"enable_metrics" env:"DISABLE_METRICS" negate:"true" default:"true"`
+		EnableTracing bool `toml:"enable_tracing" env:"DISABLE_TRACING" negate:"true" default:"true"`
+		EnableLogs    bool `toml:"enable_logs" env:"DISABLE_LOGS" negate:"true"`
+	}
+
+	resetGlobalConfig()
+	appName := "negateapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+enable_cache = true
+enable_logs = true
+`)
+	defer cleanup()
+
+	t.Setenv("NEGATEAPP_DISABLE_CACHE", "true")
+	t.Setenv("NEGATEAPP_DISABLE_METRICS", "false")
+
+	AppName = appName
+	if err := LoadConfig[NegateConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[NegateConfig]()
+
+	if cfg.EnableCache {
+		t.Error("expected DISABLE_CACHE=true to set EnableCache to false")
+	}
+	if !cfg.EnableMetrics {
+		t.Error("expected DISABLE_METRICS=false to set EnableMetrics to true")
+	}
+	if !cfg.EnableTracing {
+		t.Error("expected the default to apply to the field as is")
+	}
+	if !cfg.EnableLogs {
+		t.Error("expected the TOML value to apply to the field as is")
+	}
+}
+
+// TestNegateTagNonBool는 bool이 아닌 필드의 negate 태그가 오류가 되는지 테스트합니다
+func TestNegateTagNonBool(t *testing.T) {
+	fieldInfo := FieldInfo{Type: reflect.TypeOf(""), Negate: true}
+	var s string
+	if err := loadNegatedEnv(reflect.ValueOf(&s).Elem(), fieldInfo, "true", "name"); err == nil {
+		t.Error("expected an error for a negate tag on a string field")
+	}
+}
@@ -0,0 +1,260 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// EmptyEnvMode controls how an environment variable that is set to an empty
+// string (APP_FOO=) is treated.
+type EmptyEnvMode int
+
+const (
+	// EmptyIsUnset treats an empty env var like an unset one, keeping the
+	// TOML value or default (the default).
+	EmptyIsUnset EmptyEnvMode = iota
+	// EmptyClears makes an explicitly empty env var clear the field to its
+	// zero value, overriding any TOML value or default.
+	EmptyClears
+)
+
+var emptyEnvMode = EmptyIsUnset
+
+// SetEmptyEnvMode sets how explicitly empty environment variables are treated
+// by subsequent loads.
+//
+// Example:
+//
+//	ahatconfig.SetEmptyEnvMode(ahatconfig.EmptyClears)
+//	// MYAPP_DATABASE_USER= now clears a user set in myapp.toml
+func SetEmptyEnvMode(mode EmptyEnvMode) {
+	emptyEnvMode = mode
+}
+
+// secretPredicate reports additional secret fields on top of the secret tag.
+var secretPredicate func(fieldPath, fieldName string) bool
+
+// SetSecretPredicate sets a function that marks fields as secret in addition
+// to the secret tag, so that an organization-wide masking policy can be
+// enforced without annotating every struct. It receives the dotted field path
+// (e.g. "users[0].api_token") and the Go field name. Pass nil to rely on
+// tags only.
+//
+// Example:
+//
+//	ahatconfig.SetSecretPredicate(func(fieldPath, fieldName string) bool {
+//	    name := strings.ToLower(fieldName)
+//	    return strings.Contains(name, "password") || strings.Contains(name, "token")
+//	})
+func SetSecretPredicate(predicate func(fieldPath, fieldName string) bool) {
+	secretPredicate = predicate
+}
+
+// isSecretField reports whether the field at path is masked, either by its
+// secret tag or by the secret predicate.
+func isSecretField(fieldInfo FieldInfo, path string) bool {
+	return fieldInfo.Secret || (secretPredicate != nil && secretPredicate(path, fieldInfo.Name))
+}
+
+// environment is the active environment set with SetEnvironment.
+var environment string
+
+// SetEnvironment sets the active environment, such as "dev" or "prod", used
+// by the requiredenv tag and to select the secret providers registered with
+// RegisterSecretProviderFor. When it is not set, the APP_ENV environment
+// variable is used.
+//
+// Example:
+//
+//	ahatconfig.SetEnvironment("prod")
+func SetEnvironment(name string) {
+	environment = name
+}
+
+// Environment returns the active environment: the value given to
+// SetEnvironment, else the APP_ENV environment variable.
+func Environment() string {
+	if environment != "" {
+		return environment
+	}
+	return getEnv("APP_ENV")
+}
+
+// requiredInEnvironment reports whether the active environment is one of
+// envs, compared case-insensitively.
+func requiredInEnvironment(envs []string) bool {
+	if len(envs) == 0 {
+		return false
+	}
+	active := Environment()
+	for _, env := range envs {
+		if strings.EqualFold(env, active) {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredByDefault makes fields without a required tag required.
+var requiredByDefault bool
+
+// SetRequiredByDefault makes every field required in subsequent loads unless
+// it is tagged optional:"true", for services that prefer to fail closed.
+// A field with a default tag or a runtime default always satisfies the
+// requirement, even when the default is a zero value such as "false".
+//
+// Example:
+//
+//	ahatconfig.SetRequiredByDefault(true)
+func SetRequiredByDefault(required bool) {
+	requiredByDefault = required
+}
+
+// isRequiredField reports whether the field at path must have a non-empty
+// value: it is tagged required, required in the active environment, or
+// required by default. Unexported fields and toml:",remain" maps are never
+// required by default.
+func isRequiredField(field reflect.StructField, fieldInfo FieldInfo, path string) bool {
+	if fieldInfo.Required || requiredInEnvironment(fieldInfo.RequiredEnv) {
+		return true
+	}
+	if !requiredByDefault || fieldInfo.Optional || !field.IsExported() || isRemainField(field) {
+		return false
+	}
+	return fieldDefault(fieldInfo, path) == "" && !fieldInfo.DefaultRefs
+}
+
+// ParseErrorMode controls what happens when an environment value cannot be
+// parsed into its field.
+type ParseErrorMode int
+
+const (
+	// FailFast stops loading environment variables at the first value that
+	// fails to parse (the default).
+	FailFast ParseErrorMode = iota
+	// SkipAndCount leaves a field that fails to parse at its previous value,
+	// records the error and continues loading. Recorded errors are
+	// retrievable with ParseErrors.
+	SkipAndCount
+)
+
+var parseErrorMode = FailFast
+
+// SetOnParseError sets how values that fail to parse are handled by
+// subsequent loads.
+//
+// Example:
+//
+//	ahatconfig.SetOnParseError(ahatconfig.SkipAndCount)
+//	ahatconfig.InitConfig[MyConfig]("myapp")
+//	for _, err := range ahatconfig.ParseErrors() {
+//	    log.Printf("skipped: %v", err)
+//	}
+func SetOnParseError(mode ParseErrorMode) {
+	parseErrorMode = mode
+}
+
+// DefaultMaxDepth is the nesting limit used when SetMaxDepth is not called.
+const DefaultMaxDepth = 32
+
+var maxDepth = DefaultMaxDepth
+
+// SetMaxDepth sets how deeply fields may be nested, counted in path segments
+// from the config root ("users[0].address.city" has depth 3). Loading or
+// validating a deeper config fails with an error instead of recursing without
+// bound, which guards against self-referential types such as a tree node
+// holding children of its own type. Values below 1 restore DefaultMaxDepth.
+func SetMaxDepth(depth int) {
+	if depth < 1 {
+		depth = DefaultMaxDepth
+	}
+	maxDepth = depth
+}
+
+// checkDepth returns an error when the field at path is nested deeper than
+// the maximum depth.
+func checkDepth(path string) error {
+	if path != "" && strings.Count(path, ".")+1 > maxDepth {
+		return fmt.Errorf("config nesting exceeds the maximum depth of %d at %s (recursive type?)", maxDepth, path)
+	}
+	return nil
+}
+
+// sliceDelimiter separates the elements of list values.
+var sliceDelimiter = ','
+
+// SetSliceDelimiter sets the delimiter of list values read from environment
+// variables and default tags in subsequent loads (',' by default). A space
+// splits on runs of whitespace, as in JAVA_OPTS-style variables; elements
+// containing spaces can then be double-quoted. The delim tag overrides it
+// per field. The delimiter cannot be a double quote or a line break.
+//
+// Example:
+//
+//	ahatconfig.SetSliceDelimiter(' ')
+//	// MYAPP_PORTS="8080 8081 8082"
+func SetSliceDelimiter(delim rune) {
+	if !validDelim(delim) {
+		log.Printf("Invalid slice delimiter %q, keeping %q", delim, sliceDelimiter)
+		return
+	}
+	sliceDelimiter = delim
+}
+
+// parseDelim parses the single-character delimiter of a delim tag.
+func parseDelim(tag string) (rune, error) {
+	delim, size := utf8.DecodeRuneInString(tag)
+	if size != len(tag) || !validDelim(delim) {
+		return 0, fmt.Errorf("delimiter must be a single character other than a quote or line break")
+	}
+	return delim, nil
+}
+
+// validDelim reports whether delim can separate list elements.
+func validDelim(delim rune) bool {
+	return delim != '"' && delim != '\r' && delim != '\n' && delim != utf8.RuneError
+}
+
+// envPrefixFallbacks are the prefixes tried, in order, for an environment
+// variable that is not set under the app prefix.
+var envPrefixFallbacks []string
+
+// SetEnvPrefixFallbacks sets prefixes that are tried in order for each
+// environment variable not set under the app prefix, so that an app prefix
+// can be renamed without changing every deployment at once. A variable set
+// under the app prefix always wins, even when it is empty. Pass nil to only
+// use the app prefix.
+//
+// Example:
+//
+//	// NEWAPP_SERVER_HOST is read first, then OLDAPP_SERVER_HOST
+//	ahatconfig.SetEnvPrefixFallbacks([]string{"oldapp"})
+//	err := ahatconfig.InitConfigSafe[AppConfig]("newapp")
+func SetEnvPrefixFallbacks(prefixes []string) {
+	envPrefixFallbacks = append([]string(nil), prefixes...)
+}
+
+// withPrefixFallbacks wraps lookup so that a variable named under prefix and
+// not set is looked up under each fallback prefix in turn.
+func withPrefixFallbacks(lookup envLookup, prefix string) envLookup {
+	if len(envPrefixFallbacks) == 0 {
+		return lookup
+	}
+
+	normalizedPrefix := envPrefix(prefix)
+	return func(key string) (string, bool) {
+		if value, ok := lookup(key); ok || !strings.HasPrefix(key, normalizedPrefix) {
+			return value, ok
+		}
+		for _, fallback := range envPrefixFallbacks {
+			fallbackKey := envPrefix(fallback) + strings.TrimPrefix(key, normalizedPrefix)
+			if value, ok := lookup(fallbackKey); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
@@ -0,0 +1,353 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestEmptyEnvClears는 EmptyClears 모드에서 빈 환경변수가 TOML 값을 지우는지 테스트합니다
+func TestEmptyEnvClears(t *testing.T) {
+	tomlContent := `
+[server]
+host = "localhost"
+port = 8000
+
+[database]
+user = "tomluser"
+`
+
+	t.Run("empty env var clears TOML value", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		appName := "app"
+		_, cleanup := createTestTomlFile(t, appName, tomlContent)
+		defer cleanup()
+
+		AppName = appName
+		SetEmptyEnvMode(EmptyClears)
+		t.Setenv("APP_DATABASE_USER", "")
+		t.Setenv("APP_SERVER_PORT", "")
+
+		// DATABASE_USER는 필수 필드이므로 경고 모드로 로드해 결과를 확인한다
+		SetValidationMode(Warn)
+		if err := LoadConfig[TestConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[TestConfig]()
+		if cfg.Database.User != "" {
+			t.Errorf("expected db user to be cleared, got '%s'", cfg.Database.User)
+		}
+		if cfg.Server.Port != 0 {
+			t.Errorf("expected server port to be cleared to 0, got %d", cfg.Server.Port)
+		}
+		if cfg.Server.Host != "localhost" {
+			t.Errorf("expected server host to keep the TOML value, got '%s'", cfg.Server.Host)
+		}
+	})
+
+	t.Run("empty env var is ignored by default", func(t *testing.T) {
+		resetGlobalConfig()
+		appName := "app"
+		_, cleanup := createTestTomlFile(t, appName, tomlContent)
+		defer cleanup()
+
+		AppName = appName
+		t.Setenv("APP_DATABASE_USER", "")
+
+		if err := LoadConfig[TestConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[TestConfig]()
+		if cfg.Database.User != "tomluser" {
+			t.Errorf("expected db user to keep the TOML value, got '%s'", cfg.Database.User)
+		}
+	})
+}
+
+// TestSecretPredicate는 secret 태그가 없는 필드도 SetSecretPredicate 정책에 따라 마스킹되는지 테스트합니다
+func TestSecretPredicate(t *testing.T) {
+	type PredicateConfig struct {
+		API struct {
+			APIToken string `toml:"api_token" env:"API_TOKEN"`
+			Endpoint string `toml:"endpoint" env:"ENDPOINT"`
+		} `toml:"api" env:"API"`
+		Users []struct {
+			Name     string `toml:"name" env:"NAME"`
+			Password string `toml:"password" env:"PASSWORD"`
+		} `toml:"users" env:"USERS"`
+		Key string `toml:"key" env:"KEY" secret:"true"`
+	}
+
+	resetGlobalConfig()
+	var paths []string
+	SetSecretPredicate(func(fieldPath, fieldName string) bool {
+		paths = append(paths, fieldPath)
+		name := strings.ToLower(fieldName)
+		return strings.Contains(name, "password") || strings.Contains(name, "token")
+	})
+
+	AppName = "PREDICATE"
+	t.Setenv("PREDICATE_API_API_TOKEN", "tok-123")
+	t.Setenv("PREDICATE_API_ENDPOINT", "https://api.example.com")
+	t.Setenv("PREDICATE_USERS_0_NAME", "alice")
+	t.Setenv("PREDICATE_USERS_0_PASSWORD", "hunter2")
+	t.Setenv("PREDICATE_KEY", "k")
+
+	if err := LoadConfig[PredicateConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	masked := maskedFields(maskSecrets(instance))
+	api := maskedFields(masked["API"])
+	if api["APIToken"] != "****" {
+		t.Errorf("expected APIToken to be masked by the predicate, got %v", api["APIToken"])
+	}
+	if api["Endpoint"] != "https://api.example.com" {
+		t.Errorf("expected Endpoint to stay visible, got %v", api["Endpoint"])
+	}
+	user := maskedFields(masked["Users"].([]interface{})[0])
+	if user["Password"] != "****" || user["Name"] != "alice" {
+		t.Errorf("expected only the user password to be masked, got %v", user)
+	}
+	if masked["Key"] != "****" {
+		t.Errorf("expected tag-based secret to stay masked, got %v", masked["Key"])
+	}
+
+	exported := AsMaskedMap()
+	if exported["api"].(map[string]interface{})["api_token"] != "****" {
+		t.Errorf("expected AsMaskedMap to apply the predicate, got %v", exported["api"])
+	}
+
+	found := false
+	for _, p := range paths {
+		if p == "users[0].password" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the predicate to receive indexed paths, got %v", paths)
+	}
+}
+
+// TestRequiredEnvTag는 requiredenv 태그가 활성 환경이 일치할 때만 필수 검사를 하는지 테스트합니다
+func TestRequiredEnvTag(t *testing.T) {
+	type RequiredEnvConfig struct {
+		Host     string `toml:"host" env:"HOST" required:"true"`
+		Password string `toml:"password" env:"PASSWORD" requiredenv:"prod,staging"`
+	}
+
+	t.Run("missing in dev passes", func(t *testing.T) {
+		resetGlobalConfig()
+		SetEnvironment("dev")
+		AppName = "REQENVDEV"
+		t.Setenv("REQENVDEV_HOST", "localhost")
+
+		if err := LoadConfig[RequiredEnvConfig](); err != nil {
+			t.Fatalf("expected missing password to be allowed in dev, got %v", err)
+		}
+	})
+
+	t.Run("missing in prod fails", func(t *testing.T) {
+		resetGlobalConfig()
+		SetEnvironment("prod")
+		AppName = "REQENVPROD"
+		t.Setenv("REQENVPROD_HOST", "localhost")
+
+		err := LoadConfig[RequiredEnvConfig]()
+		expectedError := "required field 'PASSWORD' is missing or empty at password"
+		if err == nil || err.Error() != expectedError {
+			t.Errorf("expected error '%s', got '%v'", expectedError, err)
+		}
+	})
+
+	t.Run("environment from APP_ENV", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "REQENVVAR"
+		t.Setenv("APP_ENV", "Staging")
+		t.Setenv("REQENVVAR_HOST", "localhost")
+
+		if Environment() != "Staging" {
+			t.Errorf("expected environment 'Staging' from APP_ENV, got %q", Environment())
+		}
+		err := LoadConfig[RequiredEnvConfig]()
+		if err == nil || !strings.Contains(err.Error(), "required field 'PASSWORD' is missing or empty") {
+			t.Errorf("expected password to be required in staging, got %v", err)
+		}
+	})
+}
+
+// depthNode는 자기 자신을 자식으로 가지는 재귀 타입입니다
+type depthNode struct {
+	Name     string      `toml:"name" env:"NAME" required:"true"`
+	Children []depthNode `toml:"children" env:"CHILDREN"`
+}
+
+// TestMaxDepth는 재귀 타입이 최대 깊이를 넘으면 명확한 에러로 끝나는지 테스트합니다
+func TestMaxDepth(t *testing.T) {
+	type DepthConfig struct {
+		Root depthNode `toml:"root"`
+	}
+
+	// root.children[0].children[0]... 를 6단계 중첩
+	var b strings.Builder
+	b.WriteString("[root]\nname = \"n0\"\n")
+	table := "root"
+	for i := 1; i <= 6; i++ {
+		table += ".children"
+		fmt.Fprintf(&b, "\n[[%s]]\nname = \"n%d\"\n", table, i)
+	}
+
+	t.Run("exceeding the limit fails", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "depthapp", b.String())
+		defer cleanup()
+		AppName = "depthapp"
+		SetMaxDepth(4)
+
+		err := LoadConfig[DepthConfig]()
+		if err == nil {
+			t.Fatal("expected an error for a config nested deeper than the limit")
+		}
+		if !strings.Contains(err.Error(), "maximum depth of 4") {
+			t.Errorf("expected a maximum depth error, got '%v'", err)
+		}
+	})
+
+	t.Run("within the default limit loads", func(t *testing.T) {
+		resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "depthapp", b.String())
+		defer cleanup()
+		AppName = "depthapp"
+
+		if err := LoadConfig[DepthConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DepthConfig]()
+		if cfg.Root.Children[0].Children[0].Name != "n2" {
+			t.Errorf("expected nested children to load, got %+v", cfg.Root)
+		}
+	})
+
+	t.Run("type walkers terminate", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "depthapp"
+
+		fields := Describe[DepthConfig]()
+		if len(fields) != 2 || fields[1].Path != "root.children" || fields[1].Type != "[]ahatconfig.depthNode" {
+			t.Errorf("expected the recursive field to be described once, got %+v", fields)
+		}
+		if _, err := JSONSchema[DepthConfig](); err != nil {
+			t.Errorf("JSONSchema failed: %v", err)
+		}
+	})
+}
+
+// TestSliceDelimiter는 SetSliceDelimiter와 delim 태그로 공백 등 다른 구분자를 사용할 수 있는지 테스트합니다
+func TestSliceDelimiter(t *testing.T) {
+	type DelimConfig struct {
+		Ports   []int    `toml:"ports" env:"PORTS"`
+		Opts    []string `toml:"opts" env:"OPTS" delim:" "`
+		Weights []int    `toml:"weights" env:"WEIGHTS" delim:";"`
+		Bad     []int    `toml:"bad" env:"BAD" delim:"ab"`
+	}
+
+	t.Run("comma by default", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "delimapp"
+		t.Setenv("DELIMAPP_PORTS", "80, 443,8080")
+
+		if err := LoadConfig[DelimConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DelimConfig]()
+		if !reflect.DeepEqual(cfg.Ports, []int{80, 443, 8080}) {
+			t.Errorf("expected ports [80 443 8080], got %v", cfg.Ports)
+		}
+	})
+
+	t.Run("space delimiter", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "delimapp"
+		SetSliceDelimiter(' ')
+		t.Setenv("DELIMAPP_PORTS", "80  443\t8080")
+
+		if err := LoadConfig[DelimConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DelimConfig]()
+		if !reflect.DeepEqual(cfg.Ports, []int{80, 443, 8080}) {
+			t.Errorf("expected ports [80 443 8080], got %v", cfg.Ports)
+		}
+	})
+
+	t.Run("delim tag", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "delimapp"
+		t.Setenv("DELIMAPP_OPTS", `-Xmx1g "-Dgreeting=hello, world" -server`)
+		t.Setenv("DELIMAPP_WEIGHTS", "1;2;3")
+
+		if err := LoadConfig[DelimConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[DelimConfig]()
+		expectedOpts := []string{"-Xmx1g", "-Dgreeting=hello, world", "-server"}
+		if !reflect.DeepEqual(cfg.Opts, expectedOpts) {
+			t.Errorf("expected opts %q, got %q", expectedOpts, cfg.Opts)
+		}
+		if !reflect.DeepEqual(cfg.Weights, []int{1, 2, 3}) {
+			t.Errorf("expected weights [1 2 3], got %v", cfg.Weights)
+		}
+	})
+
+	t.Run("invalid delim tag", func(t *testing.T) {
+		_, err := parseFieldValue("1ab2", getCachedTypeInfo(reflect.TypeOf(DelimConfig{})).Fields[3], "bad")
+		if err == nil || !strings.Contains(err.Error(), "invalid delim tag 'ab' on field bad") {
+			t.Errorf("expected an invalid delim tag error, got %v", err)
+		}
+	})
+}
+
+// TestEnvPrefixFallbacks는 앱 접두사로 설정되지 않은 환경변수를 대체 접두사에서 순서대로 찾는지 테스트합니다
+func TestEnvPrefixFallbacks(t *testing.T) {
+	type FallbackConfig struct {
+		Host    string `env:"HOST" required:"true"`
+		Port    int    `env:"PORT" default:"80"`
+		User    string `env:"USER"`
+		Servers []struct {
+			Name string `env:"NAME"`
+		} `env:"SERVERS"`
+	}
+
+	resetGlobalConfig()
+	SetEnvPrefixFallbacks([]string{"old-app", "legacy"})
+	t.Setenv("NEWAPP_USER", "new")
+	t.Setenv("OLD_APP_HOST", "old.example.com")
+	t.Setenv("OLD_APP_USER", "old")
+	t.Setenv("LEGACY_HOST", "legacy.example.com")
+	t.Setenv("LEGACY_PORT", "8080")
+	t.Setenv("LEGACY_SERVERS_0_NAME", "alpha")
+
+	if err := InitConfigSafe[FallbackConfig]("newapp"); err != nil {
+		t.Fatalf("InitConfigSafe failed: %v", err)
+	}
+	cfg := GetConfig[FallbackConfig]()
+
+	if cfg.Host != "old.example.com" {
+		t.Errorf("expected host from the first fallback prefix, got %q", cfg.Host)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("expected port from the second fallback prefix, got %d", cfg.Port)
+	}
+	if cfg.User != "new" {
+		t.Errorf("expected the app prefix to win, got %q", cfg.User)
+	}
+	if len(cfg.Servers) != 1 || cfg.Servers[0].Name != "alpha" {
+		t.Errorf("expected servers from the fallback prefix, got %+v", cfg.Servers)
+	}
+}
@@ -0,0 +1,64 @@
+package ahatconfig
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// refreshInterval is the age after which GetConfigSafe refreshes the
+	// configuration in the background; 0 disables refreshing.
+	refreshInterval time.Duration
+	// loadedAt is the time the configuration was last loaded or refreshed,
+	// guarded by instanceMu.
+	loadedAt time.Time
+	// refreshing is set while a background refresh runs.
+	refreshing atomic.Bool
+	// now returns the current time. It is a variable so tests can control
+	// the age of the configuration.
+	now = time.Now
+)
+
+// SetRefreshInterval makes GetConfig and GetConfigSafe refresh the
+// configuration when it is older than d, for applications that tolerate
+// slightly stale values but do not want to set up a watcher. The refresh
+// rebuilds the configuration from the sources it was loaded from, like
+// ReloadConfig, in the background; the call that notices the stale
+// configuration returns it without waiting, and later calls see the new one.
+// A refresh that fails is logged and retried after another interval. A zero
+// or negative d disables refreshing (the default). Shutdown waits for a
+// running refresh.
+//
+// Example:
+//
+//	ahatconfig.SetRefreshInterval(30 * time.Second)
+func SetRefreshInterval(d time.Duration) {
+	refreshInterval = d
+}
+
+// refreshIfStale starts a background refresh of the configuration of type T
+// when it is older than the refresh interval and no refresh is running.
+func refreshIfStale[T any]() {
+	if refreshInterval <= 0 {
+		return
+	}
+	instanceMu.RLock()
+	stale := now().Sub(loadedAt) >= refreshInterval
+	instanceMu.RUnlock()
+	if !stale || !refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	// The refresh ignores done and leaves the running tasks when it finishes
+	startBackground(func(done <-chan struct{}) {
+		defer refreshing.Store(false)
+		if _, _, err := reloadConfig[T](); err != nil {
+			log.Printf("Config refresh failed, keeping current config: %v", err)
+		}
+		// An unchanged or failed refresh is not stored, so restart the clock
+		instanceMu.Lock()
+		loadedAt = now()
+		instanceMu.Unlock()
+	})
+}
@@ -0,0 +1,105 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRefreshInterval은 설정이 갱신 주기보다 오래되면 GetConfig가 백그라운드에서 다시 로드해 바뀐 환경변수를 반영하는지 테스트합니다
+func TestRefreshInterval(t *testing.T) {
+	type RefreshConfig struct {
+		Port int `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+
+	var clockMu sync.Mutex
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		clock = clock.Add(d)
+		clockMu.Unlock()
+	}
+
+	AppName = "refreshapp"
+	t.Setenv("REFRESHAPP_PORT", "8080")
+	if err := LoadConfig[RefreshConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	SetRefreshInterval(time.Minute)
+	t.Setenv("REFRESHAPP_PORT", "9090")
+
+	// 주기가 지나기 전에는 다시 로드하지 않는다
+	advance(30 * time.Second)
+	GetConfig[RefreshConfig]()
+	time.Sleep(20 * time.Millisecond)
+	if got := GetConfig[RefreshConfig]().Port; got != 8080 {
+		t.Fatalf("expected no refresh before the interval, got port %d", got)
+	}
+
+	// 주기가 지나면 오래된 값을 바로 반환하고 백그라운드에서 갱신한다
+	advance(time.Minute)
+	if got := GetConfig[RefreshConfig]().Port; got != 8080 {
+		t.Errorf("expected the stale config to be returned without waiting, got port %d", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for GetConfig[RefreshConfig]().Port != 9090 {
+		if time.Now().After(deadline) {
+			t.Fatal("config was not refreshed after the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestRefreshKeepsSource는 갱신이 LoadConfig의 파일 검색이 아니라 설정을 로드한 파일에서 다시 읽는지 테스트합니다
+func TestRefreshKeepsSource(t *testing.T) {
+	type RefreshSourceConfig struct {
+		Port int `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var clockMu sync.Mutex
+	now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}
+
+	exact := filepath.Join(t.TempDir(), "exact.toml")
+	if err := os.WriteFile(exact, []byte("port = 8080\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := InitConfigWithExactFile[RefreshSourceConfig]("refreshsource", exact); err != nil {
+		t.Fatalf("InitConfigWithExactFile failed: %v", err)
+	}
+	SetRefreshInterval(time.Minute)
+	if err := os.WriteFile(exact, []byte("port = 9090\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+
+	clockMu.Lock()
+	clock = clock.Add(2 * time.Minute)
+	clockMu.Unlock()
+	GetConfig[RefreshSourceConfig]()
+	deadline := time.Now().Add(time.Second)
+	for GetConfig[RefreshSourceConfig]().Port != 9090 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the refresh to read the exact file, got port %d", GetConfig[RefreshSourceConfig]().Port)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,103 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// isRemainField reports whether field collects unknown keys, marked with the
+// remain option of its toml tag (toml:",remain").
+func isRemainField(field reflect.StructField) bool {
+	options := strings.Split(field.Tag.Get("toml"), ",")[1:]
+	for _, option := range options {
+		if strings.TrimSpace(option) == "remain" {
+			return field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.String
+		}
+	}
+	return false
+}
+
+// captureRemainingKeys stores the keys of tree that match no field of the
+// struct v in v's remain field, if it has one. It descends into tables and
+// arrays of tables so nested sections can keep their unknown keys too.
+func captureRemainingKeys(tree *toml.Tree, v reflect.Value) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+
+	t := v.Type()
+	typeInfo := getCachedTypeInfo(t)
+	remain := -1
+
+	for i, fieldInfo := range typeInfo.Fields {
+		if isRemainField(t.Field(i)) {
+			remain = i
+			continue
+		}
+
+		switch raw := tree.GetPath([]string{fieldInfo.Key}).(type) {
+		case *toml.Tree:
+			captureRemainingKeys(raw, v.Field(i))
+		case []*toml.Tree:
+			if field := v.Field(i); isStructList(fieldInfo.Type) {
+				for j := 0; j < len(raw) && j < field.Len(); j++ {
+					captureRemainingKeys(raw[j], field.Index(j))
+				}
+			}
+		}
+	}
+
+	if remain < 0 {
+		return
+	}
+
+	field := v.Field(remain)
+	for _, key := range tree.Keys() {
+		if knownKey(typeInfo, key) {
+			continue
+		}
+		value := reflect.ValueOf(remainValue(tree.GetPath([]string{key})))
+		if !value.IsValid() || !value.Type().AssignableTo(field.Type().Elem()) {
+			continue
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(key).Convert(field.Type().Key()), value)
+	}
+}
+
+// knownKey reports whether key is decoded into a field of the struct
+// described by typeInfo.
+func knownKey(typeInfo *TypeInfo, key string) bool {
+	for _, fieldInfo := range typeInfo.Fields {
+		if fieldInfo.Key == key || strings.EqualFold(fieldInfo.Name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// remainValue converts a tree value into plain maps and slices.
+func remainValue(raw interface{}) interface{} {
+	switch val := raw.(type) {
+	case *toml.Tree:
+		return val.ToMap()
+	case []*toml.Tree:
+		tables := make([]interface{}, len(val))
+		for i, table := range val {
+			tables[i] = table.ToMap()
+		}
+		return tables
+	default:
+		return raw
+	}
+}
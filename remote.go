@@ -0,0 +1,137 @@
+package ahatconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultURLTimeout is the request timeout used by InitConfigFromURL when
+// no WithURLTimeout option is given.
+const defaultURLTimeout = 10 * time.Second
+
+// URLOption configures how InitConfigFromURL fetches the configuration document.
+type URLOption func(*urlOptions)
+
+type urlOptions struct {
+	timeout time.Duration
+	headers http.Header
+}
+
+// WithURLTimeout sets the timeout for fetching the configuration document.
+func WithURLTimeout(timeout time.Duration) URLOption {
+	return func(o *urlOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithURLHeader adds a request header sent when fetching the configuration document.
+func WithURLHeader(key, value string) URLOption {
+	return func(o *urlOptions) {
+		o.headers.Add(key, value)
+	}
+}
+
+// WithURLBasicAuth authenticates the request with HTTP basic authentication.
+func WithURLBasicAuth(username, password string) URLOption {
+	return func(o *urlOptions) {
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		o.headers.Set("Authorization", "Basic "+credentials)
+	}
+}
+
+// WithURLBearerToken authenticates the request with a bearer token.
+func WithURLBearerToken(token string) URLOption {
+	return func(o *urlOptions) {
+		o.headers.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// InitConfigFromURL initializes configuration from a TOML or JSON document
+// served over HTTP(S). The document is used as the base layer instead of the
+// local TOML file, and environment variables are applied on top of it.
+// The format is detected from the Content-Type header, falling back to the
+// URL extension (.json or .toml) and finally to TOML.
+//
+// Example:
+//
+//	err := ahatconfig.InitConfigFromURL[MyConfig]("myapp", "https://config.internal/myapp.toml",
+//	    ahatconfig.WithURLTimeout(5*time.Second),
+//	    ahatconfig.WithURLBearerToken(token))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func InitConfigFromURL[T any](appname, rawURL string, opts ...URLOption) error {
+	AppName = appname
+
+	return loadConfig(func(cfg *T) error {
+		data, format, err := fetchConfigDocument(rawURL, opts)
+		if err != nil {
+			return err
+		}
+		if err := decodeDocument(data, format, cfg); err != nil {
+			return fmt.Errorf("failed to decode config from %s: %w", rawURL, err)
+		}
+		return nil
+	})
+}
+
+// fetchConfigDocument downloads the document at rawURL and returns its body
+// together with the detected format.
+func fetchConfigDocument(rawURL string, opts []URLOption) ([]byte, string, error) {
+	options := urlOptions{
+		timeout: defaultURLTimeout,
+		headers: http.Header{},
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid config URL %s: %w", rawURL, err)
+	}
+	req.Header = options.headers
+
+	client := &http.Client{Timeout: options.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch config from %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to fetch config from %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read config from %s: %w", rawURL, err)
+	}
+
+	return data, detectURLFormat(resp.Header.Get("Content-Type"), rawURL), nil
+}
+
+// detectURLFormat picks the document format from the Content-Type header,
+// then from the URL path extension, defaulting to TOML.
+func detectURLFormat(contentType, rawURL string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mediaType, "json"):
+			return "json"
+		case strings.HasSuffix(mediaType, "toml"):
+			return "toml"
+		}
+	}
+
+	if u, err := url.Parse(rawURL); err == nil && strings.EqualFold(path.Ext(u.Path), ".json") {
+		return "json"
+	}
+	return "toml"
+}
@@ -0,0 +1,121 @@
+package ahatconfig
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestInitConfigFromURL은 HTTP 서버에서 설정 문서를 가져와 기본 계층으로 사용하는지 테스트합니다
+func TestInitConfigFromURL(t *testing.T) {
+	tomlContent := `
+[server]
+host = "remotehost"
+port = 7000
+
+[database]
+user = "remoteuser"
+`
+
+	t.Run("TOML document with env override", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/toml")
+			_, _ = w.Write([]byte(tomlContent))
+		}))
+		defer server.Close()
+
+		resetGlobalConfig()
+		t.Setenv("REMOTEAPP_SERVER_PORT", "9000")
+
+		err := InitConfigFromURL[TestConfig]("remoteapp", server.URL+"/config",
+			WithURLTimeout(time.Second),
+			WithURLBearerToken("s3cr3t"))
+		if err != nil {
+			t.Fatalf("InitConfigFromURL failed: %v", err)
+		}
+
+		cfg := GetConfig[TestConfig]()
+		if cfg.Server.Host != "remotehost" {
+			t.Errorf("expected server host to be 'remotehost' (from URL), got '%s'", cfg.Server.Host)
+		}
+		if cfg.Server.Port != 9000 {
+			t.Errorf("expected server port to be 9000 (from env), got %d", cfg.Server.Port)
+		}
+		if cfg.Database.User != "remoteuser" {
+			t.Errorf("expected db user to be 'remoteuser' (from URL), got '%s'", cfg.Database.User)
+		}
+	})
+
+	t.Run("JSON document detected by extension", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "admin" || pass != "pw" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"server": {"host": "jsonhost", "port": 7100}, "database": {"user": "jsonuser"}, "users": [{"name": "Alice"}]}`))
+		}))
+		defer server.Close()
+
+		resetGlobalConfig()
+		err := InitConfigFromURL[TestConfig]("remoteapp", server.URL+"/config.json", WithURLBasicAuth("admin", "pw"))
+		if err != nil {
+			t.Fatalf("InitConfigFromURL failed: %v", err)
+		}
+
+		cfg := GetConfig[TestConfig]()
+		if cfg.Server.Host != "jsonhost" || cfg.Server.Port != 7100 {
+			t.Errorf("expected server to be jsonhost:7100, got %s:%d", cfg.Server.Host, cfg.Server.Port)
+		}
+		if len(cfg.Users) != 1 || cfg.Users[0].Name != "Alice" {
+			t.Errorf("unexpected users: %+v", cfg.Users)
+		}
+	})
+
+	t.Run("non-200 response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "not found", http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		resetGlobalConfig()
+		err := InitConfigFromURL[TestConfig]("remoteapp", server.URL+"/missing.toml")
+		if err == nil {
+			t.Fatal("expected an error for a 404 response, but got nil")
+		}
+		if !strings.Contains(err.Error(), "unexpected status 404") {
+			t.Errorf("expected a status error, got '%v'", err)
+		}
+		if _, err := GetConfigSafe[TestConfig](); err == nil {
+			t.Error("expected config to stay uninitialized after a failed fetch")
+		}
+	})
+}
+
+// TestDetectURLFormat은 Content-Type과 URL 확장자로 문서 형식을 판별하는지 테스트합니다
+func TestDetectURLFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		url         string
+		expected    string
+	}{
+		{"application/json; charset=utf-8", "https://example.com/config", "json"},
+		{"application/toml", "https://example.com/config.json", "toml"},
+		{"text/plain", "https://example.com/config.json?v=2", "json"},
+		{"", "https://example.com/config.toml", "toml"},
+		{"", "https://example.com/config", "toml"},
+	}
+
+	for _, tt := range tests {
+		if got := detectURLFormat(tt.contentType, tt.url); got != tt.expected {
+			t.Errorf("detectURLFormat(%q, %q) = %q, expected %q", tt.contentType, tt.url, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Report collects the diagnostics of a load, giving a one-shot view of the
+// health of the configuration at startup.
+type Report struct {
+	Warnings        []error  // Validation problems tolerated in Warn mode
+	ParseErrors     []error  // Values skipped in SkipAndCount mode
+	Deprecated      []string // Deprecated fields that are set, e.g. "server.host is deprecated: use server.addr"
+	UnknownEnv      []string // Environment variables with the app prefix that match no field, sorted
+	InsecureSources []error  // Secret fields whose value is written in the config file
+}
+
+// LoadWithReport loads the configuration like InitConfigSafe and returns it
+// together with a report of its diagnostics: validation warnings, skipped
+// values, deprecated fields in use (deprecated tag), environment variables
+// with the app prefix that match no field (usually typos) and secrets written
+// in the config file. The configuration is stored as the shared instance.
+//
+// Example:
+//
+//	cfg, report, err := ahatconfig.LoadWithReport[MyConfig]("myapp")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, name := range report.UnknownEnv {
+//	    log.Printf("unknown config variable %s", name)
+//	}
+func LoadWithReport[T any](appname string) (*T, Report, error) {
+	AppName = appname
+
+	cfg, report, err := buildConfig(loadFileBase[T])
+	if err != nil {
+		return nil, Report{}, err
+	}
+	storeInstance(cfg, report, configSource{prefix: AppName, loadBase: loadFileBase[T]})
+
+	return cfg, Report{
+		Warnings:        report.warnings,
+		ParseErrors:     report.parseErrors,
+		Deprecated:      report.deprecated,
+		UnknownEnv:      unknownEnvVars(reflect.TypeOf(cfg).Elem(), appname, report.envNames),
+		InsecureSources: report.insecureSources,
+	}, nil
+}
+
+// deprecatedFields returns a message for every field of the struct v tagged
+// deprecated that holds a value other than its zero value or default.
+func deprecatedFields(v reflect.Value, path string) []string {
+	var messages []string
+	for i, fieldInfo := range getCachedTypeInfo(v.Type()).Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch {
+		case isNestedStruct(fieldInfo.Type):
+			messages = append(messages, deprecatedFields(value, fieldPath)...)
+			continue
+		case isStructList(fieldInfo.Type):
+			for j := 0; j < value.Len(); j++ {
+				if elem := derefElem(value.Index(j)); elem.IsValid() {
+					messages = append(messages, deprecatedFields(elem, indexPath(fieldPath, j))...)
+				}
+			}
+			continue
+		}
+
+		if fieldInfo.Deprecated == "" || isZero(value) {
+			continue
+		}
+		if fieldInfo.DefaultValue != "" {
+			if def, err := parseEnvValue(fieldInfo.DefaultValue, fieldInfo.Type); err == nil && reflect.DeepEqual(def, value.Interface()) {
+				continue
+			}
+		}
+		messages = append(messages, fmt.Sprintf("%s is deprecated: %s", fieldPath, fieldInfo.Deprecated))
+	}
+	return messages
+}
+
+// unknownEnvVars returns the sorted names of the environment variables in
+// names, the snapshot of the load, that start with the prefix of appname but
+// are read by no field of type t.
+func unknownEnvVars(t reflect.Type, appname string, names []string) []string {
+	prefix := strings.ReplaceAll(strings.ToUpper(appname), "-", "_") + "_"
+	known := regexp.MustCompile("^(?:" + strings.Join(envKeyPatterns(t, appname, map[reflect.Type]bool{}, []string{regexp.QuoteMeta(prefix + "CONFIG_TYPE"), regexp.QuoteMeta(prefix + "CONFIG_UNMASK"), regexp.QuoteMeta(prefix + "CONFIG_FILE")}), "|") + ")$")
+
+	var unknown []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) && !known.MatchString(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// envKeyPatterns appends regular expressions matching the environment
+// variables read for the fields of struct type t under prefix. Slice indices
+// match any number.
+func envKeyPatterns(t reflect.Type, prefix string, visiting map[reflect.Type]bool, out []string) []string {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		if fieldInfo.EnvAbs != "" {
+			continue
+		}
+		envKey := fieldEnvKey(prefix, fieldInfo)
+		pattern := regexp.QuoteMeta(envKey)
+
+		switch {
+		case isNestedStruct(fieldInfo.Type):
+			if fieldInfo.Inline || fieldInfo.Format != "" {
+				out = append(out, pattern)
+			}
+			if !visiting[fieldInfo.Type] {
+				out = envKeyPatterns(fieldInfo.Type, envKey, visiting, out)
+			}
+		case isStructList(fieldInfo.Type):
+			elemType, _ := structElem(fieldInfo.Type)
+			if !visiting[elemType] {
+				// Element keys are built as {KEY}_{INDEX}, so mark the index with a placeholder
+				first := indexEnvKey(envKey, 0)
+				for _, elem := range envKeyPatterns(elemType, first, visiting, nil) {
+					out = append(out, strings.Replace(elem, regexp.QuoteMeta(first), pattern+indexEnvPattern(), 1))
+				}
+			}
+		case isScalarSlice(fieldInfo.Type):
+			out = append(out, pattern+"(?:"+indexEnvPattern()+")?")
+		default:
+			out = append(out, pattern)
+		}
+	}
+	return out
+}
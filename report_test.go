@@ -0,0 +1,62 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadWithReport는 LoadWithReport가 사용 중인 deprecated 필드, 알 수 없는 환경변수, 파일에 적힌 시크릿을 보고하는지 테스트합니다
+func TestLoadWithReport(t *testing.T) {
+	type ReportConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" deprecated:"use server.addr instead"`
+			Addr string `toml:"addr" env:"ADDR"`
+			Port int    `toml:"port" env:"PORT" default:"8080" deprecated:"use server.addr instead"`
+		} `toml:"server" env:"SERVER"`
+		Database struct {
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		} `toml:"database" env:"DATABASE"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME"`
+		} `toml:"users" env:"USERS"`
+		Hosts []string `toml:"hosts" env:"HOSTS"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "reportapp", `[database]
+password = "hunter2"
+`)
+	defer cleanup()
+	t.Setenv("REPORTAPP_SERVER_HOST", "legacy.example.com")
+	t.Setenv("REPORTAPP_SERVER_ADDR", "example.com:80")
+	t.Setenv("REPORTAPP_SERVR_PORT", "9090")
+	t.Setenv("REPORTAPP_USERS_0_NAME", "alice")
+	t.Setenv("REPORTAPP_USERS_1_NAME", "bob")
+	t.Setenv("REPORTAPP_USERS_0_ROLE", "admin")
+	t.Setenv("REPORTAPP_HOSTS_0", "a")
+	t.Setenv("REPORTAPP_CONFIG_TYPE", "toml")
+
+	cfg, report, err := LoadWithReport[ReportConfig]("reportapp")
+	if err != nil {
+		t.Fatalf("LoadWithReport failed: %v", err)
+	}
+	if cfg != GetConfig[ReportConfig]() {
+		t.Error("expected the config to be stored as the shared instance")
+	}
+
+	// server.port only holds its default, so it is not reported
+	if !reflect.DeepEqual(report.Deprecated, []string{"server.host is deprecated: use server.addr instead"}) {
+		t.Errorf("unexpected deprecated fields: %v", report.Deprecated)
+	}
+	if !reflect.DeepEqual(report.UnknownEnv, []string{"REPORTAPP_SERVR_PORT", "REPORTAPP_USERS_0_ROLE"}) {
+		t.Errorf("unexpected unknown env vars: %v", report.UnknownEnv)
+	}
+	if len(report.InsecureSources) != 1 || !strings.Contains(report.InsecureSources[0].Error(), "database.password") {
+		t.Errorf("expected the file secret to be reported, got %v", report.InsecureSources)
+	}
+	if report.Warnings != nil || report.ParseErrors != nil {
+		t.Errorf("expected no warnings or parse errors, got %v and %v", report.Warnings, report.ParseErrors)
+	}
+}
@@ -0,0 +1,199 @@
+package ahatconfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// jsonSchemaDraft07 is the meta-schema URI emitted by JSONSchema.
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema generates a draft-07 JSON Schema describing the config type T.
+// Property names are the TOML keys, required fields come from the required
+// tag, enums from the oneof tag (e.g. oneof:"debug info warn") and bounds
+// from the min and max tags. Defaults are included when they can be parsed,
+// except for secret fields, which are marked writeOnly instead so that the
+// schema can be shared without exposing them. The schema validates TOML or
+// JSON config files in CI and editors.
+//
+// Example:
+//
+//	schema, err := ahatconfig.JSONSchema[MyConfig]()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("myapp.schema.json", schema, 0644)
+func JSONSchema[T any]() ([]byte, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	schema := typeSchema(t, "", map[reflect.Type]bool{})
+	schema["$schema"] = jsonSchemaDraft07
+	if t.Name() != "" {
+		schema["title"] = t.Name()
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// typeSchema returns the JSON Schema for a Go type at the dotted path, in
+// which slice elements appear as "users[]" and map values as "backends.*".
+// visiting holds the struct types being described further up, so
+// self-referential types terminate.
+func typeSchema(t reflect.Type, path string, visiting map[reflect.Type]bool) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if isByteSlice(t) {
+		// []byte fields are written as strings
+		return map[string]interface{}{"type": "string"}
+	}
+	if t == durationType {
+		// Durations are written as strings such as "30s"
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float64, reflect.Float32:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem(), path+"[]", visiting),
+		}
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    typeSchema(t.Elem(), path+"[]", visiting),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem(), joinPath(path, "*"), visiting),
+		}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		if visiting[t] {
+			// A recursive occurrence is described as a plain object
+			return map[string]interface{}{"type": "object"}
+		}
+		return structSchema(t, path, visiting)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the object schema for a struct type, including its
+// required fields and the constraints declared in field tags.
+func structSchema(t reflect.Type, path string, visiting map[reflect.Type]bool) map[string]interface{} {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		fieldPath := joinPath(path, fieldInfo.Key)
+		prop := typeSchema(fieldInfo.Type, fieldPath, visiting)
+		addFieldConstraints(prop, fieldInfo, fieldPath)
+		properties[fieldInfo.Key] = prop
+
+		if fieldInfo.Required {
+			required = append(required, fieldInfo.Key)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFieldConstraints adds the description, default, enum and bound keywords
+// declared by the tags of the field at path to its property schema. The
+// default of a secret field is left out.
+func addFieldConstraints(prop map[string]interface{}, fieldInfo FieldInfo, path string) {
+	if fieldInfo.Desc != "" {
+		prop["description"] = fieldInfo.Desc
+	}
+
+	parse := func(s string) (interface{}, error) {
+		return parseEnvValue(s, fieldInfo.Type)
+	}
+	if fieldInfo.Base > 0 {
+		// Strings hold integers in the base of the base tag
+		prop["type"] = []interface{}{"integer", "string"}
+		parse = func(s string) (interface{}, error) {
+			return parseBaseField(s, fieldInfo, fieldInfo.Key)
+		}
+	}
+	if fieldInfo.Type == durationType {
+		// Durations keep the tag strings as written, e.g. "30s"
+		parse = func(s string) (interface{}, error) {
+			if _, err := time.ParseDuration(s); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+	}
+
+	if isSecretField(fieldInfo, path) {
+		prop["writeOnly"] = true
+	} else if fieldInfo.DefaultValue != "" && !fieldInfo.DefaultRefs {
+		if value, err := parse(fieldInfo.DefaultValue); err == nil {
+			prop["default"] = value
+		}
+	}
+
+	if len(fieldInfo.OneOf) > 0 {
+		enum := make([]interface{}, 0, len(fieldInfo.OneOf))
+		for _, option := range fieldInfo.OneOf {
+			if value, err := parse(option); err == nil {
+				enum = append(enum, value)
+			}
+		}
+		prop["enum"] = enum
+	}
+
+	minKeyword, maxKeyword := "minimum", "maximum"
+	switch {
+	case fieldInfo.Type == durationType:
+		// min and max do not apply to durations
+		return
+	case isNumberList(fieldInfo.Type):
+		// Bounds of numeric lists apply to every element
+		if items, ok := prop["items"].(map[string]interface{}); ok {
+			prop = items
+		}
+	case prop["type"] == "string":
+		minKeyword, maxKeyword = "minLength", "maxLength"
+	case prop["type"] == "array":
+		minKeyword, maxKeyword = "minItems", "maxItems"
+	case prop["type"] == "object":
+		minKeyword, maxKeyword = "minProperties", "maxProperties"
+	}
+
+	if bound, err := strconv.ParseFloat(fieldInfo.Min, 64); err == nil {
+		prop[minKeyword] = bound
+	}
+	if bound, err := strconv.ParseFloat(fieldInfo.Max, 64); err == nil {
+		prop[maxKeyword] = bound
+	}
+}
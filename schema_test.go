@@ -0,0 +1,148 @@
+package ahatconfig
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestJSONSchema는 생성된 스키마가 필수 필드, enum, 범위 제약을 포함하는지 테스트합니다
+func TestJSONSchema(t *testing.T) {
+	type SchemaConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT" default:"8080" min:"1" max:"65535"`
+		} `toml:"server" env:"SERVER"`
+		Log struct {
+			Level string `toml:"level" env:"LEVEL" oneof:"debug info warn error" default:"info"`
+		} `toml:"log" env:"LOG"`
+		Hosts []string `toml:"hosts" env:"HOSTS"`
+	}
+
+	data, err := JSONSchema[SchemaConfig]()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	if schema["$schema"] != "http://json-schema.org/draft-07/schema#" {
+		t.Errorf("expected draft-07 schema, got %v", schema["$schema"])
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	server := properties["server"].(map[string]interface{})
+	serverProps := server["properties"].(map[string]interface{})
+
+	if !reflect.DeepEqual(server["required"], []interface{}{"host"}) {
+		t.Errorf("expected server.host to be required, got %v", server["required"])
+	}
+
+	port := serverProps["port"].(map[string]interface{})
+	if port["type"] != "integer" {
+		t.Errorf("expected port type to be integer, got %v", port["type"])
+	}
+	if port["minimum"] != 1.0 || port["maximum"] != 65535.0 {
+		t.Errorf("expected port bounds 1..65535, got %v..%v", port["minimum"], port["maximum"])
+	}
+	if port["default"] != 8080.0 {
+		t.Errorf("expected port default 8080, got %v", port["default"])
+	}
+
+	level := properties["log"].(map[string]interface{})["properties"].(map[string]interface{})["level"].(map[string]interface{})
+	expectedEnum := []interface{}{"debug", "info", "warn", "error"}
+	if !reflect.DeepEqual(level["enum"], expectedEnum) {
+		t.Errorf("expected level enum %v, got %v", expectedEnum, level["enum"])
+	}
+
+	hosts := properties["hosts"].(map[string]interface{})
+	if hosts["type"] != "array" || hosts["items"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("expected hosts to be an array of strings, got %v", hosts)
+	}
+
+	if _, ok := schema["required"]; ok {
+		t.Errorf("expected no required top-level properties, got %v", schema["required"])
+	}
+}
+
+// TestJSONSchemaMasksSecrets는 시크릿 필드의 기본값이 스키마와 Describe 출력에 노출되지 않는지 테스트합니다
+func TestJSONSchemaMasksSecrets(t *testing.T) {
+	type SecretSchemaConfig struct {
+		Database struct {
+			Password string `toml:"password" secret:"true" default:"changeme"`
+			Host     string `toml:"host" default:"localhost"`
+		} `toml:"database"`
+		Users []struct {
+			APIToken string `toml:"api_token" default:"dev-token"`
+		} `toml:"users"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	SetSecretPredicate(func(fieldPath, fieldName string) bool {
+		return fieldName == "APIToken"
+	})
+
+	data, err := JSONSchema[SecretSchemaConfig]()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	if strings.Contains(string(data), "changeme") || strings.Contains(string(data), "dev-token") {
+		t.Errorf("expected secret defaults to be left out of the schema:\n%s", data)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema JSON: %v", err)
+	}
+	database := schema["properties"].(map[string]interface{})["database"].(map[string]interface{})["properties"].(map[string]interface{})
+	password := database["password"].(map[string]interface{})
+	if password["writeOnly"] != true {
+		t.Errorf("expected the secret field to be writeOnly, got %v", password)
+	}
+	if host := database["host"].(map[string]interface{}); host["default"] != "localhost" {
+		t.Errorf("expected the default of a plain field to be kept, got %v", host)
+	}
+
+	for _, f := range Describe[SecretSchemaConfig]() {
+		if f.Secret && f.Default != "****" {
+			t.Errorf("expected the default of %s to be masked in Describe, got %q", f.Path, f.Default)
+		}
+	}
+}
+
+// TestJSONSchemaDuration은 time.Duration 필드가 문자열로 기술되고 기본값과 enum이 태그 문자열 그대로 유지되는지 테스트합니다
+func TestJSONSchemaDuration(t *testing.T) {
+	type DurationSchemaConfig struct {
+		Timeout  time.Duration   `toml:"timeout" default:"30s" oneof:"10s 30s 1m"`
+		Backoffs []time.Duration `toml:"backoffs"`
+	}
+
+	data, err := JSONSchema[DurationSchemaConfig]()
+	if err != nil {
+		t.Fatalf("JSONSchema failed: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("invalid schema JSON: %v", err)
+	}
+	properties := schema["properties"].(map[string]interface{})
+
+	timeout := properties["timeout"].(map[string]interface{})
+	expected := map[string]interface{}{
+		"type":    "string",
+		"default": "30s",
+		"enum":    []interface{}{"10s", "30s", "1m"},
+	}
+	if !reflect.DeepEqual(timeout, expected) {
+		t.Errorf("expected timeout schema %v, got %v", expected, timeout)
+	}
+	if items := properties["backoffs"].(map[string]interface{})["items"]; !reflect.DeepEqual(items, map[string]interface{}{"type": "string"}) {
+		t.Errorf("expected backoffs to be an array of strings, got %v", items)
+	}
+}
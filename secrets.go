@@ -0,0 +1,181 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SecretProvider resolves a secret reference, such as
+// "vault://secret/prod/db#password", to the secret value.
+type SecretProvider func(ref string) (string, error)
+
+var (
+	// secretProviders holds the registered providers by URI scheme.
+	secretProviders = map[string]SecretProvider{}
+	// envSecretProviders holds the providers registered for a single
+	// environment, by environment and URI scheme.
+	envSecretProviders = map[string]map[string]SecretProvider{}
+)
+
+// RegisterSecretProvider registers provider for references with the given URI
+// scheme (e.g. "vault"). After all sources are loaded, a secret field whose
+// value starts with "{scheme}://" is replaced by the value the provider
+// returns for it. Placeholders in the reference, written ${VAR} or {VAR}, are
+// expanded from the environment first, and APP_ENV stands for the active
+// environment (see Environment), so "vault://secret/{APP_ENV}/db#password"
+// reads the secret of the active environment. Pass a nil provider to remove a
+// scheme.
+//
+// Example:
+//
+//	ahatconfig.RegisterSecretProvider("vault", func(ref string) (string, error) {
+//	    return vaultClient.Read(strings.TrimPrefix(ref, "vault://"))
+//	})
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	if provider == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = provider
+}
+
+// RegisterSecretProviderFor registers provider for references with the given
+// URI scheme in the environment env only. While env is the active environment
+// (see Environment, compared case-insensitively), it takes precedence over a
+// provider registered for the scheme with RegisterSecretProvider, so the same
+// reference can be read from a local file in development and from a secret
+// store in production without code changes. Pass a nil provider to remove it.
+//
+// Example:
+//
+//	ahatconfig.RegisterSecretProviderFor("dev", "secret", readLocalSecretFile)
+//	ahatconfig.RegisterSecretProviderFor("prod", "secret", readVault)
+//	ahatconfig.SetEnvironment(os.Getenv("APP_ENV"))
+func RegisterSecretProviderFor(env, scheme string, provider SecretProvider) {
+	env = strings.ToLower(env)
+	if provider == nil {
+		delete(envSecretProviders[env], scheme)
+		return
+	}
+	if envSecretProviders[env] == nil {
+		envSecretProviders[env] = map[string]SecretProvider{}
+	}
+	envSecretProviders[env][scheme] = provider
+}
+
+// secretProvider returns the provider for scheme in the active environment.
+func secretProvider(scheme string) (SecretProvider, bool) {
+	if provider, ok := envSecretProviders[strings.ToLower(Environment())][scheme]; ok {
+		return provider, true
+	}
+	provider, ok := secretProviders[scheme]
+	return provider, ok
+}
+
+// resolveSecrets replaces the secret references in the secret fields of v by
+// their values, walking nested structs and struct slices. path is the dotted
+// path of v.
+func resolveSecrets(v reflect.Value, path string) error {
+	if len(secretProviders) == 0 && len(envSecretProviders) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i, fieldInfo := range getCachedTypeInfo(v.Type()).Fields {
+		value := v.Field(i)
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		if isNestedStruct(value.Type()) {
+			if err := resolveSecrets(value, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if isStructList(fieldInfo.Type) {
+			for j := 0; j < value.Len(); j++ {
+				if err := resolveSecrets(value.Index(j), indexPath(fieldPath, j)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if value.Kind() != reflect.String || !isSecretField(fieldInfo, fieldPath) {
+			continue
+		}
+		secret, ok, err := resolveSecretRef(value.String())
+		if err != nil {
+			return fmt.Errorf("failed to resolve secret for field %s: %w", fieldPath, err)
+		}
+		if ok {
+			value.SetString(secret)
+		}
+	}
+	return nil
+}
+
+// isSecretRef reports whether s is a reference to a registered secret
+// provider.
+func isSecretRef(s string) bool {
+	scheme, _, found := strings.Cut(s, "://")
+	_, ok := secretProvider(scheme)
+	return found && ok
+}
+
+// resolveSecretRef resolves ref with the provider registered for its scheme.
+// It reports false when ref is not a reference to a registered scheme.
+func resolveSecretRef(ref string) (string, bool, error) {
+	if !isSecretRef(ref) {
+		return "", false, nil
+	}
+	scheme, _, _ := strings.Cut(ref, "://")
+	provider, _ := secretProvider(scheme)
+
+	expanded, err := expandSecretRef(ref)
+	if err != nil {
+		return "", true, err
+	}
+	secret, err := provider(expanded)
+	if err != nil {
+		return "", true, fmt.Errorf("%s: %w", expanded, err)
+	}
+	return secret, true, nil
+}
+
+// expandSecretRef expands the ${VAR} and {VAR} placeholders of a secret
+// reference from the environment. APP_ENV expands to the active
+// environment, so it honors SetEnvironment. An unset variable is an error, as
+// the reference would otherwise silently point at another secret.
+func expandSecretRef(ref string) (string, error) {
+	var missing string
+	expand := func(name string) string {
+		value, ok := buildLookup(name)
+		if name == "APP_ENV" {
+			value = Environment()
+			ok = value != ""
+		}
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	}
+
+	ref = envRefPattern.ReplaceAllStringFunc(ref, func(match string) string {
+		return expand(envRefPattern.FindStringSubmatch(match)[1])
+	})
+	// {VAR} uses the same syntax as default tag references
+	ref = defaultRefPattern.ReplaceAllStringFunc(ref, func(match string) string {
+		return expand(defaultRefPattern.FindStringSubmatch(match)[1])
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("secret reference uses unset variable %s", missing)
+	}
+	return ref, nil
+}
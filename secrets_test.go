@@ -0,0 +1,188 @@
+package ahatconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSecretProviderEnvPlaceholders는 시크릿 참조 안의 환경변수 플레이스홀더가 확장된 뒤 프로바이더로 조회되는지 테스트합니다
+func TestSecretProviderEnvPlaceholders(t *testing.T) {
+	type ProviderConfig struct {
+		Database struct {
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+			APIKey   string `toml:"api_key" env:"API_KEY" secret:"true"`
+			URL      string `toml:"url" env:"URL"`
+		} `toml:"database" env:"DATABASE"`
+	}
+
+	vault := map[string]string{
+		"vault://secret/prod/db#password": "prod-password",
+		"vault://secret/dev/db#password":  "dev-password",
+		"vault://secret/prod/api#key":     "prod-key",
+	}
+	var requested []string
+	provider := func(ref string) (string, error) {
+		requested = append(requested, ref)
+		if secret, ok := vault[ref]; ok {
+			return secret, nil
+		}
+		return "", errors.New("secret not found")
+	}
+
+	t.Run("placeholders are expanded before resolution", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		requested = nil
+		_, cleanup := createTestTomlFile(t, "providerapp", `[database]
+password = "vault://secret/{APP_ENV}/db#password"
+url = "vault://not-a-secret-field"
+`)
+		defer cleanup()
+		AppName = "providerapp"
+		RegisterSecretProvider("vault", provider)
+		t.Setenv("APP_ENV", "prod")
+		t.Setenv("PROVIDERAPP_DATABASE_API_KEY", "vault://secret/${APP_ENV}/api#key")
+
+		if err := LoadConfig[ProviderConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[ProviderConfig]()
+		if cfg.Database.Password != "prod-password" {
+			t.Errorf("expected the prod password, got '%s'", cfg.Database.Password)
+		}
+		if cfg.Database.APIKey != "prod-key" {
+			t.Errorf("expected the prod api key, got '%s'", cfg.Database.APIKey)
+		}
+		if cfg.Database.URL != "vault://not-a-secret-field" {
+			t.Errorf("expected non-secret fields to be left alone, got '%s'", cfg.Database.URL)
+		}
+		if len(requested) != 2 {
+			t.Errorf("expected 2 provider calls, got %v", requested)
+		}
+	})
+
+	t.Run("unset placeholder fails", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "providerapp"
+		RegisterSecretProvider("vault", provider)
+		t.Setenv("PROVIDERAPP_DATABASE_PASSWORD", "vault://secret/{MISSING_ENV}/db#password")
+
+		err := LoadConfig[ProviderConfig]()
+		if err == nil || !strings.Contains(err.Error(), "database.password") || !strings.Contains(err.Error(), "unset variable MISSING_ENV") {
+			t.Errorf("expected an unset variable error for database.password, got %v", err)
+		}
+	})
+
+	t.Run("provider errors fail the load", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "providerapp"
+		RegisterSecretProvider("vault", provider)
+		t.Setenv("APP_ENV", "staging")
+		t.Setenv("PROVIDERAPP_DATABASE_PASSWORD", "vault://secret/{APP_ENV}/db#password")
+
+		err := LoadConfig[ProviderConfig]()
+		if err == nil || !strings.Contains(err.Error(), "vault://secret/staging/db#password: secret not found") {
+			t.Errorf("expected a provider error naming the expanded reference, got %v", err)
+		}
+	})
+
+	t.Run("APP_ENV follows SetEnvironment", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		requested = nil
+		AppName = "providerapp"
+		RegisterSecretProvider("vault", provider)
+		t.Setenv("APP_ENV", "staging")
+		SetEnvironment("prod")
+		t.Setenv("PROVIDERAPP_DATABASE_PASSWORD", "vault://secret/{APP_ENV}/db#password")
+
+		if err := LoadConfig[ProviderConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := GetConfig[ProviderConfig]().Database.Password; got != "prod-password" {
+			t.Errorf("expected the password of the environment set with SetEnvironment, got '%s'", got)
+		}
+	})
+}
+
+// TestSecretProviderPerEnvironment은 같은 시크릿 참조가 활성 환경에 따라 dev에서는 파일 프로바이더, prod에서는 원격 프로바이더로 조회되는지 테스트합니다
+func TestSecretProviderPerEnvironment(t *testing.T) {
+	type EnvProviderConfig struct {
+		Password string `toml:"password" env:"PASSWORD" secret:"true"`
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db_password"), []byte("dev-password\n"), 0600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+	fileProvider := func(ref string) (string, error) {
+		data, err := os.ReadFile(filepath.Join(dir, strings.TrimPrefix(ref, "secret://")))
+		return strings.TrimSpace(string(data)), err
+	}
+	remote := map[string]string{"secret://db_password": "prod-password"}
+	remoteProvider := func(ref string) (string, error) {
+		if secret, ok := remote[ref]; ok {
+			return secret, nil
+		}
+		return "", errors.New("secret not found")
+	}
+
+	load := func(t *testing.T, env string) (*EnvProviderConfig, error) {
+		t.Helper()
+		resetGlobalConfig()
+		t.Cleanup(resetGlobalConfig)
+		AppName = "envproviderapp"
+		t.Setenv("ENVPROVIDERAPP_PASSWORD", "secret://db_password")
+		RegisterSecretProviderFor("dev", "secret", fileProvider)
+		RegisterSecretProviderFor("PROD", "secret", remoteProvider)
+		SetEnvironment(env)
+		if err := LoadConfig[EnvProviderConfig](); err != nil {
+			return nil, err
+		}
+		return GetConfig[EnvProviderConfig](), nil
+	}
+
+	for env, want := range map[string]string{"dev": "dev-password", "prod": "prod-password"} {
+		t.Run(env, func(t *testing.T) {
+			cfg, err := load(t, env)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if cfg.Password != want {
+				t.Errorf("expected %q, got %q", want, cfg.Password)
+			}
+		})
+	}
+
+	t.Run("other environment falls back to the global provider", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "envproviderapp"
+		t.Setenv("ENVPROVIDERAPP_PASSWORD", "secret://db_password")
+		RegisterSecretProvider("secret", func(string) (string, error) { return "global-password", nil })
+		RegisterSecretProviderFor("prod", "secret", remoteProvider)
+		SetEnvironment("staging")
+
+		if err := LoadConfig[EnvProviderConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := GetConfig[EnvProviderConfig]().Password; got != "global-password" {
+			t.Errorf("expected the global provider outside prod, got %q", got)
+		}
+	})
+
+	t.Run("unregistered environment keeps the reference", func(t *testing.T) {
+		cfg, err := load(t, "qa")
+		if err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg.Password != "secret://db_password" {
+			t.Errorf("expected the reference to stay unresolved without a provider, got %q", cfg.Password)
+		}
+	})
+}
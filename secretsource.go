@@ -0,0 +1,108 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pelletier/go-toml"
+)
+
+// SecretSourcePolicy controls where secret fields may get their values from.
+type SecretSourcePolicy int
+
+const (
+	// AllowFile accepts secret values from any source (the default).
+	AllowFile SecretSourcePolicy = iota
+	// DisallowFile reports a secret field whose value is written in the
+	// config file or a fetched config document as a validation problem, so
+	// the load fails in Strict mode and records a warning in Warn mode.
+	// Values that only reference environment variables, such as
+	// password = "${DB_PASSWORD}", and references to registered secret
+	// providers are allowed.
+	DisallowFile
+)
+
+var (
+	secretSourcePolicy = AllowFile
+	// secretSourceErrors collects the secret fields set in the documents of
+	// the load in progress; they are policy violations under DisallowFile
+	secretSourceErrors []error
+)
+
+// SetSecretSourcePolicy sets where secret fields may get their values from in
+// subsequent loads. Use DisallowFile to catch secrets committed to a config
+// file; secrets then have to come from environment variables or a config
+// directory such as a mounted Kubernetes Secret.
+//
+// Example:
+//
+//	ahatconfig.SetSecretSourcePolicy(ahatconfig.DisallowFile)
+func SetSecretSourcePolicy(policy SecretSourcePolicy) {
+	secretSourcePolicy = policy
+}
+
+// checkSecretSources records every secret field of type t that has a
+// plaintext value in tree. path is the dotted path of t.
+func checkSecretSources(tree *toml.Tree, t reflect.Type, path string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		raw := tree.GetPath([]string{fieldInfo.Key})
+		if raw == nil {
+			continue
+		}
+		fieldPath := joinPath(path, fieldInfo.Key)
+
+		switch value := raw.(type) {
+		case *toml.Tree:
+			checkSecretSources(value, fieldInfo.Type, fieldPath)
+			continue
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for i, elem := range value {
+					checkSecretSources(elem, elemType, indexPath(fieldPath, i))
+				}
+				continue
+			}
+		}
+
+		if isSecretField(fieldInfo, fieldPath) && hasPlaintextValue(raw) {
+			secretSourceErrors = append(secretSourceErrors, fmt.Errorf("secret field %s is set in the config file; provide it through the environment instead", fieldPath))
+		}
+	}
+}
+
+// structElemType returns the struct element type of a slice or array of
+// structs or struct pointers.
+func structElemType(t reflect.Type) (reflect.Type, bool) {
+	if !isStructList(t) {
+		return nil, false
+	}
+	return structElem(t)
+}
+
+// hasPlaintextValue reports whether a document value holds anything besides
+// ${VAR} environment references or secret provider references.
+func hasPlaintextValue(raw interface{}) bool {
+	switch value := raw.(type) {
+	case string:
+		if isSecretRef(value) {
+			return false
+		}
+		return envRefPattern.ReplaceAllString(value, "") != ""
+	case []interface{}:
+		for _, elem := range value {
+			if hasPlaintextValue(elem) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,117 @@
+package ahatconfig
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSecretSourcePolicy는 DisallowFile 정책에서 설정 파일에 적힌 시크릿 값이 에러가 되는지 테스트합니다
+func TestSecretSourcePolicy(t *testing.T) {
+	type SecretSourceConfig struct {
+		Database struct {
+			User     string `toml:"user" env:"USER"`
+			Password string `toml:"password" env:"PASSWORD" secret:"true"`
+		} `toml:"database" env:"DATABASE"`
+		Tokens []struct {
+			Value string `toml:"value" env:"VALUE" secret:"true"`
+		} `toml:"tokens" env:"TOKENS"`
+	}
+
+	load := func(t *testing.T, content string) error {
+		t.Helper()
+		resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "secretsrcapp", content)
+		t.Cleanup(cleanup)
+		t.Cleanup(resetGlobalConfig)
+		AppName = "secretsrcapp"
+		SetSecretSourcePolicy(DisallowFile)
+		return LoadConfig[SecretSourceConfig]()
+	}
+
+	t.Run("secret in file fails", func(t *testing.T) {
+		err := load(t, "[database]\nuser = \"admin\"\npassword = \"hunter2\"\n")
+		expected := "secret field database.password is set in the config file"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing '%s', got %v", expected, err)
+		}
+		if err != nil && strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("error must not contain the secret value: %v", err)
+		}
+	})
+
+	t.Run("secret under another spelling of its key fails", func(t *testing.T) {
+		type CaseConfig struct {
+			Database struct {
+				Password string `secret:"true"`
+				Token    string `toml:"token" secret:"true"`
+			}
+		}
+
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "secretcaseapp", "[Database]\nPassword = \"hunter2\"\nTOKEN = \"tok\"\n")
+		defer cleanup()
+		AppName = "secretcaseapp"
+		SetSecretSourcePolicy(DisallowFile)
+
+		err := LoadConfig[CaseConfig]()
+		for _, expected := range []string{"secret field database.password", "secret field database.token"} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error containing '%s', got %v", expected, err)
+			}
+		}
+	})
+
+	t.Run("secret in table array fails", func(t *testing.T) {
+		err := load(t, "[[tokens]]\nvalue = \"${TOKEN_A}\"\n\n[[tokens]]\nvalue = \"plain\"\n")
+		if err == nil || !strings.Contains(err.Error(), "secret field tokens[1].value") {
+			t.Errorf("expected an error for tokens[1].value, got %v", err)
+		}
+	})
+
+	t.Run("secret provider references are allowed", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "secretsrcapp", "[database]\npassword = \"vault://secret/db#password\"\n")
+		defer cleanup()
+		AppName = "secretsrcapp"
+		SetSecretSourcePolicy(DisallowFile)
+		RegisterSecretProvider("vault", func(ref string) (string, error) { return "fromvault", nil })
+
+		if err := LoadConfig[SecretSourceConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := GetConfig[SecretSourceConfig]().Database.Password; got != "fromvault" {
+			t.Errorf("expected the password from the provider, got '%s'", got)
+		}
+	})
+
+	t.Run("env references and env values are allowed", func(t *testing.T) {
+		t.Setenv("DB_PASSWORD", "fromref")
+		t.Setenv("SECRETSRCAPP_TOKENS_0_VALUE", "fromenv")
+		if err := load(t, "[database]\nuser = \"admin\"\npassword = \"${DB_PASSWORD}\"\n"); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[SecretSourceConfig]()
+		if cfg.Database.Password != "fromref" || cfg.Tokens[0].Value != "fromenv" {
+			t.Errorf("unexpected secret values: %+v", cfg)
+		}
+	})
+
+	t.Run("warn mode records a warning", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "secretsrcapp", "[database]\npassword = \"hunter2\"\n")
+		defer cleanup()
+		AppName = "secretsrcapp"
+		SetSecretSourcePolicy(DisallowFile)
+		SetValidationMode(Warn)
+
+		if err := LoadConfig[SecretSourceConfig](); err != nil {
+			t.Fatalf("expected LoadConfig to succeed in Warn mode, got %v", err)
+		}
+		if got := Warnings(); len(got) != 1 || !strings.Contains(got[0].Error(), "database.password") {
+			t.Errorf("expected one warning for database.password, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package ahatconfig
+
+import (
+	"strconv"
+)
+
+// SliceIndexStyle controls how the index of a slice element is written in
+// environment variable names.
+type SliceIndexStyle int
+
+const (
+	// UnderscoreIndex writes the index as _{i}, as in USERS_0_NAME and
+	// HOSTS_0 (the default).
+	UnderscoreIndex SliceIndexStyle = iota
+	// BracketIndex writes the index as [{i}], as in USERS[0]_NAME and
+	// HOSTS[0], so that it cannot be confused with an env tag ending in a
+	// digit.
+	BracketIndex
+)
+
+var sliceIndexStyle = UnderscoreIndex
+
+// SetSliceIndexStyle sets how slice indices are written in the names of the
+// environment variables read by subsequent loads. With the default
+// UnderscoreIndex, ADDR_1 is both element 1 of a field tagged env:"ADDR" and
+// a field tagged env:"ADDR_1"; BracketIndex reads the element from ADDR[1]
+// instead.
+//
+// Example:
+//
+//	ahatconfig.SetSliceIndexStyle(ahatconfig.BracketIndex)
+//	// MYAPP_USERS[0]_NAME now sets users[0].name
+func SetSliceIndexStyle(style SliceIndexStyle) {
+	sliceIndexStyle = style
+}
+
+// indexEnvKey returns the environment variable name of element i of the
+// slice whose variable is key, e.g. USERS_0 or USERS[0].
+func indexEnvKey(key string, i int) string {
+	return indexedEnvName(key, strconv.Itoa(i))
+}
+
+// indexedEnvName returns key with the index segment for index, which may be
+// a placeholder such as "{INDEX}".
+func indexedEnvName(key, index string) string {
+	if sliceIndexStyle == BracketIndex {
+		return key + "[" + index + "]"
+	}
+	return key + "_" + index
+}
+
+// indexEnvPattern returns a regular expression matching the index segment
+// written by indexEnvKey for any index.
+func indexEnvPattern() string {
+	if sliceIndexStyle == BracketIndex {
+		return `\[\d+\]`
+	}
+	return `_\d+`
+}
@@ -0,0 +1,72 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSliceIndexStyle는 BracketIndex 스타일에서 숫자로 끝나는 env 태그가 슬라이스 인덱스와 충돌하지 않는지 테스트합니다
+func TestSliceIndexStyle(t *testing.T) {
+	type IndexedBackend struct {
+		Name string `toml:"name" env:"NAME"`
+	}
+	type IndexStyleConfig struct {
+		Backends []IndexedBackend `toml:"backends" env:"BACKEND"`
+		Primary  IndexedBackend   `toml:"primary" env:"BACKEND_0"`
+		Addrs    []string         `toml:"addrs" env:"ADDR"`
+		Addr1    string           `toml:"addr1" env:"ADDR_1"`
+	}
+
+	t.Setenv("SLICEINDEX_BACKEND_0_NAME", "primary")
+	t.Setenv("SLICEINDEX_BACKEND[0]_NAME", "alpha")
+	t.Setenv("SLICEINDEX_BACKEND[1]_NAME", "beta")
+	t.Setenv("SLICEINDEX_ADDR[0]", "a.example.com")
+	t.Setenv("SLICEINDEX_ADDR[1]", "b.example.com")
+	t.Setenv("SLICEINDEX_ADDR_1", "single.example.com")
+
+	t.Run("underscore", func(t *testing.T) {
+		resetGlobalConfig()
+		if err := InitConfigSafe[IndexStyleConfig]("sliceindex"); err != nil {
+			t.Fatalf("InitConfigSafe failed: %v", err)
+		}
+		cfg := GetConfig[IndexStyleConfig]()
+
+		// With the default style BACKEND_0_NAME is read as an element too
+		if len(cfg.Backends) != 1 || cfg.Backends[0].Name != "primary" {
+			t.Errorf("expected the colliding variable to be read as element 0, got %+v", cfg.Backends)
+		}
+	})
+
+	t.Run("brackets", func(t *testing.T) {
+		resetGlobalConfig()
+		SetSliceIndexStyle(BracketIndex)
+		cfg, report, err := LoadWithReport[IndexStyleConfig]("sliceindex")
+		if err != nil {
+			t.Fatalf("LoadWithReport failed: %v", err)
+		}
+
+		expectedBackends := []IndexedBackend{{"alpha"}, {"beta"}}
+		if !reflect.DeepEqual(cfg.Backends, expectedBackends) {
+			t.Errorf("expected backends %+v, got %+v", expectedBackends, cfg.Backends)
+		}
+		if cfg.Primary.Name != "primary" {
+			t.Errorf("expected primary from BACKEND_0_NAME, got %q", cfg.Primary.Name)
+		}
+		expectedAddrs := []string{"a.example.com", "b.example.com"}
+		if !reflect.DeepEqual(cfg.Addrs, expectedAddrs) {
+			t.Errorf("expected addrs %v, got %v", expectedAddrs, cfg.Addrs)
+		}
+		if cfg.Addr1 != "single.example.com" {
+			t.Errorf("expected addr1 from ADDR_1, got %q", cfg.Addr1)
+		}
+		if len(report.UnknownEnv) != 0 {
+			t.Errorf("expected bracketed variables to be known, got %v", report.UnknownEnv)
+		}
+
+		for _, f := range Describe[IndexStyleConfig]() {
+			if f.Path == "backends[].name" && f.EnvKey != "SLICEINDEX_BACKEND[{INDEX}]_NAME" {
+				t.Errorf("expected a bracketed env key in Describe, got %s", f.EnvKey)
+			}
+		}
+	})
+}
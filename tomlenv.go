@@ -0,0 +1,89 @@
+package ahatconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// loadFormattedValue sets value from an environment variable holding a
+// document in the format of the field's format tag: "toml" for struct and
+// map fields (e.g. APP_LIMITS='cpu = 2
+// memory = "1Gi"') or "json" for struct, map and slice fields (e.g.
+// APP_FEATURES={"beta":true}). Keys are the toml tags, as in the config
+// file. For structs, the keys of the document override single fields and
+// other fields keep their values; maps and slices are replaced. path is the
+// dotted path of the field. Errors never contain the value.
+func loadFormattedValue(value reflect.Value, fieldInfo FieldInfo, envValue, path string) error {
+	kind := value.Kind()
+	// The document is decoded as the value of a single-field struct, which
+	// works the same for every field type
+	wrapper := reflect.StructOf([]reflect.StructField{{Name: "Value", Type: value.Type(), Tag: `toml:"value"`}})
+
+	var tree *toml.Tree
+	var err error
+	switch fieldInfo.Format {
+	case "toml":
+		if kind != reflect.Struct && kind != reflect.Map {
+			return fmt.Errorf("format:\"toml\" on field %s requires a struct or map field", path)
+		}
+		var doc *toml.Tree
+		if doc, err = loadTOMLSnippet(envValue); err == nil {
+			tree, _ = toml.TreeFromMap(map[string]interface{}{})
+			tree.SetPath([]string{"value"}, doc)
+		}
+	case "json":
+		if kind != reflect.Struct && kind != reflect.Map && kind != reflect.Slice {
+			return fmt.Errorf("format:\"json\" on field %s requires a struct, map or slice field", path)
+		}
+		if !json.Valid([]byte(envValue)) {
+			return fmt.Errorf("failed to parse env value for field %s: invalid JSON", path)
+		}
+		tree, err = jsonToTree([]byte(`{"value":`+envValue+`}`), wrapper)
+	default:
+		return fmt.Errorf("unknown format '%s' on field %s, expected toml or json", fieldInfo.Format, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse env value for field %s: invalid %s: %w", path, strings.ToUpper(fieldInfo.Format), err)
+	}
+
+	if err := prepareTree(tree, wrapper, false); err != nil {
+		return fmt.Errorf("failed to parse env value for field %s: %w", path, err)
+	}
+	decoded := reflect.New(wrapper)
+	if err := unmarshalTree(tree, decoded.Interface()); err != nil {
+		return fmt.Errorf("failed to parse env value for field %s: %w", path, err)
+	}
+	result := decoded.Elem().Field(0)
+
+	if kind != reflect.Struct {
+		value.Set(result)
+		return nil
+	}
+	doc, ok := tree.GetPath([]string{"value"}).(*toml.Tree)
+	if !ok {
+		return fmt.Errorf("failed to parse env value for field %s: expected an object", path)
+	}
+	for i, fi := range getCachedTypeInfo(value.Type()).Fields {
+		if doc.HasPath([]string{fi.Key}) {
+			value.Field(i).Set(result.Field(i))
+		}
+	}
+	return nil
+}
+
+// loadTOMLSnippet parses a TOML document passed in an environment variable.
+// Shells often cannot pass newlines, so when the value does not parse as is,
+// literal \n sequences are taken as line breaks.
+func loadTOMLSnippet(doc string) (*toml.Tree, error) {
+	tree, err := loadTOML([]byte(doc))
+	if err != nil && strings.Contains(doc, `\n`) {
+		if unescaped, retryErr := loadTOML([]byte(strings.ReplaceAll(doc, `\n`, "\n"))); retryErr == nil {
+			return unescaped, nil
+		}
+	}
+	return tree, err
+}
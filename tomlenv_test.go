@@ -0,0 +1,173 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+)
+
+// TestFormatTOMLEnv는 format:"toml" 태그가 붙은 필드를 하나의 환경변수에 담긴 TOML 문서로 설정하는지 테스트합니다
+func TestFormatTOMLEnv(t *testing.T) {
+	type Limits struct {
+		CPU    int      `toml:"cpu" env:"CPU"`
+		Memory string   `toml:"memory" env:"MEMORY"`
+		Zones  []string `toml:"zones" env:"ZONES"`
+	}
+	type FormatConfig struct {
+		Limits Limits            `toml:"limits" env:"LIMITS" format:"toml"`
+		Labels map[string]string `toml:"labels" env:"LABELS" format:"toml"`
+	}
+
+	t.Run("struct and map", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "formatapp", `[limits]
+cpu = 1
+zones = ["a"]
+`)
+		defer cleanup()
+		AppName = "formatapp"
+		t.Setenv("FORMATAPP_LIMITS", "cpu = 2\nmemory = \"1Gi\"")
+		t.Setenv("FORMATAPP_LABELS", `team = "core"\nenv = "prod"`)
+
+		if err := LoadConfig[FormatConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[FormatConfig]()
+
+		if cfg.Limits.CPU != 2 || cfg.Limits.Memory != "1Gi" {
+			t.Errorf("expected limits from the TOML snippet, got %+v", cfg.Limits)
+		}
+		if !reflect.DeepEqual(cfg.Limits.Zones, []string{"a"}) {
+			t.Errorf("expected keys missing from the snippet to keep their file values, got %v", cfg.Limits.Zones)
+		}
+		if !reflect.DeepEqual(cfg.Labels, map[string]string{"team": "core", "env": "prod"}) {
+			t.Errorf("expected labels from the snippet with literal \\n, got %v", cfg.Labels)
+		}
+	})
+
+	t.Run("field env var overrides snippet", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "formatapp"
+		t.Setenv("FORMATAPP_LIMITS", "cpu = 2\nmemory = \"1Gi\"")
+		t.Setenv("FORMATAPP_LIMITS_CPU", "4")
+
+		if err := LoadConfig[FormatConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if cfg := GetConfig[FormatConfig](); cfg.Limits.CPU != 4 || cfg.Limits.Memory != "1Gi" {
+			t.Errorf("expected FORMATAPP_LIMITS_CPU to override the snippet, got %+v", cfg.Limits)
+		}
+	})
+
+	t.Run("invalid snippet", func(t *testing.T) {
+		var cfg FormatConfig
+		lookup := func(key string) (string, bool) {
+			if key == "FORMATAPP_LIMITS" {
+				return "cpu = = 2", true
+			}
+			return "", false
+		}
+		err := loadStructEnv(lookup, reflect.ValueOf(&cfg).Elem(), "formatapp", "")
+		if err == nil || !strings.Contains(err.Error(), "field limits: invalid TOML") {
+			t.Errorf("expected an invalid TOML error, got %v", err)
+		}
+	})
+}
+
+// TestFormatJSONEnv는 format:"json" 태그가 붙은 필드를 하나의 환경변수에 담긴 JSON 문서로 설정하는지 테스트합니다
+func TestFormatJSONEnv(t *testing.T) {
+	type Limits struct {
+		CPU    int    `toml:"cpu" env:"CPU"`
+		Memory string `toml:"memory" env:"MEMORY"`
+	}
+	type JSONConfig struct {
+		Limits   Limits          `toml:"limits" env:"LIMITS" format:"json"`
+		Features map[string]bool `toml:"features" env:"FEATURES" format:"json"`
+		Hosts    []string        `toml:"hosts" env:"HOSTS" format:"json"`
+	}
+
+	t.Run("struct, map and slice", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		_, cleanup := createTestTomlFile(t, "jsonapp", `[limits]
+memory = "512Mi"
+`)
+		defer cleanup()
+		AppName = "jsonapp"
+		t.Setenv("JSONAPP_LIMITS", `{"cpu": 2}`)
+		t.Setenv("JSONAPP_FEATURES", `{"beta": true, "legacy": false}`)
+		t.Setenv("JSONAPP_HOSTS", `["a.example.com", "b.example.com"]`)
+
+		if err := LoadConfig[JSONConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		cfg := GetConfig[JSONConfig]()
+
+		if cfg.Limits.CPU != 2 || cfg.Limits.Memory != "512Mi" {
+			t.Errorf("expected cpu from JSON and memory from the file, got %+v", cfg.Limits)
+		}
+		if !reflect.DeepEqual(cfg.Features, map[string]bool{"beta": true, "legacy": false}) {
+			t.Errorf("expected features from JSON, got %v", cfg.Features)
+		}
+		if !reflect.DeepEqual(cfg.Hosts, []string{"a.example.com", "b.example.com"}) {
+			t.Errorf("expected hosts from JSON, got %v", cfg.Hosts)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		var cfg JSONConfig
+		lookup := func(key string) (string, bool) {
+			if key == "JSONAPP_FEATURES" {
+				return `{"beta": true`, true
+			}
+			return "", false
+		}
+		err := loadStructEnv(lookup, reflect.ValueOf(&cfg).Elem(), "jsonapp", "")
+		if err == nil || !strings.Contains(err.Error(), "field features: invalid JSON") {
+			t.Errorf("expected an invalid JSON error naming the field, got %v", err)
+		}
+	})
+}
+
+// TestFormatEnvDecodesLikeFile는 환경변수 문서가 설정 파일과 같은 전처리와 디코더 옵션으로 디코딩되는지 테스트합니다
+func TestFormatEnvDecodesLikeFile(t *testing.T) {
+	type Upstream struct {
+		Host string `toml:"host" transformfn:"formatHostOnly"`
+	}
+	type DecodeConfig struct {
+		Upstream Upstream `toml:"upstream" env:"UPSTREAM" format:"toml"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	RegisterTransform("formatHostOnly", func(s string) (string, error) {
+		return strings.TrimPrefix(s, "https://"), nil
+	})
+
+	lookup := func(value string) envLookup {
+		return func(key string) (string, bool) {
+			if key == "DECODEAPP_UPSTREAM" {
+				return value, true
+			}
+			return "", false
+		}
+	}
+
+	var cfg DecodeConfig
+	if err := loadStructEnv(lookup(`host = "https://api.example.com"`), reflect.ValueOf(&cfg).Elem(), "decodeapp", ""); err != nil {
+		t.Fatalf("loadStructEnv failed: %v", err)
+	}
+	if cfg.Upstream.Host != "api.example.com" {
+		t.Errorf("expected transformfn to apply inside the document, got %q", cfg.Upstream.Host)
+	}
+
+	SetTOMLDecodeOptions(func(d *toml.Decoder) *toml.Decoder { return d.Strict(true) })
+	err := loadStructEnv(lookup(`host = "a"`+"\n"+`bogus = 1`), reflect.ValueOf(&cfg).Elem(), "decodeapp", "")
+	if err == nil || !strings.Contains(err.Error(), "field upstream") {
+		t.Errorf("expected the strict decoder option to reject the unknown key, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package ahatconfig
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// defaultLocation is the location of date and time values without an offset.
+var defaultLocation = time.UTC
+
+// SetDefaultLocation sets the location in which date and time values without
+// an offset, such as 2024-03-15 10:00:00 or the TOML local date-time
+// 2024-03-15T10:00:00, are interpreted by subsequent loads. The default is
+// UTC, so the same value means the same instant on every host. A nil
+// location restores UTC.
+//
+// Example:
+//
+//	seoul, _ := time.LoadLocation("Asia/Seoul")
+//	ahatconfig.SetDefaultLocation(seoul)
+func SetDefaultLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	defaultLocation = loc
+}
+
+// loadTOML parses a TOML document. go-toml rejects a local date such as
+// 2024-01-02 that is directly followed by a newline, comma or closing
+// bracket, so a space is inserted after such dates first.
+func loadTOML(data []byte) (*toml.Tree, error) {
+	return toml.LoadBytes(padLocalDates(data))
+}
+
+// padLocalDates inserts a space after every bare local date value that is
+// directly followed by a line break, comma or closing bracket. Strings and
+// comments are left untouched.
+func padLocalDates(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '#':
+			end := i
+			for end < len(data) && data[end] != '\n' {
+				end++
+			}
+			out = append(out, data[i:end]...)
+			i = end - 1
+		case c == '"' || c == '\'':
+			end := tomlStringEnd(data, i)
+			out = append(out, data[i:end]...)
+			i = end - 1
+		case isLocalDateAt(data, i):
+			out = append(out, data[i:i+10]...)
+			i += 9
+			if i+1 < len(data) && strings.IndexByte("\r\n,]}", data[i+1]) >= 0 {
+				out = append(out, ' ')
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// tomlStringEnd returns the index just past the string starting at data[i],
+// which may be a basic, literal or multi-line string.
+func tomlStringEnd(data []byte, i int) int {
+	quote := data[i]
+	if i+2 < len(data) && data[i+1] == quote && data[i+2] == quote {
+		delim := string([]byte{quote, quote, quote})
+		if end := strings.Index(string(data[i+3:]), delim); end >= 0 {
+			return i + 3 + end + 3
+		}
+		return len(data)
+	}
+	for j := i + 1; j < len(data); j++ {
+		switch {
+		case data[j] == '\\' && quote == '"':
+			j++
+		case data[j] == quote || data[j] == '\n':
+			return j + 1
+		}
+	}
+	return len(data)
+}
+
+// isLocalDateAt reports whether a date in the form YYYY-MM-DD starts at
+// data[i] and is not part of a longer word or number.
+func isLocalDateAt(data []byte, i int) bool {
+	if i+10 > len(data) || (i > 0 && isWordByte(data[i-1])) {
+		return false
+	}
+	for j, c := range data[i : i+10] {
+		if j == 4 || j == 7 {
+			if c != '-' {
+				return false
+			}
+		} else if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordByte reports whether c can be part of a bare key or number.
+func isWordByte(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c == ':' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// convertTOMLDateTimes converts TOML date and time values assigned to
+// time.Time and string fields into values go-toml can decode: local dates and
+// times become time.Time values in the default location, and string fields
+// get the canonical TOML form of the value (RFC 3339 for offset date-times).
+// It descends into tables and arrays of tables.
+func convertTOMLDateTimes(tree *toml.Tree, t reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		raw := tree.GetPath([]string{fieldInfo.Key})
+		if raw == nil {
+			continue
+		}
+
+		switch value := raw.(type) {
+		case *toml.Tree:
+			convertTOMLDateTimes(value, fieldInfo.Type)
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for _, elem := range value {
+					convertTOMLDateTimes(elem, elemType)
+				}
+			}
+		case []interface{}:
+			if fieldInfo.Type.Kind() != reflect.Slice && fieldInfo.Type.Kind() != reflect.Array {
+				continue
+			}
+			converted := make([]interface{}, len(value))
+			changed := false
+			for i, elem := range value {
+				var ok bool
+				if converted[i], ok = convertDateTime(elem, fieldInfo.Type.Elem()); ok {
+					changed = true
+				}
+			}
+			if changed {
+				tree.SetPath([]string{fieldInfo.Key}, converted)
+			}
+		default:
+			if converted, ok := convertDateTime(raw, fieldInfo.Type); ok {
+				tree.SetPath([]string{fieldInfo.Key}, converted)
+			}
+		}
+	}
+}
+
+// convertDateTime converts a TOML date or time value for a field of type t.
+// It returns false for other values, which are left unchanged.
+func convertDateTime(value interface{}, t reflect.Type) (interface{}, bool) {
+	switch {
+	case t == timeType:
+		switch v := value.(type) {
+		case toml.LocalDate:
+			return v.In(defaultLocation), true
+		case toml.LocalTime:
+			return time.Date(0, time.January, 1, v.Hour, v.Minute, v.Second, v.Nanosecond, defaultLocation), true
+		case toml.LocalDateTime:
+			return v.In(defaultLocation), true
+		}
+	case t.Kind() == reflect.String:
+		switch v := value.(type) {
+		case toml.LocalDate:
+			return v.String(), true
+		case toml.LocalTime:
+			return v.String(), true
+		case toml.LocalDateTime:
+			return v.String(), true
+		case time.Time:
+			return v.Format(time.RFC3339Nano), true
+		}
+	}
+	return value, false
+}
@@ -0,0 +1,160 @@
+package ahatconfig
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestTOMLLocalDateTime는 TOML local-date/local-time 값이 time.Time과 string 필드에 올바르게 로드되는지 테스트합니다
+func TestTOMLLocalDateTime(t *testing.T) {
+	type Window struct {
+		Start time.Time `toml:"start"`
+		End   string    `toml:"end"`
+	}
+	type DateConfig struct {
+		ReleaseDate   time.Time   `toml:"release_date"`
+		ReleaseDay    string      `toml:"release_day"`
+		BackupAt      time.Time   `toml:"backup_at"`
+		BackupTime    string      `toml:"backup_time"`
+		DeployAt      string      `toml:"deploy_at"`
+		CreatedAt     string      `toml:"created_at"`
+		Holidays      []time.Time `toml:"holidays"`
+		HolidayLabels []string    `toml:"holiday_labels"`
+		Note          string      `toml:"note"`
+		Windows       []Window    `toml:"windows"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "dateapp", `release_date = 2024-03-15
+release_day = 2024-03-15
+backup_at = 02:30:00
+backup_time = 02:30:00.5
+deploy_at = 2024-03-15T10:00:00
+created_at = 2024-03-15T10:00:00+09:00
+holidays = [2024-12-25, 2025-01-01]
+holiday_labels = [2024-12-25,2025-01-01]
+note = "2024-03-15" # 2024-03-15
+
+[[windows]]
+start = 2024-06-01
+end = 18:00:00
+`)
+	defer cleanup()
+	AppName = "dateapp"
+
+	if err := LoadConfig[DateConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[DateConfig]()
+
+	if !cfg.ReleaseDate.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected local-date at midnight UTC, got %v", cfg.ReleaseDate)
+	}
+	if cfg.ReleaseDay != "2024-03-15" {
+		t.Errorf("expected local-date as '2024-03-15', got '%s'", cfg.ReleaseDay)
+	}
+	if !cfg.BackupAt.Equal(time.Date(0, 1, 1, 2, 30, 0, 0, time.UTC)) {
+		t.Errorf("expected local-time on the zero date, got %v", cfg.BackupAt)
+	}
+	if cfg.BackupTime != "02:30:00.500000000" {
+		t.Errorf("expected local-time as '02:30:00.500000000', got '%s'", cfg.BackupTime)
+	}
+	if cfg.DeployAt != "2024-03-15T10:00:00" {
+		t.Errorf("expected local-datetime as '2024-03-15T10:00:00', got '%s'", cfg.DeployAt)
+	}
+	if cfg.CreatedAt != "2024-03-15T10:00:00+09:00" {
+		t.Errorf("expected offset date-time in RFC 3339, got '%s'", cfg.CreatedAt)
+	}
+	expectedHolidays := []time.Time{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(cfg.Holidays, expectedHolidays) {
+		t.Errorf("expected holidays %v, got %v", expectedHolidays, cfg.Holidays)
+	}
+	if !reflect.DeepEqual(cfg.HolidayLabels, []string{"2024-12-25", "2025-01-01"}) {
+		t.Errorf("expected holiday labels as strings, got %v", cfg.HolidayLabels)
+	}
+	if cfg.Note != "2024-03-15" {
+		t.Errorf("expected quoted date to stay unchanged, got '%s'", cfg.Note)
+	}
+	if len(cfg.Windows) != 1 || !cfg.Windows[0].Start.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) || cfg.Windows[0].End != "18:00:00" {
+		t.Errorf("expected dates in arrays of tables to be converted, got %+v", cfg.Windows)
+	}
+}
+
+// TestTimeFieldFromEnv는 time.Time 필드를 환경변수에서 읽고 출력 함수들이 하나의 값으로 다루는지 테스트합니다
+func TestTimeFieldFromEnv(t *testing.T) {
+	type TimeConfig struct {
+		Start    time.Time `toml:"start" env:"START" default:"2024-01-01"`
+		Deadline time.Time `toml:"deadline" env:"DEADLINE" required:"true"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "timeapp"
+
+	if err := LoadConfig[TimeConfig](); err == nil {
+		t.Error("expected a required error for the unset time field")
+	}
+
+	t.Setenv("TIMEAPP_DEADLINE", "2024-03-15T10:00:00+09:00")
+	if err := LoadConfig[TimeConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[TimeConfig]()
+	if !cfg.Start.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected start from the default tag, got %v", cfg.Start)
+	}
+	if cfg.Deadline.Format(time.RFC3339) != "2024-03-15T10:00:00+09:00" {
+		t.Errorf("expected deadline from the env var, got %v", cfg.Deadline)
+	}
+	if got, ok := AsMap()["deadline"].(time.Time); !ok || !got.Equal(cfg.Deadline) {
+		t.Errorf("expected AsMap to keep the time value, got %v", AsMap()["deadline"])
+	}
+	if err := FprintConfig(io.Discard, true); err != nil {
+		t.Errorf("FprintConfig failed: %v", err)
+	}
+
+	if _, err := parseTimeValue("next week"); err == nil {
+		t.Error("expected a parse error for an invalid time")
+	}
+}
+
+// TestDefaultLocation은 오프셋이 없는 날짜/시간 값이 SetDefaultLocation으로 설정한 위치에서 해석되는지 테스트합니다
+func TestDefaultLocation(t *testing.T) {
+	type LocationConfig struct {
+		Deadline time.Time `toml:"deadline" env:"DEADLINE"`
+		Release  time.Time `toml:"release"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "locapp", "release = 2024-03-15T09:00:00\n")
+	defer cleanup()
+	AppName = "locapp"
+	SetDefaultLocation(time.FixedZone("KST", 9*60*60))
+	t.Setenv("LOCAPP_DEADLINE", "2024-03-15 10:00:00")
+
+	if err := LoadConfig[LocationConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[LocationConfig]()
+	if want := time.Date(2024, 3, 15, 1, 0, 0, 0, time.UTC); !cfg.Deadline.Equal(want) {
+		t.Errorf("expected the env datetime in the default location (%v), got %v", want, cfg.Deadline)
+	}
+	if want := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC); !cfg.Release.Equal(want) {
+		t.Errorf("expected the TOML local datetime in the default location (%v), got %v", want, cfg.Release)
+	}
+
+	// An explicit offset wins over the default location
+	got, err := parseTimeValue("2024-03-15T10:00:00Z")
+	if err != nil || !got.(time.Time).Equal(time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the explicit offset to be kept, got %v (%v)", got, err)
+	}
+
+	SetDefaultLocation(nil)
+	if defaultLocation != time.UTC {
+		t.Errorf("expected a nil location to restore UTC, got %v", defaultLocation)
+	}
+}
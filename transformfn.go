@@ -0,0 +1,127 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pelletier/go-toml"
+)
+
+// TransformFunc rewrites the raw string value of a field before it is
+// converted to the field's type.
+type TransformFunc func(string) (string, error)
+
+// transformFuncs holds the functions registered with RegisterTransform.
+var transformFuncs = map[string]TransformFunc{}
+
+// RegisterTransform registers fn under name for the transformfn tag. A field
+// tagged transformfn:"<name>" has its raw value, from an environment
+// variable, a default or a TOML string, passed through fn before it is
+// converted to the field's type. Several names separated by commas are
+// applied in order. Pass a nil fn to remove a transform.
+//
+// Example:
+//
+//	ahatconfig.RegisterTransform("hostOnly", func(s string) (string, error) {
+//	    u, err := url.Parse(s)
+//	    if err != nil {
+//	        return "", err
+//	    }
+//	    return u.Hostname(), nil
+//	})
+//
+//	DBHost string `toml:"db_host" env:"DB_HOST" transformfn:"hostOnly"`
+func RegisterTransform(name string, fn TransformFunc) {
+	if fn == nil {
+		delete(transformFuncs, name)
+		return
+	}
+	transformFuncs[name] = fn
+}
+
+// applyTransformFns passes value through the functions named by the
+// transformfn tag of the field at path.
+func applyTransformFns(value string, fieldInfo FieldInfo, path string) (string, error) {
+	for _, name := range fieldInfo.TransformFns {
+		fn, ok := transformFuncs[name]
+		if !ok {
+			return "", fmt.Errorf("unknown transformfn '%s' on field %s", name, path)
+		}
+		transformed, err := fn(value)
+		if err != nil {
+			return "", fmt.Errorf("transformfn '%s' failed for field %s: %w", name, path, err)
+		}
+		value = transformed
+	}
+	return value, nil
+}
+
+// convertTOMLTransformFns applies the transformfn tags to the string values
+// of the TOML document. It descends into tables and arrays of tables; path is
+// the dotted path of tree.
+func convertTOMLTransformFns(tree *toml.Tree, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for _, fieldInfo := range getCachedTypeInfo(t).Fields {
+		fieldPath := joinPath(path, fieldInfo.Key)
+		value := tree.GetPath([]string{fieldInfo.Key})
+
+		// go-toml fills a missing key from the default tag as is, so the
+		// transformed default is written in its place
+		if value == nil && len(fieldInfo.TransformFns) > 0 {
+			if def := fieldDefault(fieldInfo, fieldPath); def != "" {
+				value = def
+			}
+		}
+
+		switch value := value.(type) {
+		case string:
+			if len(fieldInfo.TransformFns) == 0 {
+				continue
+			}
+			transformed, err := transformTreeString(value, fieldInfo, fieldPath)
+			if err != nil {
+				return err
+			}
+			tree.SetPath([]string{fieldInfo.Key}, transformed)
+		case *toml.Tree:
+			if err := convertTOMLTransformFns(value, fieldInfo.Type, fieldPath); err != nil {
+				return err
+			}
+		case []*toml.Tree:
+			if elemType, ok := structElemType(fieldInfo.Type); ok {
+				for i, elem := range value {
+					if err := convertTOMLTransformFns(elem, elemType, indexPath(fieldPath, i)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// transformTreeString applies the transformfn tag to the TOML string value of
+// a field. The result stays a string, which the later conversions and go-toml
+// decode, except for plain numbers and booleans: those are parsed here since
+// go-toml does not decode strings into them.
+func transformTreeString(value string, fieldInfo FieldInfo, path string) (interface{}, error) {
+	switch fieldInfo.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		if fieldInfo.Type != durationType && fieldInfo.Base == 0 {
+			parsed, err := parseFieldValue(value, fieldInfo, path)
+			if err != nil {
+				return nil, err
+			}
+			return normalizeTreeValue(parsed), nil
+		}
+	}
+	return applyTransformFns(value, fieldInfo, path)
+}
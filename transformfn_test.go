@@ -0,0 +1,97 @@
+package ahatconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRegisterTransform는 transformfn 태그에 등록한 함수가 타입 변환 전에 원시 값에 적용되는지 테스트합니다
+func TestRegisterTransform(t *testing.T) {
+	type TransformFnConfig struct {
+		Region string `toml:"region" env:"REGION" transformfn:"upper"`
+		Zone   string `toml:"zone" env:"ZONE" transformfn:"upper"`
+		Port   int    `toml:"port" env:"PORT" transformfn:"stripPrefix"`
+		Name   string `toml:"name" env:"NAME" transformfn:"stripPrefix,upper" default:"port-default"`
+	}
+
+	resetGlobalConfig()
+	RegisterTransform("upper", func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	RegisterTransform("stripPrefix", func(s string) (string, error) {
+		return strings.TrimPrefix(s, "port-"), nil
+	})
+
+	appName := "transformfnapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+zone = "eu-west-1a"
+`)
+	defer cleanup()
+
+	t.Setenv("TRANSFORMFNAPP_REGION", "eu-west-1")
+	t.Setenv("TRANSFORMFNAPP_PORT", "port-8080")
+
+	AppName = appName
+	if err := LoadConfig[TransformFnConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[TransformFnConfig]()
+
+	if cfg.Region != "EU-WEST-1" {
+		t.Errorf("expected transformed env value EU-WEST-1, got %q", cfg.Region)
+	}
+	if cfg.Zone != "EU-WEST-1A" {
+		t.Errorf("expected transformed TOML value EU-WEST-1A, got %q", cfg.Zone)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("expected the transform to run before parsing, got %d", cfg.Port)
+	}
+	if cfg.Name != "DEFAULT" {
+		t.Errorf("expected transforms applied in order to the default, got %q", cfg.Name)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		RegisterTransform("fail", func(s string) (string, error) {
+			return "", errors.New("bad input")
+		})
+		if _, err := applyTransformFns("x", FieldInfo{TransformFns: []string{"fail"}}, "dsn"); err == nil || !strings.Contains(err.Error(), "bad input") {
+			t.Errorf("expected the transform error to be returned, got %v", err)
+		}
+		RegisterTransform("fail", nil)
+		if _, err := applyTransformFns("x", FieldInfo{TransformFns: []string{"fail"}}, "dsn"); err == nil || !strings.Contains(err.Error(), "unknown transformfn 'fail'") {
+			t.Errorf("expected an unknown transformfn error, got %v", err)
+		}
+	})
+}
+
+// TestRegisterTransformTOMLNumbers는 TOML 문자열 값이 변환 후 숫자 필드로 디코딩되는지 테스트합니다
+func TestRegisterTransformTOMLNumbers(t *testing.T) {
+	type TransformNumberConfig struct {
+		Port    int     `toml:"port" transformfn:"stripPrefix"`
+		Ratio   float64 `toml:"ratio" transformfn:"stripPrefix" default:"port-0.5"`
+		Enabled bool    `toml:"enabled" transformfn:"stripPrefix"`
+	}
+
+	resetGlobalConfig()
+	RegisterTransform("stripPrefix", func(s string) (string, error) {
+		return strings.TrimPrefix(s, "port-"), nil
+	})
+
+	appName := "transformnumapp"
+	_, cleanup := createTestTomlFile(t, appName, `
+port = "port-8080"
+enabled = "port-true"
+`)
+	defer cleanup()
+
+	AppName = appName
+	if err := LoadConfig[TransformNumberConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[TransformNumberConfig]()
+
+	if cfg.Port != 8080 || cfg.Ratio != 0.5 || !cfg.Enabled {
+		t.Errorf("expected port 8080, ratio 0.5 and enabled, got %+v", *cfg)
+	}
+}
@@ -0,0 +1,199 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// LoadTree loads the TOML file of appname, found the same way as by
+// LoadConfig, as a go-toml tree for tools that edit the file in place.
+//
+// SaveTree writes back only the lines of the keys that changed, so the
+// file's comments and formatting survive the edit.
+//
+// Example:
+//
+//	tree, err := ahatconfig.LoadTree("myapp")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	if err := ahatconfig.SetByPath(tree, "server.port", 9090); err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = ahatconfig.SaveTree("myapp", tree)
+func LoadTree(appname string) (*toml.Tree, error) {
+	tomlPath, err := configFilePath(appname + ".toml")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(tomlPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tomlPath, err)
+	}
+	tree, err := loadTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", tomlPath, err)
+	}
+	return tree, nil
+}
+
+// SaveTree writes tree to the TOML file of appname, at the path LoadTree
+// reads from. The changed, added and removed keys are patched into the
+// existing file line by line, keeping its comments and formatting; new
+// tables are appended at the end. Edits that cannot be made that way, such
+// as a change inside an inline table or an array of tables, fall back to
+// rewriting the whole file from tree, which drops the comments. The write
+// is atomic and keeps the permissions of an existing file; a new file is
+// created with 0600 permissions, as it may hold secrets.
+func SaveTree(appname string, t *toml.Tree) error {
+	tomlPath, err := configFilePath(appname + ".toml")
+	if err != nil {
+		return err
+	}
+	if realPath, err := filepath.EvalSymlinks(tomlPath); err == nil {
+		tomlPath = realPath
+	}
+
+	var data []byte
+	if current, err := os.ReadFile(tomlPath); err == nil {
+		if data, err = patchTOML(current, t); err != nil {
+			log.Printf("Rewriting %s without its comments: %v", tomlPath, err)
+		}
+	}
+	if data == nil {
+		encoded, err := t.ToTomlString()
+		if err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		data = []byte(encoded)
+	}
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(tomlPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := writeFileAtomic(tomlPath, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tomlPath, err)
+	}
+	return nil
+}
+
+// WriteConfigTOML writes cfg, a config struct or a pointer to one, to the
+// TOML file of appname at the path LoadTree reads from. Secrets are written
+// as they are, so the file is created with 0600 permissions. The write is
+// atomic: the data goes to a temporary file in the same directory, which is
+// synced and then renamed over the config file, so a crash never leaves a
+// partially written config behind. When the config file is a symlink, its
+// target is replaced.
+//
+// Example:
+//
+//	cfg := ahatconfig.GetConfig[MyConfig]()
+//	cfg.Server.Port = 9090
+//	err := ahatconfig.WriteConfigTOML("myapp", cfg)
+func WriteConfigTOML(appname string, cfg interface{}) error {
+	tomlPath, err := configFilePath(appname + ".toml")
+	if err != nil {
+		return err
+	}
+	if realPath, err := filepath.EvalSymlinks(tomlPath); err == nil {
+		tomlPath = realPath
+	}
+
+	data, err := toml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := writeFileAtomic(tomlPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tomlPath, err)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces the file at path with data through a synced
+// temporary file in the same directory, so readers see either the old or the
+// new content.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Removing fails harmlessly once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself; not every platform can sync a directory
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// SetByPath sets the value at the dotted path (e.g. "server.port") in t,
+// creating missing tables. Integer and float values are stored as int64 and
+// float64, the types go-toml uses.
+func SetByPath(t *toml.Tree, path string, value interface{}) error {
+	keys := strings.Split(path, ".")
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("invalid config path '%s'", path)
+		}
+	}
+
+	for i := 1; i < len(keys); i++ {
+		parent := t.GetPath(keys[:i])
+		if parent == nil {
+			break
+		}
+		if _, ok := parent.(*toml.Tree); !ok {
+			return fmt.Errorf("cannot set %s: %s is not a table", path, strings.Join(keys[:i], "."))
+		}
+	}
+
+	t.SetPath(keys, normalizeTreeValue(value))
+	return nil
+}
+
+// normalizeTreeValue converts numbers to the int64 and float64 types used by
+// go-toml trees.
+func normalizeTreeValue(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	default:
+		return value
+	}
+}
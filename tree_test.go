@@ -0,0 +1,273 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTreeRoundTrip는 LoadTree, SetByPath, SaveTree로 한 값만 수정하고 다른 키는 유지되는지 테스트합니다
+func TestTreeRoundTrip(t *testing.T) {
+	type TreeConfig struct {
+		Server struct {
+			Host string `toml:"host"`
+			Port int    `toml:"port"`
+		} `toml:"server"`
+		Database struct {
+			User  string   `toml:"user"`
+			Hosts []string `toml:"hosts"`
+		} `toml:"database"`
+	}
+
+	resetGlobalConfig()
+	appName := "treeapp"
+	filePath, cleanup := createTestTomlFile(t, appName, `# application config
+[server]
+host = "localhost" # bind address
+port = 8080
+
+[database]
+user = "admin"
+hosts = ["db1", "db2"]
+`)
+	defer cleanup()
+	configPath = filePath
+
+	tree, err := LoadTree(appName)
+	if err != nil {
+		t.Fatalf("LoadTree failed: %v", err)
+	}
+	if err := SetByPath(tree, "server.port", 9090); err != nil {
+		t.Fatalf("SetByPath failed: %v", err)
+	}
+	if err := SetByPath(tree, "cache.ttl", "5m"); err != nil {
+		t.Fatalf("SetByPath failed to create a table: %v", err)
+	}
+	if err := SaveTree(appName, tree); err != nil {
+		t.Fatalf("SaveTree failed: %v", err)
+	}
+
+	AppName = appName
+	if err := LoadConfig[TreeConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	cfg := GetConfig[TreeConfig]()
+	if cfg.Server.Port != 9090 {
+		t.Errorf("expected port 9090, got %d", cfg.Server.Port)
+	}
+	if cfg.Server.Host != "localhost" || cfg.Database.User != "admin" || strings.Join(cfg.Database.Hosts, ",") != "db1,db2" {
+		t.Errorf("expected unrelated keys to survive, got %+v", cfg)
+	}
+
+	data, err := os.ReadFile(filepath.Join(filepath.Dir(filePath), appName+".toml"))
+	if err != nil {
+		t.Fatalf("failed to read saved file: %v", err)
+	}
+	if !strings.Contains(string(data), `ttl = "5m"`) {
+		t.Errorf("expected new key in saved file, got:\n%s", data)
+	}
+	for _, want := range []string{"# application config\n[server]", `host = "localhost" # bind address`, "port = 9090\n", `hosts = ["db1", "db2"]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %q to survive in saved file, got:\n%s", want, data)
+		}
+	}
+}
+
+// TestSaveTreePatch는 SaveTree가 추가, 변경, 삭제된 키의 줄만 수정하고 줄 단위로 수정할 수 없으면 전체를 다시 쓰는지 테스트합니다
+func TestSaveTreePatch(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	appName := "treepatch"
+	original := `# top comment
+name = "app" # the name
+
+[server]
+# listen settings
+host = "localhost"
+ports = [
+  8080, # http
+  8443, # https
+]
+debug = true # remove me
+
+# trailing comment
+`
+	filePath, cleanup := createTestTomlFile(t, appName, original)
+	defer cleanup()
+	configPath = filePath
+	if err := os.Chmod(filePath, 0640); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+
+	tree, err := LoadTree(appName)
+	if err != nil {
+		t.Fatalf("LoadTree failed: %v", err)
+	}
+	SetByPath(tree, "name", "renamed")
+	SetByPath(tree, "version", 2)
+	SetByPath(tree, "server.ports", []interface{}{int64(9090)})
+	SetByPath(tree, "server.timeout", "5s")
+	if err := tree.DeletePath([]string{"server", "debug"}); err != nil {
+		t.Fatalf("DeletePath failed: %v", err)
+	}
+	if err := SaveTree(appName, tree); err != nil {
+		t.Fatalf("SaveTree failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filePath)
+	want := `# top comment
+name = "renamed" # the name
+version = 2
+
+[server]
+# listen settings
+host = "localhost"
+ports = [9090]
+timeout = "5s"
+
+# trailing comment
+`
+	if string(data) != want {
+		t.Errorf("expected patched file:\n%s\ngot:\n%s", want, data)
+	}
+	if info, err := os.Stat(filePath); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("expected permissions to be kept, got %v (%v)", info.Mode().Perm(), err)
+	}
+
+	// A change inside an inline table rewrites the file
+	if err := os.WriteFile(filePath, []byte("# gone\nserver = { host = \"a\", port = 1 }\n"), 0640); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if tree, err = LoadTree(appName); err != nil {
+		t.Fatalf("LoadTree failed: %v", err)
+	}
+	SetByPath(tree, "server.port", 2)
+	if err := SaveTree(appName, tree); err != nil {
+		t.Fatalf("SaveTree failed: %v", err)
+	}
+	saved, err := LoadTree(appName)
+	if err != nil {
+		t.Fatalf("reloading the saved file failed: %v", err)
+	}
+	if saved.Get("server.port") != int64(2) || saved.Get("server.host") != "a" {
+		t.Errorf("expected the rewritten file to hold the edit, got %v", saved.ToMap())
+	}
+}
+
+// TestSetByPathErrors는 잘못된 경로에 대해 SetByPath가 오류를 반환하는지 테스트합니다
+func TestSetByPathErrors(t *testing.T) {
+	resetGlobalConfig()
+	appName := "treeerr"
+	filePath, cleanup := createTestTomlFile(t, appName, "[server]\nport = 8080\n")
+	defer cleanup()
+	configPath = filePath
+
+	tree, err := LoadTree(appName)
+	if err != nil {
+		t.Fatalf("LoadTree failed: %v", err)
+	}
+
+	if err := SetByPath(tree, "server..port", 1); err == nil {
+		t.Error("expected an error for an empty path segment")
+	}
+	if err := SetByPath(tree, "server.port.value", 1); err == nil || !strings.Contains(err.Error(), "server.port is not a table") {
+		t.Errorf("expected an error for setting below a value, got %v", err)
+	}
+
+	resetGlobalConfig()
+	configPath = filepath.Join(t.TempDir(), "app")
+	if _, err := LoadTree("missing"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+// TestWriteConfigTOML은 WriteConfigTOML이 임시 파일 없이 0600 권한으로 원자적으로 기록하고 다시 로드할 수 있는지 테스트합니다
+func TestWriteConfigTOML(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	filePath, cleanup := createTestTomlFile(t, "writeapp", `[server]
+host = "localhost"
+
+[database]
+user = "admin"
+password = "old"
+`)
+	defer cleanup()
+	if err := os.Chmod(filePath, 0644); err != nil {
+		t.Fatalf("failed to chmod: %v", err)
+	}
+
+	if err := InitConfigSafe[TestConfig]("writeapp"); err != nil {
+		t.Fatalf("InitConfigSafe failed: %v", err)
+	}
+	cfg := GetConfig[TestConfig]()
+	cfg.Server.Port = 9090
+	cfg.Database.Password = "hunter2"
+	cfg.Database.Hosts = []string{"db1", "db2"}
+
+	if err := WriteConfigTOML("writeapp", cfg); err != nil {
+		t.Fatalf("WriteConfigTOML failed: %v", err)
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("failed to stat config file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("expected permissions 0600, got %o", perm)
+	}
+	entries, err := os.ReadDir(filepath.Dir(filePath))
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("expected no temporary file to be left, found %s", entry.Name())
+		}
+	}
+
+	// A failed write leaves the previous file untouched
+	before, _ := os.ReadFile(filePath)
+	if err := WriteConfigTOML("writeapp", struct{ Bad chan int }{}); err == nil {
+		t.Error("expected an encoding error")
+	}
+	if after, _ := os.ReadFile(filePath); string(after) != string(before) {
+		t.Error("expected a failed write to keep the previous file")
+	}
+
+	resetGlobalConfig()
+	if err := InitConfigWithExactFile[TestConfig]("writeapp", filePath); err != nil {
+		t.Fatalf("reloading the written file failed: %v", err)
+	}
+	reloaded := GetConfig[TestConfig]()
+	if reloaded.Server.Port != 9090 || reloaded.Database.Password != "hunter2" || len(reloaded.Database.Hosts) != 2 {
+		t.Errorf("expected written values to be reloaded unmasked, got %+v", reloaded)
+	}
+}
+
+// TestSaveTreeNewFileMode는 SaveTree가 새로 만드는 파일을 0600 권한으로 만드는지 테스트합니다
+func TestSaveTreeNewFileMode(t *testing.T) {
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	filePath, cleanup := createTestTomlFile(t, "treenew", "")
+	defer cleanup()
+	if err := os.Remove(filePath); err != nil {
+		t.Fatalf("failed to remove config file: %v", err)
+	}
+
+	tree, err := loadTOML([]byte("password = \"hunter2\"\n"))
+	if err != nil {
+		t.Fatalf("loadTOML failed: %v", err)
+	}
+	if err := SaveTree("treenew", tree); err != nil {
+		t.Fatalf("SaveTree failed: %v", err)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("expected SaveTree to create the file: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("expected a new file to be created with mode 0600, got %o", mode)
+	}
+}
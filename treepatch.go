@@ -0,0 +1,418 @@
+package ahatconfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// tomlEntry is a key/value line of a TOML document. The offsets are byte
+// offsets into the document: the line starts at lineStart, the value spans
+// valueStart to valueEnd and the line, including a trailing comment and the
+// line break, ends at lineEnd.
+type tomlEntry struct {
+	lineStart, valueStart, valueEnd, lineEnd int
+}
+
+// tomlSection is a [table] of a TOML document, or the root table before the
+// first header. New keys of the table are inserted at end, just after its
+// last key/value line.
+type tomlSection struct {
+	path []string
+	end  int
+}
+
+// tomlDocument is the line structure of a TOML document, keyed by the
+// dotted paths of its keys and tables joined with pathSep.
+type tomlDocument struct {
+	entries  map[string]tomlEntry
+	sections map[string]tomlSection
+}
+
+// pathSep joins path keys in the maps of tomlDocument, as keys may contain
+// dots.
+const pathSep = "\x00"
+
+// tomlEdit replaces data[start:end] of a document with text.
+type tomlEdit struct {
+	start, end int
+	text       string
+}
+
+// patchTOML returns data, a TOML document, edited so that it holds the keys
+// and values of t. Only the lines of the keys that were changed, added or
+// removed are touched, so comments and formatting elsewhere survive. It
+// returns an error when an edit cannot be made line by line, e.g. a change
+// inside an inline table or an array of tables, or a removed table.
+func patchTOML(data []byte, t *toml.Tree) ([]byte, error) {
+	orig, err := loadTOML(data)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := scanTOMLDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	origLeaves, origTables := map[string]interface{}{}, map[string]bool{}
+	flattenTree(orig, nil, origLeaves, origTables)
+	newLeaves := map[string]interface{}{}
+	flattenTree(t, nil, newLeaves, map[string]bool{})
+
+	var edits []tomlEdit
+	for key, value := range origLeaves {
+		newValue, ok := newLeaves[key]
+		if ok && reflect.DeepEqual(value, newValue) {
+			continue
+		}
+		entry, found := doc.entries[key]
+		if !found {
+			return nil, fmt.Errorf("%s is not a key/value line", displayPath(key))
+		}
+		if !ok {
+			edits = append(edits, tomlEdit{entry.lineStart, entry.lineEnd, ""})
+			continue
+		}
+		repr, err := tomlValueRepr(newValue)
+		if err != nil {
+			return nil, err
+		}
+		edits = append(edits, tomlEdit{entry.valueStart, entry.valueEnd, repr})
+	}
+
+	added := map[string][]string{}
+	for key := range newLeaves {
+		if _, ok := origLeaves[key]; !ok {
+			parent := key[:max(strings.LastIndex(key, pathSep), 0)]
+			added[parent] = append(added[parent], key)
+		}
+	}
+	parents := make([]string, 0, len(added))
+	for parent := range added {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	var tail strings.Builder
+	for _, parent := range parents {
+		keys := added[parent]
+		sort.Strings(keys)
+		var lines strings.Builder
+		for _, key := range keys {
+			name := key[strings.LastIndex(key, pathSep)+1:]
+			repr, err := tomlValueRepr(newLeaves[key])
+			if err != nil {
+				return nil, err
+			}
+			lines.WriteString(quoteTOMLKey(name) + " = " + repr + "\n")
+		}
+
+		if origTables[parent] {
+			section, ok := doc.sections[parent]
+			if !ok {
+				return nil, fmt.Errorf("table %s has no [header] to add keys under", displayPath(parent))
+			}
+			text := lines.String()
+			if section.end > 0 && data[section.end-1] != '\n' {
+				text = "\n" + text
+			}
+			edits = append(edits, tomlEdit{section.end, section.end, text})
+			continue
+		}
+
+		path := strings.Split(parent, pathSep)
+		for i := 1; i < len(path); i++ {
+			prefix := strings.Join(path[:i], pathSep)
+			if _, ok := origLeaves[prefix]; ok {
+				return nil, fmt.Errorf("%s is not a table", displayPath(prefix))
+			}
+		}
+		header := make([]string, len(path))
+		for i, key := range path {
+			header[i] = quoteTOMLKey(key)
+		}
+		tail.WriteString("\n[" + strings.Join(header, ".") + "]\n" + lines.String())
+	}
+	if tail.Len() > 0 {
+		text := tail.String()
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			text = "\n" + text
+		}
+		edits = append(edits, tomlEdit{len(data), len(data), text})
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), data...)
+	for _, edit := range edits {
+		out = append(out[:edit.start], append([]byte(edit.text), out[edit.end:]...)...)
+	}
+
+	// Whatever the line edits missed, e.g. an emptied table, shows up here
+	patched, err := loadTOML(out)
+	if err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(patched.ToMap(), t.ToMap()) {
+		return nil, fmt.Errorf("the changes cannot be applied line by line")
+	}
+	return out, nil
+}
+
+// flattenTree adds the values of t, a table at path, to leaves and the
+// paths of t and its subtables to tables, both keyed by the path joined with
+// pathSep. Arrays of tables are values.
+func flattenTree(t *toml.Tree, path []string, leaves map[string]interface{}, tables map[string]bool) {
+	tables[strings.Join(path, pathSep)] = true
+	for _, key := range t.Keys() {
+		keyPath := append(append([]string(nil), path...), key)
+		if sub, ok := t.GetPath([]string{key}).(*toml.Tree); ok {
+			flattenTree(sub, keyPath, leaves, tables)
+			continue
+		}
+		leaves[strings.Join(keyPath, pathSep)] = t.GetPath([]string{key})
+	}
+}
+
+// displayPath turns a path joined with pathSep into a dotted path for
+// messages.
+func displayPath(key string) string {
+	return strings.ReplaceAll(key, pathSep, ".")
+}
+
+// tomlValueRepr returns the TOML representation of a single-line value.
+func tomlValueRepr(value interface{}) (string, error) {
+	switch value.(type) {
+	case *toml.Tree, []*toml.Tree:
+		return "", fmt.Errorf("tables cannot be written as a single value")
+	}
+	tree, err := toml.TreeFromMap(map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+	tree.SetPath([]string{"v"}, value)
+	s, err := tree.ToTomlString()
+	if err != nil {
+		return "", err
+	}
+	repr := strings.TrimSuffix(s, "\n")
+	if !strings.HasPrefix(repr, "v = ") || strings.Contains(repr, "\n") {
+		return "", fmt.Errorf("value %v cannot be written on a single line", value)
+	}
+	return strings.TrimPrefix(repr, "v = "), nil
+}
+
+// quoteTOMLKey returns key as a bare key when it only holds A-Za-z0-9_-,
+// and as a quoted key otherwise.
+func quoteTOMLKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for i := 0; i < len(key); i++ {
+		if !isBareKeyByte(key[i]) {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
+
+// isBareKeyByte reports whether c may appear in a bare TOML key.
+func isBareKeyByte(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// scanTOMLDocument records the key/value lines and [table] headers of data.
+// Keys inside arrays of tables are not recorded, as their paths are not
+// unique.
+func scanTOMLDocument(data []byte) (*tomlDocument, error) {
+	doc := &tomlDocument{
+		entries:  map[string]tomlEntry{},
+		sections: map[string]tomlSection{"": {end: -1}},
+	}
+	var current []string
+	inArray := false
+	var arrays []string
+	firstHeader := -1
+
+	for pos := 0; pos < len(data); {
+		lineStart := pos
+		pos = skipBlank(data, pos)
+		if pos >= len(data) {
+			break
+		}
+
+		switch data[pos] {
+		case '\n', '\r', '#':
+			pos = lineEnd(data, pos)
+		case '[':
+			if firstHeader < 0 {
+				firstHeader = lineStart
+			}
+			array := pos+1 < len(data) && data[pos+1] == '['
+			start := pos + 1
+			if array {
+				start++
+			}
+			path, end, err := scanKeyPath(data, start)
+			if err != nil {
+				return nil, err
+			}
+			closing := "]"
+			if array {
+				closing = "]]"
+			}
+			end = skipBlank(data, end)
+			if !strings.HasPrefix(string(data[end:]), closing) {
+				return nil, fmt.Errorf("unterminated table header at offset %d", lineStart)
+			}
+			pos = lineEnd(data, end+len(closing))
+
+			joined := strings.Join(path, pathSep)
+			inArray = array
+			for _, prefix := range arrays {
+				if joined == prefix || strings.HasPrefix(joined, prefix+pathSep) {
+					inArray = true
+				}
+			}
+			if array {
+				arrays = append(arrays, joined)
+			}
+			current = path
+			if !inArray {
+				doc.sections[joined] = tomlSection{path: path, end: pos}
+			}
+		default:
+			key, end, err := scanKeyPath(data, pos)
+			if err != nil {
+				return nil, err
+			}
+			end = skipBlank(data, end)
+			if end >= len(data) || data[end] != '=' {
+				return nil, fmt.Errorf("expected '=' after key at offset %d", lineStart)
+			}
+			valueStart := skipBlank(data, end+1)
+			valueEnd := scanValueEnd(data, valueStart)
+			pos = lineEnd(data, valueEnd)
+			if inArray {
+				continue
+			}
+
+			path := append(append([]string(nil), current...), key...)
+			doc.entries[strings.Join(path, pathSep)] = tomlEntry{lineStart, valueStart, valueEnd, pos}
+			joined := strings.Join(current, pathSep)
+			section := doc.sections[joined]
+			section.path, section.end = current, pos
+			doc.sections[joined] = section
+		}
+	}
+
+	// Keys of a root table without key/value lines go before the first header
+	if root := doc.sections[""]; root.end < 0 {
+		root.end = firstHeader
+		if root.end < 0 {
+			root.end = len(data)
+		}
+		doc.sections[""] = root
+	}
+	return doc, nil
+}
+
+// scanKeyPath parses the possibly dotted and quoted key starting at
+// data[pos] and returns its parts and the offset just past it.
+func scanKeyPath(data []byte, pos int) ([]string, int, error) {
+	var path []string
+	for {
+		pos = skipBlank(data, pos)
+		if pos >= len(data) {
+			return nil, pos, fmt.Errorf("unexpected end of document in key")
+		}
+
+		switch data[pos] {
+		case '"':
+			end := tomlStringEnd(data, pos)
+			key, err := strconv.Unquote(string(data[pos:end]))
+			if err != nil {
+				return nil, pos, fmt.Errorf("invalid quoted key at offset %d", pos)
+			}
+			path = append(path, key)
+			pos = end
+		case '\'':
+			end := tomlStringEnd(data, pos)
+			if end-pos < 2 || data[end-1] != '\'' {
+				return nil, pos, fmt.Errorf("invalid quoted key at offset %d", pos)
+			}
+			path = append(path, string(data[pos+1:end-1]))
+			pos = end
+		default:
+			end := pos
+			for end < len(data) && isBareKeyByte(data[end]) {
+				end++
+			}
+			if end == pos {
+				return nil, pos, fmt.Errorf("invalid key at offset %d", pos)
+			}
+			path = append(path, string(data[pos:end]))
+			pos = end
+		}
+
+		next := skipBlank(data, pos)
+		if next >= len(data) || data[next] != '.' {
+			return path, pos, nil
+		}
+		pos = next + 1
+	}
+}
+
+// scanValueEnd returns the offset just past the value starting at
+// data[pos], without trailing blanks. Strings, arrays and inline tables may
+// span several lines.
+func scanValueEnd(data []byte, pos int) int {
+	depth := 0
+	for pos < len(data) {
+		switch c := data[pos]; {
+		case c == '"' || c == '\'':
+			pos = tomlStringEnd(data, pos)
+			continue
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == '#' && depth > 0:
+			for pos < len(data) && data[pos] != '\n' {
+				pos++
+			}
+			continue
+		case (c == '#' || c == '\n') && depth <= 0:
+			for pos > 0 && (data[pos-1] == ' ' || data[pos-1] == '\t' || data[pos-1] == '\r') {
+				pos--
+			}
+			return pos
+		}
+		pos++
+	}
+	return pos
+}
+
+// skipBlank returns the offset of the first byte from pos that is not a
+// space or tab.
+func skipBlank(data []byte, pos int) int {
+	for pos < len(data) && (data[pos] == ' ' || data[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+// lineEnd returns the offset just past the line break of the line holding
+// data[pos], or the end of data.
+func lineEnd(data []byte, pos int) int {
+	for pos < len(data) && data[pos] != '\n' {
+		pos++
+	}
+	if pos < len(data) {
+		pos++
+	}
+	return pos
+}
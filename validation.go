@@ -0,0 +1,399 @@
+package ahatconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationMode controls how validation problems found during loading,
+// such as missing required fields, are reported.
+type ValidationMode int
+
+const (
+	// Strict fails loading on the first validation problem (the default).
+	Strict ValidationMode = iota
+	// Warn loads the config anyway and records validation problems as
+	// warnings, retrievable with Warnings.
+	Warn
+)
+
+var (
+	validationMode = Strict
+	warnings       []error
+	parseErrors    []error
+)
+
+// SetValidationMode sets how validation problems are reported by subsequent
+// loads. Use Warn to roll out stricter validation gradually without breaking
+// existing deployments.
+//
+// Example:
+//
+//	ahatconfig.SetValidationMode(ahatconfig.Warn)
+//	ahatconfig.InitConfig[MyConfig]("myapp")
+//	for _, w := range ahatconfig.Warnings() {
+//	    log.Printf("config warning: %v", w)
+//	}
+func SetValidationMode(mode ValidationMode) {
+	validationMode = mode
+}
+
+// Warnings returns the validation problems recorded by the last load in Warn
+// mode. It returns nil when the last load found no problems.
+func Warnings() []error {
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	if len(warnings) == 0 {
+		return nil
+	}
+	return append([]error(nil), warnings...)
+}
+
+// ParseErrors returns the values that failed to parse and were skipped by the
+// last load in SkipAndCount mode, each naming the field path. It returns nil
+// when nothing was skipped.
+func ParseErrors() []error {
+	instanceMu.RLock()
+	defer instanceMu.RUnlock()
+	if len(parseErrors) == 0 {
+		return nil
+	}
+	return append([]error(nil), parseErrors...)
+}
+
+// ValidationError is returned by the loaders in Strict mode when validation
+// finds problems. It holds every problem found, not only the first.
+//
+// Example:
+//
+//	var verr *ahatconfig.ValidationError
+//	if errors.As(err, &verr) {
+//	    for _, path := range verr.MissingRequired() {
+//	        promptFor(path)
+//	    }
+//	}
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].Error()
+	}
+	msgs := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		msgs[i] = problem.Error()
+	}
+	return fmt.Sprintf("%d validation problems: %s", len(e.Problems), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual problems, for errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// MissingRequired returns the dotted paths of the required fields that are
+// missing or empty (e.g. "server.host" or "users[1].role"), in the order they
+// were found.
+func (e *ValidationError) MissingRequired() []string {
+	var paths []string
+	for _, problem := range e.Problems {
+		var missing *requiredFieldError
+		if errors.As(problem, &missing) {
+			paths = append(paths, missing.path)
+		}
+	}
+	return paths
+}
+
+// requiredFieldError reports a required field that is missing or empty.
+// name is the env tag (or field name) and path the dotted field path.
+type requiredFieldError struct {
+	name string
+	path string
+}
+
+func (e *requiredFieldError) Error() string {
+	return fmt.Sprintf("required field '%s' is missing or empty at %s", e.name, e.path)
+}
+
+// FieldError is a validation problem of a field tagged errmsg, whose message
+// replaces the generic ones. The problems it stands for are available through
+// errors.As, e.g. for ValidationError.MissingRequired.
+//
+// Example:
+//
+//	var ferr *ahatconfig.FieldError
+//	if errors.As(err, &ferr) {
+//	    form.ShowError(ferr.Path, ferr.Message)
+//	}
+type FieldError struct {
+	Path     string  // Dotted path of the field, e.g. "database.url"
+	Message  string  // Value of the errmsg tag
+	Problems []error // Problems found for the field
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the problems found for the field.
+func (e *FieldError) Unwrap() []error {
+	return e.Problems
+}
+
+// withErrMsg replaces the problems found for the field at path with a single
+// FieldError when the field has an errmsg tag.
+func withErrMsg(errs []error, fieldInfo FieldInfo, path string) []error {
+	if fieldInfo.ErrMsg == "" || len(errs) == 0 {
+		return errs
+	}
+	return []error{&FieldError{Path: path, Message: fieldInfo.ErrMsg, Problems: errs}}
+}
+
+// checkBounds checks the min and max tags of a field: the value of numbers,
+// the length of strings, every element of numeric slices and arrays, and the
+// element count of other slices and maps. Numbers are checked even when they
+// are zero, so min:"1" rejects an unset port; empty strings, slices and maps
+// and nil pointers are not checked, use the required or minlen tag for them.
+// path is the dotted path of the field.
+func checkBounds(value reflect.Value, fieldInfo FieldInfo, path string) []error {
+	if fieldInfo.Min == "" && fieldInfo.Max == "" {
+		return nil
+	}
+
+	min, max, err := parseBounds(fieldInfo, path)
+	if err != nil {
+		return []error{err}
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case isNumber(value.Type()):
+		return checkNumberBounds(value, path, min, max)
+	case value.Kind() == reflect.String:
+		if value.Len() == 0 {
+			return nil
+		}
+		return checkCountBounds(float64(utf8.RuneCountInString(value.String())), path, "characters", min, max)
+	case isNumberList(value.Type()):
+		var errs []error
+		for i := 0; i < value.Len(); i++ {
+			errs = append(errs, checkNumberBounds(value.Index(i), indexPath(path, i), min, max)...)
+		}
+		return errs
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array || value.Kind() == reflect.Map:
+		if value.Len() == 0 {
+			return nil
+		}
+		return checkCountBounds(float64(value.Len()), path, "elements", min, max)
+	default:
+		return nil
+	}
+}
+
+// checkOneOf checks the oneof tag of a field: the value, or every element of
+// a slice or array, must equal one of the allowed values once parsed into the
+// field type, so oneof:"1m 5m" accepts a time.Duration of five minutes. Zero
+// values, such as an unset string or number, and nil pointers are not
+// checked; use the required tag for them. path is the dotted path of the
+// field.
+func checkOneOf(value reflect.Value, fieldInfo FieldInfo, path string) []error {
+	if len(fieldInfo.OneOf) == 0 {
+		return nil
+	}
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && !isByteSlice(value.Type()):
+		var errs []error
+		for i := 0; i < value.Len(); i++ {
+			errs = append(errs, checkOneOfValue(value.Index(i), fieldInfo.OneOf, indexPath(path, i))...)
+		}
+		return errs
+	case value.Kind() == reflect.Map:
+		return nil
+	case isZero(value):
+		return nil
+	default:
+		return checkOneOfValue(value, fieldInfo.OneOf, path)
+	}
+}
+
+// checkOneOfValue checks that value equals one of options. Options that do not
+// parse into the type of value are compared as text.
+func checkOneOfValue(value reflect.Value, options []string, path string) []error {
+	for _, option := range options {
+		allowed, err := parseEnvValue(option, value.Type())
+		if err != nil || allowed == nil || !reflect.TypeOf(allowed).ConvertibleTo(value.Type()) {
+			if fmt.Sprint(value.Interface()) == option {
+				return nil
+			}
+			continue
+		}
+		if reflect.DeepEqual(reflect.ValueOf(allowed).Convert(value.Type()).Interface(), value.Interface()) {
+			return nil
+		}
+	}
+	return []error{fmt.Errorf("field %s must be one of %s, got %v", path, strings.Join(options, ", "), value.Interface())}
+}
+
+// checkMinLen checks the minlen tag of a map, slice or array field. Unlike
+// min, it also applies to an empty value, so minlen:"1" requires at least
+// one entry.
+func checkMinLen(value reflect.Value, fieldInfo FieldInfo, path string) []error {
+	if fieldInfo.MinLen == 0 {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return []error{fmt.Errorf("minlen tag on field %s requires a map or slice field", path)}
+	}
+
+	if value.Len() < fieldInfo.MinLen {
+		return []error{fmt.Errorf("field %s must have at least %d entries, got %d", path, fieldInfo.MinLen, value.Len())}
+	}
+	return nil
+}
+
+// checkCountTags reports the first invalid minelems or minlen tag of the
+// struct type t or of its nested sections, so that a load fails before it
+// changes anything. path is the dotted path of t; seen stops recursive types.
+func checkCountTags(t reflect.Type, path string, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	for i, fieldInfo := range getCachedTypeInfo(t).Fields {
+		fieldPath := joinPath(path, fieldInfo.Key)
+		for _, tag := range []struct {
+			name string
+			n    int
+		}{{"minelems", fieldInfo.MinElems}, {"minlen", fieldInfo.MinLen}} {
+			if tag.n < 0 {
+				return fmt.Errorf("invalid %s tag '%s' on field %s", tag.name, t.Field(i).Tag.Get(tag.name), fieldPath)
+			}
+		}
+
+		elem := fieldInfo.Type
+		for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array || elem.Kind() == reflect.Map {
+			elem = elem.Elem()
+		}
+		if isNestedStruct(elem) {
+			if err := checkCountTags(elem, fieldPath, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isStructMapElem reports whether map values of type t are validated as
+// structs: structs other than time.Time, or pointers to them.
+func isStructMapElem(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return isNestedStruct(t)
+}
+
+// bound is a parsed min or max tag; set is false when the tag is empty.
+type bound struct {
+	value float64
+	tag   string
+	set   bool
+}
+
+// parseBounds parses the min and max tags of a field.
+func parseBounds(fieldInfo FieldInfo, path string) (bound, bound, error) {
+	var min, max bound
+	for _, b := range []struct {
+		name string
+		tag  string
+		dst  *bound
+	}{{"min", fieldInfo.Min, &min}, {"max", fieldInfo.Max, &max}} {
+		if b.tag == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(b.tag, 64)
+		if err != nil {
+			return min, max, fmt.Errorf("invalid %s tag '%s' on field %s", b.name, b.tag, path)
+		}
+		*b.dst = bound{value: value, tag: b.tag, set: true}
+	}
+	return min, max, nil
+}
+
+// checkNumberBounds checks a number against the bounds.
+func checkNumberBounds(value reflect.Value, path string, min, max bound) []error {
+	var n float64
+	switch value.Kind() {
+	case reflect.Float64, reflect.Float32:
+		n = value.Float()
+	case reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8:
+		n = float64(value.Uint())
+	default:
+		n = float64(value.Int())
+	}
+
+	if min.set && n < min.value {
+		return []error{fmt.Errorf("field %s must be at least %s, got %v", path, min.tag, value.Interface())}
+	}
+	if max.set && n > max.value {
+		return []error{fmt.Errorf("field %s must be at most %s, got %v", path, max.tag, value.Interface())}
+	}
+	return nil
+}
+
+// checkCountBounds checks a length or element count against the bounds.
+func checkCountBounds(n float64, path, unit string, min, max bound) []error {
+	if min.set && n < min.value {
+		return []error{fmt.Errorf("field %s must have at least %s %s, got %v", path, min.tag, unit, n)}
+	}
+	if max.set && n > max.value {
+		return []error{fmt.Errorf("field %s must have at most %s %s, got %v", path, max.tag, unit, n)}
+	}
+	return nil
+}
+
+// isNumber reports whether t is an integer or floating point type, other
+// than time.Duration.
+func isNumber(t reflect.Type) bool {
+	if t == durationType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8,
+		reflect.Uint, reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8,
+		reflect.Float64, reflect.Float32:
+		return true
+	}
+	return false
+}
+
+// isNumberList reports whether t is a slice or array of numbers whose bounds
+// apply to every element. Byte slices hold byte strings and are bounded by
+// their length instead.
+func isNumberList(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+	return isNumber(t.Elem()) && t.Elem().Kind() != reflect.Uint8
+}
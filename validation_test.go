@@ -0,0 +1,574 @@
+package ahatconfig
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestValidationWarnMode는 Warn 모드에서 필수 필드 누락이 경고로 기록되고 로드는 성공하는지 테스트합니다
+func TestValidationWarnMode(t *testing.T) {
+	t.Run("missing required field becomes a warning", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "WARNAPP"
+		SetValidationMode(Warn)
+		// WARNAPP_SERVER_HOST와 WARNAPP_DATABASE_USER 누락
+		t.Setenv("WARNAPP_SERVER_PORT", "9090")
+
+		if err := LoadConfig[TestConfig](); err != nil {
+			t.Fatalf("expected LoadConfig to succeed in Warn mode, got %v", err)
+		}
+
+		cfg := GetConfig[TestConfig]()
+		if cfg.Server.Port != 9090 {
+			t.Errorf("expected server port to be 9090, got %d", cfg.Server.Port)
+		}
+
+		got := Warnings()
+		if len(got) != 2 {
+			t.Fatalf("expected 2 warnings, got %d: %v", len(got), got)
+		}
+		if !strings.Contains(got[0].Error(), "required field 'HOST' is missing or empty") {
+			t.Errorf("expected a warning for HOST, got '%v'", got[0])
+		}
+		if !strings.Contains(got[1].Error(), "required field 'USER' is missing or empty") {
+			t.Errorf("expected a warning for USER, got '%v'", got[1])
+		}
+	})
+
+	t.Run("strict mode is the default", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "WARNAPP"
+		t.Setenv("WARNAPP_SERVER_PORT", "9090")
+
+		if err := LoadConfig[TestConfig](); err == nil {
+			t.Fatal("expected an error for missing required field, but got nil")
+		}
+		if got := Warnings(); got != nil {
+			t.Errorf("expected no warnings in strict mode, got %v", got)
+		}
+	})
+
+	t.Run("warnings are cleared by a clean load", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "WARNAPP"
+		SetValidationMode(Warn)
+
+		if err := LoadConfig[TestConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if len(Warnings()) == 0 {
+			t.Fatal("expected warnings after the first load")
+		}
+
+		t.Setenv("WARNAPP_SERVER_HOST", "localhost")
+		t.Setenv("WARNAPP_DATABASE_USER", "admin")
+		if err := LoadConfig[TestConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if got := Warnings(); got != nil {
+			t.Errorf("expected warnings to be cleared, got %v", got)
+		}
+	})
+}
+
+// TestSkipAndCountParseErrors는 SkipAndCount 모드에서 파싱 실패 필드를 건너뛰고 오류를 기록하는지 테스트합니다
+func TestSkipAndCountParseErrors(t *testing.T) {
+	type ParseErrorConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT" default:"8080"`
+		} `toml:"server" env:"SERVER"`
+		Timeout int `toml:"timeout" env:"TIMEOUT"`
+	}
+
+	t.Run("skip and count", func(t *testing.T) {
+		resetGlobalConfig()
+		SetOnParseError(SkipAndCount)
+		AppName = "PARSEERR"
+		t.Setenv("PARSEERR_SERVER_PORT", "not-a-number")
+		t.Setenv("PARSEERR_SERVER_HOST", "localhost")
+		t.Setenv("PARSEERR_TIMEOUT", "30")
+
+		if err := LoadConfig[ParseErrorConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+
+		cfg := GetConfig[ParseErrorConfig]()
+		if cfg.Server.Host != "localhost" || cfg.Timeout != 30 {
+			t.Errorf("expected valid fields to load, got %+v", cfg)
+		}
+		if cfg.Server.Port != 0 {
+			t.Errorf("expected unparseable port to keep its previous value, got %d", cfg.Server.Port)
+		}
+
+		errs := ParseErrors()
+		if len(errs) != 1 {
+			t.Fatalf("expected 1 parse error, got %v", errs)
+		}
+		if !strings.Contains(errs[0].Error(), "field server.port") {
+			t.Errorf("expected parse error to name the field path, got %v", errs[0])
+		}
+	})
+
+	t.Run("fail fast by default", func(t *testing.T) {
+		resetGlobalConfig()
+		AppName = "PARSEERRFAST"
+		t.Setenv("PARSEERRFAST_SERVER_PORT", "not-a-number")
+
+		if err := loadConfigEnv(new(ParseErrorConfig)); err == nil {
+			t.Error("expected a parse error in FailFast mode, but got nil")
+		}
+		if err := LoadConfig[ParseErrorConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+		if errs := ParseErrors(); errs != nil {
+			t.Errorf("expected no recorded parse errors in FailFast mode, got %v", errs)
+		}
+	})
+}
+
+// TestValidationErrorMissingRequired는 Strict 모드 에러가 누락된 모든 필수 필드 경로를 제공하는지 테스트합니다
+func TestValidationErrorMissingRequired(t *testing.T) {
+	type MissingConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT" required:"true"`
+		} `toml:"server" env:"SERVER"`
+		Users []struct {
+			Name string `toml:"name" env:"NAME" required:"true"`
+			Role string `toml:"role" env:"ROLE" required:"true"`
+		} `toml:"users" env:"USERS"`
+	}
+
+	resetGlobalConfig()
+	_, cleanup := createTestTomlFile(t, "missingapp", `[server]
+port = 8080
+
+[[users]]
+name = "alice"
+role = "admin"
+
+[[users]]
+`)
+	defer cleanup()
+	AppName = "missingapp"
+
+	err := LoadConfig[MissingConfig]()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a *ValidationError, got %T: %v", err, err)
+	}
+
+	expected := []string{"server.host", "users[1].name", "users[1].role"}
+	if got := verr.MissingRequired(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected missing paths %v, got %v", expected, got)
+	}
+	if !strings.HasPrefix(err.Error(), "3 validation problems: ") {
+		t.Errorf("expected an aggregated error message, got '%v'", err)
+	}
+}
+
+// TestBoundsValidation은 min/max 태그가 숫자 슬라이스의 모든 요소에 적용되고 위반한 인덱스를 보고하는지 테스트합니다
+func TestBoundsValidation(t *testing.T) {
+	type BoundsConfig struct {
+		Ports   []int     `toml:"ports" env:"PORTS" min:"1" max:"65535"`
+		Weights []float64 `toml:"weights" env:"WEIGHTS" min:"0" max:"1"`
+		Name    string    `toml:"name" env:"NAME" min:"2" max:"8"`
+		Workers int       `toml:"workers" env:"WORKERS" min:"1"`
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			env: map[string]string{
+				"BOUNDSAPP_PORTS":   "80,443,65535",
+				"BOUNDSAPP_WEIGHTS": "0.5,1",
+				"BOUNDSAPP_NAME":    "api",
+				"BOUNDSAPP_WORKERS": "4",
+			},
+		},
+		{
+			name:        "slice element above max",
+			env:         map[string]string{"BOUNDSAPP_PORTS": "80,443,70000"},
+			expectedErr: "field ports[2] must be at most 65535, got 70000",
+		},
+		{
+			name:        "slice element below min",
+			env:         map[string]string{"BOUNDSAPP_WEIGHTS": "0.5,-0.1"},
+			expectedErr: "field weights[1] must be at least 0, got -0.1",
+		},
+		{
+			name:        "string too long",
+			env:         map[string]string{"BOUNDSAPP_NAME": "averylongname"},
+			expectedErr: "field name must have at most 8 characters",
+		},
+		{
+			name:        "number below min",
+			env:         map[string]string{"BOUNDSAPP_WORKERS": "-1"},
+			expectedErr: "field workers must be at least 1, got -1",
+		},
+		{
+			name:        "zero number below min",
+			env:         map[string]string{"BOUNDSAPP_WORKERS": "0"},
+			expectedErr: "field workers must be at least 1, got 0",
+		},
+		{
+			name:        "unset number below min",
+			env:         map[string]string{"BOUNDSAPP_NAME": "api"},
+			expectedErr: "field workers must be at least 1, got 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalConfig()
+			defer resetGlobalConfig()
+			AppName = "boundsapp"
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			err := LoadConfig[BoundsConfig]()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig failed: %v", err)
+				}
+				if cfg := GetConfig[BoundsConfig](); !reflect.DeepEqual(cfg.Ports, []int{80, 443, 65535}) {
+					t.Errorf("expected ports [80 443 65535], got %v", cfg.Ports)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestOneOfValidation은 oneof 태그가 로드 시 검사되고 값이 필드 타입으로 비교되는지 테스트합니다
+func TestOneOfValidation(t *testing.T) {
+	type OneOfConfig struct {
+		Level   string        `toml:"level" env:"LEVEL" oneof:"debug info warn"`
+		Timeout time.Duration `toml:"timeout" env:"TIMEOUT" oneof:"1m 5m" default:"1m"`
+		Ports   []int         `toml:"ports" env:"PORTS" oneof:"80 443"`
+		Count   int           `toml:"count" env:"COUNT" oneof:"1 2 3"`
+	}
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			env: map[string]string{
+				"ONEOFAPP_LEVEL":   "warn",
+				"ONEOFAPP_TIMEOUT": "300s",
+				"ONEOFAPP_PORTS":   "80,443",
+			},
+		},
+		{
+			name: "unset string and number",
+			env:  map[string]string{},
+		},
+		{
+			name:        "number not allowed",
+			env:         map[string]string{"ONEOFAPP_COUNT": "4"},
+			expectedErr: "field count must be one of 1, 2, 3, got 4",
+		},
+		{
+			name:        "string not allowed",
+			env:         map[string]string{"ONEOFAPP_LEVEL": "verbose"},
+			expectedErr: "field level must be one of debug, info, warn, got verbose",
+		},
+		{
+			name:        "duration not allowed",
+			env:         map[string]string{"ONEOFAPP_TIMEOUT": "2m"},
+			expectedErr: "field timeout must be one of 1m, 5m, got 2m0s",
+		},
+		{
+			name:        "slice element not allowed",
+			env:         map[string]string{"ONEOFAPP_PORTS": "80,8080"},
+			expectedErr: "field ports[1] must be one of 80, 443, got 8080",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalConfig()
+			defer resetGlobalConfig()
+			AppName = "oneofapp"
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			err := LoadConfig[OneOfConfig]()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestBoundsUnsigned는 min/max 태그가 부호 없는 정수에 적용되고 빈 문자열과 빈 슬라이스는 검사하지 않는지 테스트합니다
+func TestBoundsUnsigned(t *testing.T) {
+	type UnsignedConfig struct {
+		Replicas uint8    `toml:"replicas" min:"1" max:"10"`
+		Limit    uint64   `toml:"limit" max:"100"`
+		Shards   []uint   `toml:"shards" max:"4"`
+		Name     string   `toml:"name" min:"2"`
+		Tags     []string `toml:"tags" min:"1"`
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr string
+	}{
+		{name: "valid", content: "replicas = 3\nlimit = 100\nshards = [0, 4]\n"},
+		{name: "uint above max", content: "replicas = 20\n", expectedErr: "field replicas must be at most 10, got 20"},
+		{name: "zero uint below min", content: "replicas = 0\n", expectedErr: "field replicas must be at least 1, got 0"},
+		{name: "uint64 above max", content: "replicas = 1\nlimit = 101\n", expectedErr: "field limit must be at most 100, got 101"},
+		{name: "uint element above max", content: "replicas = 1\nshards = [1, 5]\n", expectedErr: "field shards[1] must be at most 4, got 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalConfig()
+			defer resetGlobalConfig()
+			_, cleanup := createTestTomlFile(t, "unsignedapp", tt.content)
+			defer cleanup()
+			AppName = "unsignedapp"
+
+			err := LoadConfig[UnsignedConfig]()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected empty name and tags to skip their bounds, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing '%s', got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// TestRequiredByDefault는 SetRequiredByDefault 설정 시 태그 없는 필드가 필수가 되고 optional 태그와 기본값이 예외가 되는지 테스트합니다
+func TestRequiredByDefault(t *testing.T) {
+	type StrictConfig struct {
+		Server struct {
+			Host  string `toml:"host" env:"HOST"`
+			Port  int    `toml:"port" env:"PORT" default:"8080"`
+			Debug bool   `toml:"debug" env:"DEBUG" default:"false"`
+		} `toml:"server" env:"SERVER"`
+		Proxy struct {
+			URL string `toml:"url" env:"URL"`
+		} `toml:"proxy" env:"PROXY" optional:"true"`
+		Comment string `toml:"comment" env:"COMMENT" optional:"true"`
+		APIKey  string `toml:"api_key" env:"API_KEY"`
+		cache   string
+	}
+
+	t.Run("unmarked field missing", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "strictapp"
+		SetRequiredByDefault(true)
+		t.Setenv("STRICTAPP_SERVER_HOST", "localhost")
+
+		err := LoadConfig[StrictConfig]()
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected a *ValidationError, got %T: %v", err, err)
+		}
+		if got := verr.MissingRequired(); !reflect.DeepEqual(got, []string{"api_key"}) {
+			t.Errorf("expected only api_key to be missing, got %v", got)
+		}
+	})
+
+	t.Run("optional and defaulted fields pass", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "strictapp"
+		SetRequiredByDefault(true)
+		t.Setenv("STRICTAPP_SERVER_HOST", "localhost")
+		t.Setenv("STRICTAPP_API_KEY", "key")
+
+		if err := LoadConfig[StrictConfig](); err != nil {
+			t.Fatalf("LoadConfig failed: %v", err)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "strictapp"
+
+		if err := LoadConfig[StrictConfig](); err != nil {
+			t.Fatalf("expected unmarked fields to be optional by default, got %v", err)
+		}
+	})
+}
+
+// TestMapValidation는 맵 필드의 required, minlen 태그와 맵 값 구조체의 필수 필드 검사를 테스트합니다
+func TestMapValidation(t *testing.T) {
+	type MapBackend struct {
+		URL    string `toml:"url" env:"URL" required:"true"`
+		Weight int    `toml:"weight" env:"WEIGHT"`
+	}
+	type MapValidationConfig struct {
+		Backends map[string]MapBackend  `toml:"backends" env:"BACKENDS" required:"true"`
+		Replicas map[string]*MapBackend `toml:"replicas" env:"REPLICAS" minlen:"2"`
+	}
+
+	tests := []struct {
+		name        string
+		toml        string
+		expectedErr []string
+	}{
+		{
+			name: "populated",
+			toml: `
+[backends.primary]
+url = "http://primary"
+
+[replicas.a]
+url = "http://a"
+
+[replicas.b]
+url = "http://b"
+`,
+		},
+		{
+			name:        "empty required map",
+			toml:        "",
+			expectedErr: []string{"required field 'BACKENDS' is missing or empty at backends", "field replicas must have at least 2 entries, got 0"},
+		},
+		{
+			name: "required field in map value",
+			toml: `
+[backends.primary]
+weight = 1
+
+[replicas.a]
+url = "http://a"
+
+[replicas.b]
+weight = 2
+`,
+			expectedErr: []string{"at backends.primary.url", "at replicas.b.url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetGlobalConfig()
+			defer resetGlobalConfig()
+			appName := "mapvalidapp"
+			_, cleanup := createTestTomlFile(t, appName, tt.toml)
+			defer cleanup()
+
+			AppName = appName
+			err := LoadConfig[MapValidationConfig]()
+			if len(tt.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("LoadConfig failed: %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			for _, expected := range tt.expectedErr {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected error containing '%s', got: %v", expected, err)
+				}
+			}
+		})
+	}
+
+	t.Run("malformed minlen tag", func(t *testing.T) {
+		type BadMinLenConfig struct {
+			Backends []struct {
+				Labels map[string]string `toml:"labels" minlen:"-1"`
+			} `toml:"backends"`
+		}
+
+		resetGlobalConfig()
+		defer resetGlobalConfig()
+		AppName = "minlenbad"
+		err := LoadConfig[BadMinLenConfig]()
+		if err == nil || !strings.Contains(err.Error(), "invalid minlen tag '-1' on field backends.labels") {
+			t.Errorf("expected the malformed tag to fail the load before decoding, got %v", err)
+		}
+	})
+
+	t.Run("invalid minlen", func(t *testing.T) {
+		var s string
+		errs := checkMinLen(reflect.ValueOf(s), FieldInfo{MinLen: 1}, "name")
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "requires a map or slice field") {
+			t.Errorf("expected an error for minlen on a string, got %v", errs)
+		}
+	})
+}
+
+// TestErrMsgTag는 errmsg 태그가 있는 필드의 검증 실패 시 일반 메시지 대신 지정한 메시지가 쓰이는지 테스트합니다
+func TestErrMsgTag(t *testing.T) {
+	type ErrMsgConfig struct {
+		Database struct {
+			URL string `toml:"url" env:"URL" required:"true" errmsg:"Please provide a valid database URL"`
+		} `toml:"database" env:"DATABASE"`
+		Workers int    `toml:"workers" env:"WORKERS" min:"1" errmsg:"Workers must be a positive number"`
+		Name    string `toml:"name" env:"NAME" required:"true"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	AppName = "errmsgapp"
+	t.Setenv("ERRMSGAPP_WORKERS", "-2")
+
+	err := LoadConfig[ErrMsgConfig]()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+
+	for _, expected := range []string{
+		"Please provide a valid database URL",
+		"Workers must be a positive number",
+		"required field 'NAME' is missing or empty at name",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected error containing '%s', got: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "at database.url") || strings.Contains(err.Error(), "must be at least 1") {
+		t.Errorf("expected the custom messages to replace the generic ones, got: %v", err)
+	}
+
+	var ferr *FieldError
+	if !errors.As(verr.Problems[0], &ferr) || ferr.Path != "database.url" {
+		t.Errorf("expected a FieldError for database.url, got %v", verr.Problems[0])
+	}
+	if missing := verr.MissingRequired(); !reflect.DeepEqual(missing, []string{"database.url", "name"}) {
+		t.Errorf("expected missing required fields [database.url name], got %v", missing)
+	}
+}
@@ -0,0 +1,165 @@
+package ahatconfig
+
+import (
+	"log"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchEnv polls for configuration changes every interval, for platforms
+// that rewrite environment variables without emitting filesystem events.
+// Each poll rebuilds the configuration from the sources of the current one
+// (the config file, embedded file or URL it was loaded from, then
+// environment variables) and, when the result differs from the current
+// configuration, stores it and calls onChange with it. A poll that fails to
+// load or validate is logged and the current configuration is kept.
+// The returned stop function stops polling; Shutdown stops it too. Called
+// from another goroutine, stop waits for a running onChange to return, so no
+// callback runs after it. stop may also be called from onChange, e.g. to
+// stop after the first change: it then returns right away and polling ends
+// once onChange returns.
+//
+// Example:
+//
+//	stop := ahatconfig.WatchEnv[MyConfig](30*time.Second, func(cfg *MyConfig) {
+//	    log.Printf("config changed, new port: %d", cfg.Server.Port)
+//	})
+//	defer stop()
+func WatchEnv[T any](interval time.Duration, onChange func(*T)) (stop func()) {
+	// onChange runs on the polling goroutine, so a stop from onChange, the
+	// only caller on that goroutine, cannot wait for it to exit. Stops from
+	// other goroutines wait, so no callback runs after they return.
+	var poller atomic.Uint64
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	var stopOnce sync.Once
+
+	stopTask := startBackground(func(done <-chan struct{}) {
+		poller.Store(goroutineID())
+		close(started)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-stopped:
+				return
+			case <-ticker.C:
+				if cfg, changed := pollConfig[T](); changed && onChange != nil {
+					onChange(cfg)
+				}
+			}
+		}
+	})
+	<-started
+
+	return func() {
+		if goroutineID() == poller.Load() {
+			stopOnce.Do(func() { close(stopped) })
+			return
+		}
+		stopTask()
+	}
+}
+
+// goroutineID returns the id of the calling goroutine, read from the header
+// of its stack trace ("goroutine 7 [running]:").
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := strings.Fields(strings.TrimPrefix(string(buf[:n]), "goroutine "))
+	if len(fields) == 0 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(fields[0], 10, 64)
+	return id
+}
+
+// reloadErrorHook is called with the error of every failed reload.
+var reloadErrorHook func(error)
+
+// SetReloadErrorHook sets a function called whenever a reload by WatchEnv,
+// ReloadConfig or a refresh (SetRefreshInterval) fails to load, parse or
+// validate the configuration. The current configuration is kept in that
+// case, so the hook is the place to alert on it. Pass nil to remove the hook.
+//
+// Example:
+//
+//	ahatconfig.SetReloadErrorHook(func(err error) {
+//	    reloadFailures.Inc()
+//	    log.Printf("config reload rejected: %v", err)
+//	})
+func SetReloadErrorHook(hook func(error)) {
+	reloadErrorHook = hook
+}
+
+// ReloadConfig rebuilds the configuration from the sources it was loaded
+// from, e.g. the file given to InitConfigWithExactFile, and stores it when
+// its effective value differs from the current one, returning the fields that
+// changed. An edit that does not change any value, such as a new comment or
+// reformatted whitespace in the TOML file, returns no changes and keeps the
+// current configuration. A reload that fails to load or validate returns the
+// error and also keeps the current configuration.
+//
+// Example:
+//
+//	changes, err := ahatconfig.ReloadConfig[MyConfig]()
+//	if err != nil {
+//	    log.Printf("reload failed: %v", err)
+//	}
+//	for _, change := range changes {
+//	    log.Printf("%s: %v -> %v", change.Path, change.Old, change.New)
+//	}
+func ReloadConfig[T any]() ([]FieldChange, error) {
+	if _, err := instanceAs[T](currentInstance()); err != nil {
+		return nil, err
+	}
+	_, changes, err := reloadConfig[T]()
+	return changes, err
+}
+
+// pollConfig rebuilds the configuration and stores it when it differs from
+// the current one, reporting whether it changed.
+func pollConfig[T any]() (*T, bool) {
+	next, _, err := reloadConfig[T]()
+	if err != nil {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		return nil, false
+	}
+	return next, next != nil
+}
+
+// reloadConfig rebuilds the configuration from the sources of the current
+// one, such as the file of InitConfigWithExactFile or the URL of
+// InitConfigFromURL, and stores it when it differs from the current one,
+// returning the new configuration and its changes from the current one. The
+// configuration is nil when nothing changed. A failed build is passed to the
+// reload error hook.
+func reloadConfig[T any]() (*T, []FieldChange, error) {
+	prefix, loadBase := currentSource[T]()
+	next, report, err := buildPrefixedConfig(prefix, "", loadBase)
+	if err != nil {
+		if reloadErrorHook != nil {
+			reloadErrorHook(err)
+		}
+		return nil, nil, err
+	}
+
+	current, currentErr := instanceAs[T](currentInstance())
+	if currentErr == nil && reflect.DeepEqual(current, next) {
+		return nil, nil, nil
+	}
+
+	storeInstance(next, report, configSource{prefix: prefix, loadBase: loadBase})
+	if currentErr != nil {
+		return next, nil, nil
+	}
+	return next, Diff(current, next), nil
+}
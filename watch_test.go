@@ -0,0 +1,342 @@
+package ahatconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// TestWatchEnv는 폴링 사이에 환경변수가 바뀌면 콜백이 한 번만 호출되는지 테스트합니다
+func TestWatchEnv(t *testing.T) {
+	type WatchConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	defer Shutdown()
+	AppName = "WATCHENV"
+	t.Setenv("WATCHENV_SERVER_HOST", "localhost")
+	t.Setenv("WATCHENV_SERVER_PORT", "8080")
+
+	if err := LoadConfig[WatchConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	changes := make(chan *WatchConfig, 10)
+	stop := WatchEnv[WatchConfig](5*time.Millisecond, func(cfg *WatchConfig) {
+		changes <- cfg
+	})
+
+	// 변경이 없으면 콜백이 호출되지 않아야 한다
+	select {
+	case cfg := <-changes:
+		t.Fatalf("unexpected callback without changes: %+v", cfg)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	t.Setenv("WATCHENV_SERVER_PORT", "9090")
+
+	select {
+	case cfg := <-changes:
+		if cfg.Server.Port != 9090 {
+			t.Errorf("expected callback with port 9090, got %d", cfg.Server.Port)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("callback was not called after the env var changed")
+	}
+
+	// 같은 변경으로 다시 호출되지 않아야 한다
+	select {
+	case cfg := <-changes:
+		t.Errorf("callback fired more than once: %+v", cfg)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	stop()
+	if got := GetConfig[WatchConfig]().Server.Port; got != 9090 {
+		t.Errorf("expected stored config to have port 9090, got %d", got)
+	}
+}
+
+// TestReloadConfigNoOpEdit는 값이 바뀌지 않는 파일 수정(공백, 주석)은 콜백을 호출하지 않고 실제 변경은 Diff로 보고되는지 테스트합니다
+func TestReloadConfigNoOpEdit(t *testing.T) {
+	type ReloadConfigStruct struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	defer Shutdown()
+	path, cleanup := createTestTomlFile(t, "reloadapp", "[server]\nhost = \"localhost\"\nport = 8080\n")
+	defer cleanup()
+	AppName = "reloadapp"
+
+	if _, err := ReloadConfig[ReloadConfigStruct](); err == nil {
+		t.Error("expected an error before the config is loaded")
+	}
+	if err := LoadConfig[ReloadConfigStruct](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	changes := make(chan *ReloadConfigStruct, 10)
+	stop := WatchEnv[ReloadConfigStruct](5*time.Millisecond, func(cfg *ReloadConfigStruct) {
+		changes <- cfg
+	})
+	defer stop()
+
+	// 공백과 주석만 바뀐 파일은 콜백을 호출하지 않아야 한다
+	if err := os.WriteFile(path, []byte("# edited\n[server]\n  host   = \"localhost\"\n\n  port = 8080\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+	select {
+	case cfg := <-changes:
+		t.Fatalf("unexpected callback after a no-op edit: %+v", cfg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	stop()
+
+	if got, err := ReloadConfig[ReloadConfigStruct](); err != nil || got != nil {
+		t.Errorf("expected no changes after a no-op edit, got %+v (%v)", got, err)
+	}
+
+	if err := os.WriteFile(path, []byte("[server]\nhost = \"localhost\"\nport = 9090\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+	got, err := ReloadConfig[ReloadConfigStruct]()
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if want := []FieldChange{{Path: "server.port", Old: 8080, New: 9090}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if port := GetConfig[ReloadConfigStruct]().Server.Port; port != 9090 {
+		t.Errorf("expected the reloaded port 9090 to be stored, got %d", port)
+	}
+}
+
+// TestReloadErrorHook는 다시 로드한 설정이 검증에 실패하면 훅이 오류를 받고 이전 설정이 유지되는지 테스트합니다
+func TestReloadErrorHook(t *testing.T) {
+	type HookConfig struct {
+		Server struct {
+			Host string `toml:"host" env:"HOST" required:"true"`
+			Port int    `toml:"port" env:"PORT"`
+		} `toml:"server" env:"SERVER"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+	path, cleanup := createTestTomlFile(t, "hookapp", "[server]\nhost = \"localhost\"\nport = 8080\n")
+	defer cleanup()
+	AppName = "hookapp"
+	if err := LoadConfig[HookConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	hookErrs := make(chan error, 10)
+	SetReloadErrorHook(func(err error) {
+		hookErrs <- err
+	})
+
+	// 필수 필드가 빠진 파일로 다시 로드
+	if err := os.WriteFile(path, []byte("[server]\nport = 9090\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+	if _, err := ReloadConfig[HookConfig](); err == nil {
+		t.Fatal("expected ReloadConfig to fail without server.host")
+	}
+	select {
+	case err := <-hookErrs:
+		if !strings.Contains(err.Error(), "server.host") {
+			t.Errorf("expected the hook to receive the missing field error, got %v", err)
+		}
+	default:
+		t.Fatal("reload error hook was not called")
+	}
+	if cfg := GetConfig[HookConfig](); cfg.Server.Host != "localhost" || cfg.Server.Port != 8080 {
+		t.Errorf("expected the previous config to be kept, got %+v", cfg.Server)
+	}
+
+	// 폴링 감시도 같은 훅을 호출한다
+	stop := WatchEnv[HookConfig](5*time.Millisecond, nil)
+	defer stop()
+	select {
+	case err := <-hookErrs:
+		if !strings.Contains(err.Error(), "server.host") {
+			t.Errorf("expected the watcher to report the missing field, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reload error hook was not called by WatchEnv")
+	}
+	stop()
+	if got := GetConfig[HookConfig]().Server.Host; got != "localhost" {
+		t.Errorf("expected the previous host to be kept, got %q", got)
+	}
+}
+
+// TestReloadKeepsSources는 ReloadConfig와 WatchEnv가 설정을 처음 로드한 소스(임베디드 파일, 지정한 파일)에서 다시 로드하는지 테스트합니다
+func TestReloadKeepsSources(t *testing.T) {
+	type SourceConfig struct {
+		Host string `toml:"host" env:"HOST"`
+		Port int    `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+
+	// LoadConfig가 찾는 파일은 다시 로드할 때 읽히지 않아야 한다
+	_, cleanup := createTestTomlFile(t, "sourceapp", "host = \"search\"\nport = 1\n")
+	defer cleanup()
+
+	fsys := fstest.MapFS{"embedded.toml": {Data: []byte("host = \"embedded\"\nport = 8080\n")}}
+	if err := InitConfigFromFS[SourceConfig]("sourceapp", fsys, "embedded.toml"); err != nil {
+		t.Fatalf("InitConfigFromFS failed: %v", err)
+	}
+	fsys["embedded.toml"] = &fstest.MapFile{Data: []byte("host = \"embedded\"\nport = 9090\n")}
+	changes, err := ReloadConfig[SourceConfig]()
+	if err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if want := []FieldChange{{Path: "port", Old: 8080, New: 9090}}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v from the embedded file, got %+v", want, changes)
+	}
+
+	exact := filepath.Join(t.TempDir(), "exact.toml")
+	if err := os.WriteFile(exact, []byte("host = \"exact\"\nport = 7070\n"), 0644); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	if err := InitConfigWithExactFile[SourceConfig]("sourceapp", exact); err != nil {
+		t.Fatalf("InitConfigWithExactFile failed: %v", err)
+	}
+	updated := make(chan *SourceConfig, 10)
+	stop := WatchEnv[SourceConfig](5*time.Millisecond, func(cfg *SourceConfig) {
+		updated <- cfg
+	})
+	defer stop()
+	if err := os.WriteFile(exact, []byte("host = \"exact\"\nport = 7171\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite config file: %v", err)
+	}
+	select {
+	case cfg := <-updated:
+		if cfg.Host != "exact" || cfg.Port != 7171 {
+			t.Errorf("expected the watcher to reload the exact file, got %+v", cfg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watcher did not pick up the change of the exact file")
+	}
+}
+
+// TestWatchEnvStopInCallback는 콜백 안에서 stop을 호출해도 교착 없이 감시가 멈추는지 테스트합니다
+func TestWatchEnvStopInCallback(t *testing.T) {
+	type StopConfig struct {
+		Port int `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+	AppName = "stopinside"
+	t.Setenv("STOPINSIDE_PORT", "8080")
+	if err := LoadConfig[StopConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	calls := make(chan int, 10)
+	var stop func()
+	stopped := make(chan struct{})
+	stop = WatchEnv[StopConfig](5*time.Millisecond, func(cfg *StopConfig) {
+		calls <- cfg.Port
+		stop()
+		close(stopped)
+	})
+
+	t.Setenv("STOPINSIDE_PORT", "9090")
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("stop called from the callback did not return")
+	}
+
+	// 멈춘 뒤에는 더 이상 콜백이 호출되지 않는다
+	t.Setenv("STOPINSIDE_PORT", "7070")
+	time.Sleep(30 * time.Millisecond)
+	stop()
+	if len(calls) != 1 {
+		t.Errorf("expected a single callback, got %d", len(calls))
+	}
+	backgroundMu.Lock()
+	running := len(backgroundTasks)
+	backgroundMu.Unlock()
+	if running != 0 {
+		t.Errorf("expected the watcher to leave the running tasks, got %d", running)
+	}
+}
+
+// TestWatchEnvConcurrentStop는 콜백이 실행되는 동안 다른 고루틴에서 호출한 stop이 콜백이 끝날 때까지 기다리는지 테스트합니다
+func TestWatchEnvConcurrentStop(t *testing.T) {
+	type ConcurrentStopConfig struct {
+		Port int `toml:"port" env:"PORT"`
+	}
+
+	resetGlobalConfig()
+	defer resetGlobalConfig()
+	defer Shutdown()
+	AppName = "concurrentstop"
+	t.Setenv("CONCURRENTSTOP_PORT", "8080")
+	if err := LoadConfig[ConcurrentStopConfig](); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	// 실패해도 콜백을 풀어 Shutdown이 끝나도록 한다
+	defer releaseOnce.Do(func() { close(release) })
+	var running atomic.Bool
+	stop := WatchEnv[ConcurrentStopConfig](5*time.Millisecond, func(cfg *ConcurrentStopConfig) {
+		running.Store(true)
+		entered <- struct{}{}
+		<-release
+		running.Store(false)
+	})
+
+	t.Setenv("CONCURRENTSTOP_PORT", "9090")
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("callback was not called")
+	}
+
+	returned := make(chan struct{})
+	go func() {
+		stop()
+		close(returned)
+	}()
+	select {
+	case <-returned:
+		t.Fatal("stop returned while the callback was still running")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	releaseOnce.Do(func() { close(release) })
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("stop did not return after the callback finished")
+	}
+	if running.Load() {
+		t.Error("expected no callback to run after stop returned")
+	}
+}